@@ -0,0 +1,290 @@
+// Package authorization restricts handlers to the users holding a role: the
+// owners of the bot, the administrators or the creator of a chat, or the
+// administrators granted some permissions.
+//
+// An Authorizer looks up the administrators of a chat with
+// getChatAdministrators and caches them for a while. Registered on a
+// handlers.Registry, it drops the cache of a chat when a chat member update
+// reports a change among its administrators. Roles are checked by the
+// Require middleware, or by matchers for the handler registration methods
+// that take one. Administrators who post anonymously, on behalf of the chat,
+// are recognized from the sender chat of their messages.
+package authorization
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/botapi"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/handlers"
+	"github.com/tgbotkit/runtime/logger"
+	"golang.org/x/sync/singleflight"
+)
+
+// Admin is an administrator of a chat.
+type Admin struct {
+	UserID  int64
+	Creator bool
+	// Permissions holds the can_* rights of the administrator, such as
+	// can_restrict_members, by name. It is empty for the creator, who holds
+	// them all.
+	Permissions map[string]bool
+}
+
+// Authorizer checks the roles of users.
+type Authorizer struct {
+	opts Options
+	log  logger.Logger
+
+	// mu guards admins, the cached administrators of each chat, and
+	// generations, how many times the cache of each chat was invalidated.
+	mu          sync.Mutex
+	admins      map[int64]adminsEntry
+	generations map[int64]uint64
+	// lookups coalesces the concurrent lookups of a chat's administrators.
+	lookups singleflight.Group
+}
+
+type adminsEntry struct {
+	admins    map[int64]Admin
+	expiresAt time.Time
+}
+
+// New creates a new Authorizer with the given options.
+func New(opts Options) (*Authorizer, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid authorization options: %w", err)
+	}
+
+	if opts.logger == nil {
+		opts.logger = logger.NewNop()
+	}
+
+	return &Authorizer{
+		opts:        opts,
+		log:         opts.logger,
+		admins:      make(map[int64]adminsEntry),
+		generations: make(map[int64]uint64),
+	}, nil
+}
+
+// Register makes the authorizer drop the cached administrators of a chat
+// when registry receives a chat member update about one of them, or about
+// the bot. Telegram only sends the updates about other members to bots that
+// administer the chat and ask for chat_member updates. It returns a function
+// that unregisters the handlers.
+func (a *Authorizer) Register(registry *handlers.Registry) eventemitter.UnsubscribeFunc {
+	unsubscribe := []eventemitter.UnsubscribeFunc{
+		registry.OnChatMember(a.handleChatMember),
+		registry.OnMyChatMember(a.handleChatMember),
+	}
+
+	return func() {
+		for _, fn := range unsubscribe {
+			fn()
+		}
+	}
+}
+
+// Check reports whether the subject of the event carried by payload holds
+// role. Events without a user do not hold any role.
+func (a *Authorizer) Check(ctx context.Context, role Role, payload any) (bool, error) {
+	subject, ok := SubjectOf(payload)
+	if !ok {
+		return false, nil
+	}
+
+	return role(ctx, subject)
+}
+
+// Admins returns the administrators of chatID by user ID, from the cache
+// when it holds them. Private chats have none.
+func (a *Authorizer) Admins(ctx context.Context, chatID int64) (map[int64]Admin, error) {
+	if chatID >= 0 {
+		return nil, nil
+	}
+
+	a.mu.Lock()
+	entry, ok := a.admins[chatID]
+	a.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.admins, nil
+	}
+
+	// Concurrent misses for a chat share one lookup, which outlives the
+	// caller that started it so that the others still get its result.
+	results := a.lookups.DoChan(lookupKey(chatID), func() (any, error) {
+		return a.lookupAdmins(context.WithoutCancel(ctx), chatID)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("get administrators of chat %d: %w", chatID, ctx.Err())
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+
+		admins, _ := result.Val.(map[int64]Admin)
+
+		return admins, nil
+	}
+}
+
+// Invalidate drops the cached administrators of chatID.
+func (a *Authorizer) Invalidate(chatID int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.admins, chatID)
+	a.generations[chatID]++
+	a.lookups.Forget(lookupKey(chatID))
+}
+
+func lookupKey(chatID int64) string {
+	return strconv.FormatInt(chatID, 10)
+}
+
+// lookupAdmins fetches the administrators of chatID and caches them,
+// dropping the expired entries of other chats. They are not cached when the
+// chat was invalidated meanwhile, since they may predate the change.
+func (a *Authorizer) lookupAdmins(ctx context.Context, chatID int64) (map[int64]Admin, error) {
+	a.mu.Lock()
+	generation := a.generations[chatID]
+	a.mu.Unlock()
+
+	admins, err := a.fetchAdmins(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.generations[chatID] != generation {
+		return admins, nil
+	}
+
+	maps.DeleteFunc(a.admins, func(_ int64, entry adminsEntry) bool {
+		return !now.Before(entry.expiresAt)
+	})
+
+	a.admins[chatID] = adminsEntry{admins: admins, expiresAt: now.Add(a.opts.adminTTL)}
+
+	return admins, nil
+}
+
+// admin returns the subject as an administrator of their chat, and whether
+// they are one.
+func (a *Authorizer) admin(ctx context.Context, subject Subject) (Admin, bool, error) {
+	if subject.ChatID == 0 {
+		return Admin{}, false, nil
+	}
+
+	admins, err := a.Admins(ctx, subject.ChatID)
+	if err != nil {
+		return Admin{}, false, err
+	}
+
+	admin, ok := admins[subject.UserID]
+
+	return admin, ok, nil
+}
+
+func (a *Authorizer) handleChatMember(_ context.Context, event *events.ChatMemberEvent) error {
+	update := event.ChatMember
+	if update == nil {
+		return nil
+	}
+
+	if isAdminStatus(update.OldChatMember) || isAdminStatus(update.NewChatMember) {
+		a.Invalidate(update.Chat.Id)
+	}
+
+	return nil
+}
+
+func (a *Authorizer) fetchAdmins(ctx context.Context, chatID int64) (map[int64]Admin, error) {
+	resp, err := a.opts.client.GetChatAdministratorsWithResponse(ctx, client.GetChatAdministratorsJSONRequestBody{
+		ChatId: chatID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get administrators of chat %d: %w", chatID, err)
+	}
+
+	if resp.JSON200 == nil {
+		if apiErr := botapi.FromResponse(resp.StatusCode(), resp.Body); apiErr != nil {
+			return nil, fmt.Errorf("get administrators of chat %d: %w", chatID, apiErr)
+		}
+	}
+
+	if resp.JSON200 == nil || !bool(resp.JSON200.Ok) {
+		return nil, fmt.Errorf("get administrators of chat %d: unexpected response: %s", chatID, resp.Status())
+	}
+
+	admins := make(map[int64]Admin, len(resp.JSON200.Result))
+
+	for _, member := range resp.JSON200.Result {
+		if admin, ok := decodeAdmin(member); ok {
+			admins[admin.UserID] = admin
+		}
+	}
+
+	return admins, nil
+}
+
+// chatMember is the part of a ChatMember the authorizer reads.
+type chatMember struct {
+	Status string `json:"status"`
+	User   struct {
+		ID int64 `json:"id"`
+	} `json:"user"`
+}
+
+func decodeAdmin(member client.ChatMember) (Admin, bool) {
+	if !isAdminStatus(member) {
+		return Admin{}, false
+	}
+
+	data, err := json.Marshal(member)
+	if err != nil {
+		return Admin{}, false
+	}
+
+	var decoded chatMember
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return Admin{}, false
+	}
+
+	admin := Admin{UserID: decoded.User.ID, Creator: decoded.Status == "creator"}
+
+	if !admin.Creator {
+		admin.Permissions = make(map[string]bool)
+
+		for name, value := range member {
+			if granted, ok := value.(bool); ok && strings.HasPrefix(name, "can_") {
+				admin.Permissions[name] = granted
+			}
+		}
+	}
+
+	return admin, true
+}
+
+func isAdminStatus(member client.ChatMember) bool {
+	status, _ := member["status"].(string)
+
+	return status == "creator" || status == "administrator"
+}
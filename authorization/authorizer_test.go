@@ -0,0 +1,288 @@
+package authorization_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/authorization"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/handlers"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+const groupID = -100
+
+type adminsClient struct {
+	client.ClientWithResponsesInterface
+	admins []client.ChatMember
+	// release, when set, holds every lookup until it is closed.
+	release chan struct{}
+	started chan struct{}
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *adminsClient) GetChatAdministratorsWithResponse(
+	_ context.Context,
+	_ client.GetChatAdministratorsJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.GetChatAdministratorsResponse, error) {
+	c.mu.Lock()
+	c.calls++
+	c.mu.Unlock()
+
+	if c.release != nil {
+		select {
+		case c.started <- struct{}{}:
+		default:
+		}
+
+		<-c.release
+	}
+
+	return &client.GetChatAdministratorsResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"},
+		JSON200: &struct {
+			Ok     client.GetChatAdministrators200Ok `json:"ok"`
+			Result []client.ChatMember               `json:"result"`
+		}{Ok: true, Result: c.admins},
+	}, nil
+}
+
+func (c *adminsClient) callCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.calls
+}
+
+func newAdminsClient() *adminsClient {
+	return &adminsClient{admins: []client.ChatMember{
+		{"status": "creator", "user": map[string]any{"id": 1}},
+		{"status": "administrator", "user": map[string]any{"id": 2}, "can_restrict_members": true},
+		{"status": "administrator", "user": map[string]any{"id": 3}, "can_restrict_members": false},
+	}}
+}
+
+func newAuthorizer(t *testing.T, api client.ClientWithResponsesInterface, opts ...authorization.OptOptionsSetter) *authorization.Authorizer {
+	t.Helper()
+
+	authz, err := authorization.New(authorization.NewOptions(api, opts...))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	return authz
+}
+
+func commandFrom(userID int64) *events.CommandEvent {
+	return &events.CommandEvent{
+		Message: &client.Message{From: &client.User{Id: userID}, Chat: client.Chat{Id: groupID}},
+		Command: "ban",
+	}
+}
+
+func TestAuthorizerRoles(t *testing.T) {
+	api := newAdminsClient()
+	authz := newAuthorizer(t, api, authorization.WithOwners([]int64{9}))
+
+	anonymous := &events.MessageEvent{Message: &client.Message{
+		From:       &client.User{Id: 1087968824},
+		SenderChat: &client.Chat{Id: groupID},
+		Chat:       client.Chat{Id: groupID},
+	}}
+
+	tests := []struct {
+		name    string
+		role    authorization.Role
+		payload any
+		want    bool
+	}{
+		{"creator is admin", authz.ChatAdmin(), commandFrom(1), true},
+		{"administrator is admin", authz.ChatAdmin(), commandFrom(3), true},
+		{"member is not admin", authz.ChatAdmin(), commandFrom(4), false},
+		{"creator is creator", authz.ChatCreator(), commandFrom(1), true},
+		{"administrator is not creator", authz.ChatCreator(), commandFrom(2), false},
+		{"granted permission", authz.Can(authorization.PermissionRestrictMembers), commandFrom(2), true},
+		{"denied permission", authz.Can(authorization.PermissionRestrictMembers), commandFrom(3), false},
+		{"creator holds every permission", authz.Can(authorization.PermissionPromoteMembers), commandFrom(1), true},
+		{"owner", authz.Owner(), commandFrom(9), true},
+		{"owner or admin", authorization.AnyOf(authz.Owner(), authz.ChatAdmin()), commandFrom(2), true},
+		{"owner and admin", authorization.AllOf(authz.Owner(), authz.ChatAdmin()), commandFrom(2), false},
+		{"anonymous admin is admin", authz.ChatAdmin(), anonymous, true},
+		{"anonymous admin is untrusted", authz.Can(authorization.PermissionRestrictMembers), anonymous, false},
+		{"event without user", authz.ChatAdmin(), &events.PollEvent{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := authz.Check(context.Background(), tt.role, tt.payload)
+			if err != nil {
+				t.Fatalf("Check() unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Check() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if api.callCount() != 1 {
+		t.Errorf("getChatAdministrators called %d times, want 1", api.callCount())
+	}
+}
+
+func TestAuthorizerInvalidation(t *testing.T) {
+	api := newAdminsClient()
+	authz := newAuthorizer(t, api)
+
+	ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+	if err != nil {
+		t.Fatalf("NewSync() unexpected error: %v", err)
+	}
+
+	defer authz.Register(handlers.NewRegistry(ee, logger.NewNop()))()
+
+	isAdmin := func() bool {
+		t.Helper()
+
+		ok, err := authz.Check(context.Background(), authz.ChatAdmin(), commandFrom(4))
+		if err != nil {
+			t.Fatalf("Check() unexpected error: %v", err)
+		}
+
+		return ok
+	}
+
+	if isAdmin() {
+		t.Fatal("member is admin before promotion")
+	}
+
+	// Promoting the member is reported by a chat member update.
+	promoted := client.ChatMember{"status": "administrator", "user": map[string]any{"id": 4}}
+	api.admins = append(api.admins, promoted)
+
+	if isAdmin() {
+		t.Fatal("cached administrators reloaded without a chat member update")
+	}
+
+	ee.Emit(context.Background(), events.OnChatMember, &events.ChatMemberEvent{ChatMember: &client.ChatMemberUpdated{
+		Chat:          client.Chat{Id: groupID},
+		OldChatMember: client.ChatMember{"status": "member", "user": map[string]any{"id": 4}},
+		NewChatMember: promoted,
+	}})
+
+	if !isAdmin() {
+		t.Error("member is not admin after promotion")
+	}
+
+	if api.callCount() != 2 {
+		t.Errorf("getChatAdministrators called %d times, want 2", api.callCount())
+	}
+}
+
+func TestAuthorizerCoalescesLookups(t *testing.T) {
+	api := newAdminsClient()
+	api.release = make(chan struct{})
+	api.started = make(chan struct{}, 1)
+	authz := newAuthorizer(t, api)
+
+	var wg sync.WaitGroup
+
+	for range 5 {
+		wg.Go(func() {
+			admins, err := authz.Admins(context.Background(), groupID)
+			if err != nil || len(admins) != 3 {
+				t.Errorf("Admins()=%v, %v, want 3 administrators", admins, err)
+			}
+		})
+	}
+
+	<-api.started
+	time.Sleep(20 * time.Millisecond)
+	close(api.release)
+	wg.Wait()
+
+	if api.callCount() != 1 {
+		t.Errorf("getChatAdministrators called %d times, want 1", api.callCount())
+	}
+}
+
+func TestAuthorizerDropsLookupsInvalidatedMeanwhile(t *testing.T) {
+	api := newAdminsClient()
+	api.release = make(chan struct{})
+	api.started = make(chan struct{}, 1)
+	authz := newAuthorizer(t, api)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		if _, err := authz.Admins(context.Background(), groupID); err != nil {
+			t.Errorf("Admins() unexpected error: %v", err)
+		}
+	}()
+
+	// An administrator is demoted while their chat is looked up.
+	<-api.started
+	authz.Invalidate(groupID)
+	close(api.release)
+	<-done
+
+	api.admins = api.admins[:1]
+
+	admins, err := authz.Admins(context.Background(), groupID)
+	if err != nil {
+		t.Fatalf("Admins() unexpected error: %v", err)
+	}
+
+	if len(admins) != 1 || api.callCount() != 2 {
+		t.Fatalf("Admins()=%v after %d calls, want the demotion looked up again", admins, api.callCount())
+	}
+}
+
+func TestAuthorizerRequire(t *testing.T) {
+	var denied []any
+
+	authz := newAuthorizer(t, newAdminsClient(), authorization.WithOnDenied(func(_ context.Context, payload any) error {
+		denied = append(denied, payload)
+
+		return eventemitter.ErrBreak
+	}))
+
+	var handled []int64
+	listener := authz.Require(authz.ChatAdmin()).Handle(eventemitter.ListenerFunc(func(_ context.Context, payload any) error {
+		handled = append(handled, payload.(*events.CommandEvent).Message.From.Id)
+
+		return nil
+	}))
+
+	if err := listener.Handle(context.Background(), commandFrom(2)); err != nil {
+		t.Fatalf("Handle() unexpected error: %v", err)
+	}
+
+	if err := listener.Handle(context.Background(), commandFrom(4)); !errors.Is(err, eventemitter.ErrBreak) {
+		t.Fatalf("Handle() error = %v, want ErrBreak", err)
+	}
+
+	if len(handled) != 1 || handled[0] != 2 {
+		t.Errorf("handled = %v, want [2]", handled)
+	}
+
+	if len(denied) != 1 {
+		t.Errorf("denied %d events, want 1", len(denied))
+	}
+
+	match := authz.CommandMatcher(authz.Can(authorization.PermissionRestrictMembers))
+	if !match(commandFrom(2)) || match(commandFrom(3)) {
+		t.Error("CommandMatcher() does not match the administrators granted the permission only")
+	}
+}
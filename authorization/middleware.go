@@ -0,0 +1,78 @@
+package authorization
+
+import (
+	"context"
+
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/handlers"
+)
+
+// Require returns a middleware that lets through the events whose subject
+// holds role. Other events are stopped: the onDenied callback runs for them
+// when set, and they are dropped otherwise. Failures to check the role are
+// returned to the event emitter.
+//
+// Add it to a registry with handlers.WithMiddleware to restrict its handlers:
+//
+//	admins := bot.Handlers().With(handlers.WithMiddleware(authz.Require(authz.ChatAdmin())))
+//	admins.OnCommandName("ban", ban)
+func (a *Authorizer) Require(role Role) eventemitter.Middleware {
+	return eventemitter.MiddlewareFunc(func(next eventemitter.Listener) eventemitter.Listener {
+		return eventemitter.ListenerFunc(func(ctx context.Context, payload any) error {
+			ok, err := a.Check(ctx, role, payload)
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				return next.Handle(ctx, payload)
+			}
+
+			if a.opts.onDenied != nil {
+				return a.opts.onDenied(ctx, payload)
+			}
+
+			return nil
+		})
+	})
+}
+
+// CommandMatcher returns a matcher of the commands sent by subjects holding
+// role, for Registry.OnCommandMatch. Matchers run without a context, so the
+// administrator lookups they cause are bounded by WithLookupTimeout, and
+// their failures are logged and do not match. Prefer Require where a
+// matcher is not needed.
+func (a *Authorizer) CommandMatcher(role Role) handlers.CommandMatcher {
+	return func(event *events.CommandEvent) bool {
+		return a.match(role, event)
+	}
+}
+
+// MessageMatcher returns a matcher of the messages sent by subjects holding
+// role, for Registry.OnMessageMatch. See CommandMatcher.
+func (a *Authorizer) MessageMatcher(role Role) handlers.MessageMatcher {
+	return func(event *events.MessageEvent) bool {
+		return a.match(role, event)
+	}
+}
+
+// CallbackQueryMatcher returns a matcher of the callback queries of subjects
+// holding role, for Registry.OnCallbackQueryMatch. See CommandMatcher.
+func (a *Authorizer) CallbackQueryMatcher(role Role) handlers.CallbackQueryMatcher {
+	return func(event *events.CallbackQueryEvent) bool {
+		return a.match(role, event)
+	}
+}
+
+func (a *Authorizer) match(role Role, payload any) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), a.opts.lookupTimeout)
+	defer cancel()
+
+	ok, err := a.Check(ctx, role, payload)
+	if err != nil {
+		a.log.Errorf("check role: %v", err)
+	}
+
+	return ok
+}
@@ -0,0 +1,100 @@
+// Code generated by options-gen v0.55.3. DO NOT EDIT.
+
+package authorization
+
+import (
+	"context"
+	fmt461e464ebed9 "fmt"
+	"time"
+
+	errors461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/errors"
+	validator461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/validator"
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+type OptOptionsSetter func(o *Options)
+
+func NewOptions(
+	client client.ClientWithResponsesInterface,
+	options ...OptOptionsSetter,
+) Options {
+	var o Options
+
+	// Setting defaults from field tag (if present)
+
+	o.adminTTL, _ = time.ParseDuration("5m")
+	o.lookupTimeout, _ = time.ParseDuration("5s")
+
+	o.client = client
+
+	for _, opt := range options {
+		opt(&o)
+	}
+	return o
+}
+
+// owners are the IDs of the users who own the bot.
+func WithOwners(opt []int64) OptOptionsSetter {
+	return func(o *Options) { o.owners = opt }
+}
+
+// adminTTL is how long the administrators of a chat are cached. Chat member
+// updates about an administrator drop the cache of the chat earlier.
+func WithAdminTTL(opt time.Duration) OptOptionsSetter {
+	return func(o *Options) { o.adminTTL = opt }
+}
+
+// trustAnonymous grants anonymous administrators, who post on behalf of
+// the chat, the ChatCreator and Can roles too. Telegram does not reveal
+// who they are, so by default they only hold ChatAdmin.
+func WithTrustAnonymous(opt bool) OptOptionsSetter {
+	return func(o *Options) { o.trustAnonymous = opt }
+}
+
+// lookupTimeout bounds the administrator lookups of matchers, which run
+// without a context.
+func WithLookupTimeout(opt time.Duration) OptOptionsSetter {
+	return func(o *Options) { o.lookupTimeout = opt }
+}
+
+// onDenied is called by Require for the events it stops. Its error is
+// returned to the event emitter, so it can return eventemitter.ErrBreak
+// to stop the later listeners of the event.
+func WithOnDenied(opt func(ctx context.Context, payload any) error) OptOptionsSetter {
+	return func(o *Options) { o.onDenied = opt }
+}
+
+// logger is the logger to use.
+func WithLogger(opt logger.Logger) OptOptionsSetter {
+	return func(o *Options) { o.logger = opt }
+}
+
+func (o *Options) Validate() error {
+	errs := new(errors461e464ebed9.ValidationErrors)
+	errs.Add(errors461e464ebed9.NewValidationError("client", _validate_Options_client(o)))
+	errs.Add(errors461e464ebed9.NewValidationError("adminTTL", _validate_Options_adminTTL(o)))
+	errs.Add(errors461e464ebed9.NewValidationError("lookupTimeout", _validate_Options_lookupTimeout(o)))
+	return errs.AsError()
+}
+
+func _validate_Options_client(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.client, "required"); err != nil {
+		return fmt461e464ebed9.Errorf("field `client` did not pass the test: %w", err)
+	}
+	return nil
+}
+
+func _validate_Options_adminTTL(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.adminTTL, "gt=0"); err != nil {
+		return fmt461e464ebed9.Errorf("field `adminTTL` did not pass the test: %w", err)
+	}
+	return nil
+}
+
+func _validate_Options_lookupTimeout(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.lookupTimeout, "gt=0"); err != nil {
+		return fmt461e464ebed9.Errorf("field `lookupTimeout` did not pass the test: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package authorization
+
+import (
+	"context"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+//go:generate go tool options-gen -out-filename=options.gen.go -from-struct=Options
+
+// Options is the options for the Authorizer.
+type Options struct {
+	// client is the Telegram API client used to look up chat administrators.
+	client client.ClientWithResponsesInterface `option:"mandatory" validate:"required"`
+	// owners are the IDs of the users who own the bot.
+	owners []int64
+	// adminTTL is how long the administrators of a chat are cached. Chat member
+	// updates about an administrator drop the cache of the chat earlier.
+	adminTTL time.Duration `default:"5m" validate:"gt=0"`
+	// trustAnonymous grants anonymous administrators, who post on behalf of
+	// the chat, the ChatCreator and Can roles too. Telegram does not reveal
+	// who they are, so by default they only hold ChatAdmin.
+	trustAnonymous bool
+	// lookupTimeout bounds the administrator lookups of matchers, which run
+	// without a context.
+	lookupTimeout time.Duration `default:"5s" validate:"gt=0"`
+	// onDenied is called by Require for the events it stops. Its error is
+	// returned to the event emitter, so it can return eventemitter.ErrBreak
+	// to stop the later listeners of the event.
+	onDenied func(ctx context.Context, payload any) error
+	// logger is the logger to use.
+	logger logger.Logger
+}
@@ -0,0 +1,115 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// Permissions of chat administrators, as named by the Bot API.
+const (
+	PermissionManageChat       = "can_manage_chat"
+	PermissionDeleteMessages   = "can_delete_messages"
+	PermissionManageVideoChats = "can_manage_video_chats"
+	PermissionRestrictMembers  = "can_restrict_members"
+	PermissionPromoteMembers   = "can_promote_members"
+	PermissionChangeInfo       = "can_change_info"
+	PermissionInviteUsers      = "can_invite_users"
+	PermissionPinMessages      = "can_pin_messages"
+	PermissionManageTopics     = "can_manage_topics"
+	PermissionPostMessages     = "can_post_messages"
+	PermissionEditMessages     = "can_edit_messages"
+)
+
+// Role reports whether a subject holds it.
+type Role func(ctx context.Context, subject Subject) (bool, error)
+
+// Owner returns the role of the bot owners given by WithOwners.
+func (a *Authorizer) Owner() Role {
+	return func(_ context.Context, subject Subject) (bool, error) {
+		return !subject.Anonymous && slices.Contains(a.opts.owners, subject.UserID), nil
+	}
+}
+
+// ChatAdmin returns the role of the administrators of the subject's chat,
+// its creator included. Anonymous administrators hold it.
+func (a *Authorizer) ChatAdmin() Role {
+	return func(ctx context.Context, subject Subject) (bool, error) {
+		if subject.Anonymous {
+			return true, nil
+		}
+
+		_, ok, err := a.admin(ctx, subject)
+
+		return ok, err
+	}
+}
+
+// ChatCreator returns the role of the creator of the subject's chat.
+func (a *Authorizer) ChatCreator() Role {
+	return func(ctx context.Context, subject Subject) (bool, error) {
+		if subject.Anonymous {
+			return a.opts.trustAnonymous, nil
+		}
+
+		admin, ok, err := a.admin(ctx, subject)
+
+		return ok && admin.Creator, err
+	}
+}
+
+// Can returns the role of the administrators of the subject's chat granted
+// all of permissions, such as PermissionRestrictMembers. The creator holds
+// every permission.
+func (a *Authorizer) Can(permissions ...string) Role {
+	return func(ctx context.Context, subject Subject) (bool, error) {
+		if subject.Anonymous {
+			return a.opts.trustAnonymous, nil
+		}
+
+		admin, ok, err := a.admin(ctx, subject)
+		if err != nil || !ok {
+			return false, err
+		}
+
+		for _, permission := range permissions {
+			if !admin.Creator && !admin.Permissions[permission] {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+}
+
+// AnyOf returns a role held by the subjects holding any of roles. A role
+// that fails to be checked does not stop the next ones from granting it.
+func AnyOf(roles ...Role) Role {
+	return func(ctx context.Context, subject Subject) (bool, error) {
+		var errs error
+
+		for _, role := range roles {
+			ok, err := role(ctx, subject)
+			if ok {
+				return true, nil
+			}
+
+			errs = errors.Join(errs, err)
+		}
+
+		return false, errs
+	}
+}
+
+// AllOf returns a role held by the subjects holding all of roles.
+func AllOf(roles ...Role) Role {
+	return func(ctx context.Context, subject Subject) (bool, error) {
+		for _, role := range roles {
+			if ok, err := role(ctx, subject); err != nil || !ok {
+				return false, err
+			}
+		}
+
+		return true, nil
+	}
+}
@@ -0,0 +1,96 @@
+package authorization
+
+import (
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+// Subject is a user acting in a chat.
+type Subject struct {
+	UserID int64
+	// ChatID is the chat the user acts in, or zero for events outside chats,
+	// such as inline queries.
+	ChatID int64
+	// Anonymous is set for the messages an administrator sent on behalf of
+	// the chat. UserID is then the placeholder user Telegram sends them from.
+	Anonymous bool
+}
+
+// SubjectFromMessage returns who sent message and where.
+func SubjectFromMessage(message *client.Message) Subject {
+	subject := Subject{ChatID: message.Chat.Id}
+
+	if message.From != nil {
+		subject.UserID = message.From.Id
+	}
+
+	if message.SenderChat != nil && message.SenderChat.Id == message.Chat.Id {
+		subject.Anonymous = true
+	}
+
+	return subject
+}
+
+// SubjectOf returns the subject of the event carried by payload, and false
+// for events without a user.
+func SubjectOf(payload any) (Subject, bool) {
+	switch event := payload.(type) {
+	case *events.MessageEvent:
+		return messageSubject(event.Message)
+	case *events.CommandEvent:
+		return messageSubject(event.Message)
+	case *events.CallbackQueryEvent:
+		return callbackSubject(event.CallbackQuery)
+	case *events.InlineQueryEvent:
+		if event.InlineQuery == nil {
+			return Subject{}, false
+		}
+
+		return Subject{UserID: event.InlineQuery.From.Id}, true
+	case *events.ChatMemberEvent:
+		return chatMemberSubject(event.ChatMember)
+	case *events.ChatJoinRequestEvent:
+		return joinRequestSubject(event.ChatJoinRequest)
+	default:
+		return Subject{}, false
+	}
+}
+
+func messageSubject(message *client.Message) (Subject, bool) {
+	if message == nil || (message.From == nil && message.SenderChat == nil) {
+		return Subject{}, false
+	}
+
+	return SubjectFromMessage(message), true
+}
+
+func callbackSubject(query *client.CallbackQuery) (Subject, bool) {
+	if query == nil {
+		return Subject{}, false
+	}
+
+	subject := Subject{UserID: query.From.Id}
+
+	if message, err := respond.CallbackMessage(query); err == nil {
+		subject.ChatID = message.Chat.Id
+	}
+
+	return subject, true
+}
+
+func chatMemberSubject(update *client.ChatMemberUpdated) (Subject, bool) {
+	if update == nil {
+		return Subject{}, false
+	}
+
+	return Subject{UserID: update.From.Id, ChatID: update.Chat.Id}, true
+}
+
+func joinRequestSubject(request *client.ChatJoinRequest) (Subject, bool) {
+	if request == nil {
+		return Subject{}, false
+	}
+
+	return Subject{UserID: request.From.Id, ChatID: request.Chat.Id}, true
+}
@@ -3,6 +3,7 @@ package runtime
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -13,8 +14,10 @@ import (
 	"github.com/tgbotkit/runtime/handlers"
 	"github.com/tgbotkit/runtime/listeners"
 	"github.com/tgbotkit/runtime/logger"
+	"github.com/tgbotkit/runtime/messagetype"
 	"github.com/tgbotkit/runtime/middleware"
 	"github.com/tgbotkit/runtime/respond"
+	"github.com/tgbotkit/runtime/throttle"
 	"github.com/tgbotkit/runtime/updatepoller"
 	"github.com/tgbotkit/runtime/updatepoller/offsetstore"
 	"golang.org/x/sync/errgroup"
@@ -28,9 +31,10 @@ const (
 
 // Bot is the main bot structure.
 type Bot struct {
-	opts      Options
-	registry  *handlers.Registry
-	responder *respond.Responder
+	opts         Options
+	registry     *handlers.Registry
+	responder    *respond.Responder
+	messageTypes *messagetype.Registry
 }
 
 var _ botcontext.BotContext = (*Bot)(nil)
@@ -60,7 +64,7 @@ func New(opts Options) (*Bot, error) {
 	}
 
 	if opts.client == nil {
-		opts.client, err = newDefaultClient(opts.botToken)
+		opts.client, err = newDefaultClient(opts.botToken, opts.limiter)
 		if err != nil {
 			return nil, err
 		}
@@ -72,9 +76,10 @@ func New(opts Options) (*Bot, error) {
 	}
 
 	bot := &Bot{
-		opts:      opts,
-		registry:  handlers.NewRegistry(opts.eventEmitter, opts.logger),
-		responder: respond.New(opts.client),
+		opts:         opts,
+		registry:     handlers.NewRegistry(opts.eventEmitter, opts.logger),
+		responder:    respond.New(opts.client),
+		messageTypes: messagetype.NewRegistry(),
 	}
 
 	registerDefaults(opts, bot, botName)
@@ -131,6 +136,12 @@ func (b *Bot) Responder() *respond.Responder {
 	return b.responder
 }
 
+// MessageTypes returns the registry used to classify messages. Detectors registered
+// here feed MessageEvent.Type for every message-like event.
+func (b *Bot) MessageTypes() *messagetype.Registry {
+	return b.messageTypes
+}
+
 func (b *Bot) initDefaultPoller() error {
 	poller, err := updatepoller.NewPoller(updatepoller.NewOptions(
 		b.opts.client,
@@ -149,7 +160,7 @@ func (b *Bot) initDefaultPoller() error {
 	return nil
 }
 
-func newDefaultClient(botToken string) (client.ClientWithResponsesInterface, error) {
+func newDefaultClient(botToken string, limiter *throttle.Limiter) (client.ClientWithResponsesInterface, error) {
 	serverURL, err := client.NewServerUrlTelegramBotAPIEndpointSubstituteBotTokenWithYourBotToken(
 		client.ServerUrlTelegramBotAPIEndpointSubstituteBotTokenWithYourBotTokenBotTokenVariable(botToken),
 	)
@@ -157,7 +168,12 @@ func newDefaultClient(botToken string) (client.ClientWithResponsesInterface, err
 		return nil, fmt.Errorf("create server URL: %w", err)
 	}
 
-	api, err := client.NewClientWithResponses(serverURL)
+	var clientOpts []client.ClientOption
+	if limiter != nil {
+		clientOpts = append(clientOpts, client.WithHTTPClient(limiter))
+	}
+
+	api, err := client.NewClientWithResponses(serverURL, clientOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("create API client: %w", err)
 	}
@@ -189,7 +205,12 @@ func registerDefaults(opts Options, bot *Bot, botName string) {
 	}
 
 	if opts.defaultListenersEnabled {
-		opts.eventEmitter.AddListener(events.OnUpdate, listeners.Classifier(opts.eventEmitter))
+		opts.eventEmitter.AddListener(events.OnUpdate, listeners.ClassifierWithTypes(opts.eventEmitter, bot.messageTypes))
+		opts.eventEmitter.AddListener(
+			events.OnMessage,
+			listeners.ChatMigration(opts.eventEmitter, opts.chatMigrationHooks...),
+		)
+		opts.eventEmitter.AddListener(events.OnMessage, listeners.Payments(opts.eventEmitter))
 		opts.eventEmitter.AddListener(events.OnMessage, listeners.CommandParser(opts.eventEmitter, botName))
 	}
 }
@@ -229,7 +250,38 @@ func (b *Bot) receiveLoop(ctx context.Context) error {
 			}
 
 			b.Logger().Debugf("got update: %v", update.UpdateId)
-			b.opts.eventEmitter.Emit(ctx, events.OnUpdate, &events.UpdateEvent{Update: &update})
+
+			b.handleUpdate(ctx, update)
 		}
 	}
 }
+
+func (b *Bot) handleUpdate(ctx context.Context, update client.Update) {
+	raw := b.rawUpdate(update.UpdateId)
+
+	if source, ok := b.opts.updateSource.(UpdateContextSource); ok {
+		var done func()
+
+		ctx, done = source.UpdateContext(ctx, update.UpdateId)
+		defer done()
+	}
+
+	if b.opts.deduplicator != nil && b.opts.deduplicator.IsDuplicate(ctx, &update) {
+		return
+	}
+
+	ctx = events.WithUpdate(ctx, &update)
+
+	b.opts.eventEmitter.Emit(ctx, events.OnUpdate, &events.UpdateEvent{Update: &update, Raw: raw})
+}
+
+func (b *Bot) rawUpdate(updateID int) json.RawMessage {
+	source, ok := b.opts.updateSource.(RawUpdateSource)
+	if !ok {
+		return nil
+	}
+
+	raw, _ := source.RawUpdate(updateID)
+
+	return raw
+}
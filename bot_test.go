@@ -2,8 +2,11 @@ package runtime_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -11,8 +14,12 @@ import (
 
 	"github.com/tgbotkit/client"
 	"github.com/tgbotkit/runtime"
+	"github.com/tgbotkit/runtime/dedup"
+	"github.com/tgbotkit/runtime/dedup/seenstore"
 	"github.com/tgbotkit/runtime/eventemitter"
 	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/messagetype"
+	"github.com/tgbotkit/runtime/webhook"
 )
 
 // mockClient mocks the Telegram API client.
@@ -95,6 +102,18 @@ func (m *mockUpdateSource) Stop(_ context.Context) error {
 	return nil
 }
 
+// mockRawUpdateSource additionally exposes raw update JSON.
+type mockRawUpdateSource struct {
+	mockUpdateSource
+	raw map[int]json.RawMessage
+}
+
+func (m *mockRawUpdateSource) RawUpdate(updateID int) (json.RawMessage, bool) {
+	raw, ok := m.raw[updateID]
+
+	return raw, ok
+}
+
 func TestNew(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		cl := &mockClient{}
@@ -233,6 +252,39 @@ func TestNew(t *testing.T) {
 		}
 	})
 
+	t.Run("custom message types feed handlers", func(t *testing.T) {
+		bot, err := runtime.New(runtime.NewOptions(
+			"test-token",
+			runtime.WithClient(&mockClient{}),
+			runtime.WithBotUsername("TestBot"),
+		))
+		if err != nil {
+			t.Fatalf("New() unexpected error: %v", err)
+		}
+
+		const greeting messagetype.MessageType = "greeting"
+
+		bot.MessageTypes().Register(1, messagetype.DetectorFunc(func(m *client.Message) (messagetype.MessageType, bool) {
+			return greeting, m.Text != nil && *m.Text == "hi"
+		}))
+
+		var called bool
+		bot.Handlers().OnMessageType(greeting, func(_ context.Context, _ *events.MessageEvent) error {
+			called = true
+
+			return nil
+		})
+
+		text := "hi"
+		bot.EventEmitter().Emit(context.Background(), events.OnUpdate, &events.UpdateEvent{
+			Update: &client.Update{UpdateId: 1, Message: &client.Message{Text: &text}},
+		})
+
+		if !called {
+			t.Fatal("custom message type handler was not called")
+		}
+	})
+
 	t.Run("disabled default listeners skips getMe", func(t *testing.T) {
 		cl := &mockClient{
 			getMeFunc: func(_ context.Context, _ ...client.RequestEditorFn) (*client.GetMeResponse, error) {
@@ -368,6 +420,167 @@ func TestBot_Run(t *testing.T) {
 		}
 	})
 
+	t.Run("drops duplicate updates", func(t *testing.T) {
+		cl := &mockClient{}
+		us := &mockUpdateSource{ch: make(chan client.Update, 3)}
+
+		ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+		if err != nil {
+			t.Fatalf("NewSync() unexpected error: %v", err)
+		}
+
+		var received atomic.Int32
+		ee.AddListener(events.OnUpdate, eventemitter.ListenerFunc(func(_ context.Context, _ any) error {
+			received.Add(1)
+			return nil
+		}))
+
+		deduplicator, err := dedup.New(dedup.NewOptions(seenstore.NewInMemorySeenStore(time.Minute, 100)))
+		if err != nil {
+			t.Fatalf("dedup.New() unexpected error: %v", err)
+		}
+
+		bot, err := runtime.New(runtime.NewOptions(
+			"test-token",
+			runtime.WithClient(cl),
+			runtime.WithUpdateSource(us),
+			runtime.WithEventEmitter(ee),
+			runtime.WithDeduplicator(deduplicator),
+		))
+		if err != nil {
+			t.Fatalf("New() unexpected error: %v", err)
+		}
+
+		us.ch <- client.Update{UpdateId: 1}
+		us.ch <- client.Update{UpdateId: 1}
+		us.ch <- client.Update{UpdateId: 2}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- bot.Run(ctx)
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		if err := <-errCh; err != nil {
+			t.Fatalf("Run() error=%v, want nil", err)
+		}
+		if got := received.Load(); got != 2 {
+			t.Fatalf("received=%d, want 2", got)
+		}
+		if got := deduplicator.Dropped(); got != 1 {
+			t.Fatalf("Dropped()=%d, want 1", got)
+		}
+	})
+
+	t.Run("passes raw update JSON to OnUpdate", func(t *testing.T) {
+		cl := &mockClient{}
+		raw := json.RawMessage(`{"update_id":7,"future_field":{}}`)
+		us := &mockRawUpdateSource{
+			mockUpdateSource: mockUpdateSource{ch: make(chan client.Update, 1)},
+			raw:              map[int]json.RawMessage{7: raw},
+		}
+
+		ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+		if err != nil {
+			t.Fatalf("NewSync() unexpected error: %v", err)
+		}
+
+		gotRaw := make(chan json.RawMessage, 1)
+		ee.AddListener(events.OnUpdate, eventemitter.ListenerFunc(func(_ context.Context, payload any) error {
+			if e, ok := payload.(*events.UpdateEvent); ok {
+				gotRaw <- e.Raw
+			}
+			return nil
+		}))
+
+		bot, err := runtime.New(runtime.NewOptions(
+			"test-token",
+			runtime.WithClient(cl),
+			runtime.WithUpdateSource(us),
+			runtime.WithEventEmitter(ee),
+		))
+		if err != nil {
+			t.Fatalf("New() unexpected error: %v", err)
+		}
+
+		us.ch <- client.Update{UpdateId: 7}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- bot.Run(ctx)
+		}()
+
+		select {
+		case got := <-gotRaw:
+			if string(got) != string(raw) {
+				t.Fatalf("raw=%s, want %s", got, raw)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for update")
+		}
+
+		cancel()
+		if err := <-errCh; err != nil {
+			t.Fatalf("Run() error=%v, want nil", err)
+		}
+	})
+
+	t.Run("writes webhook replies in the response body", func(t *testing.T) {
+		wh, err := webhook.New(webhook.NewOptions(webhook.WithReplyTimeout(time.Second)))
+		if err != nil {
+			t.Fatalf("webhook.New() unexpected error: %v", err)
+		}
+
+		ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+		if err != nil {
+			t.Fatalf("NewSync() unexpected error: %v", err)
+		}
+
+		ee.AddListener(events.OnUpdate, eventemitter.ListenerFunc(func(ctx context.Context, _ any) error {
+			webhook.Reply(ctx, "sendMessage", client.SendMessageJSONRequestBody{ChatId: 1, Text: "pong"})
+
+			return nil
+		}))
+
+		bot, err := runtime.New(runtime.NewOptions(
+			"test-token",
+			runtime.WithClient(&mockClient{}),
+			runtime.WithUpdateSource(wh),
+			runtime.WithEventEmitter(ee),
+		))
+		if err != nil {
+			t.Fatalf("New() unexpected error: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- bot.Run(ctx)
+		}()
+
+		rr := httptest.NewRecorder()
+		wh.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"update_id":9}`)))
+
+		if !strings.Contains(rr.Body.String(), `"method":"sendMessage"`) {
+			t.Fatalf("response body=%q, want sendMessage reply", rr.Body.String())
+		}
+
+		cancel()
+		if err := <-errCh; err != nil {
+			t.Fatalf("Run() error=%v, want nil", err)
+		}
+	})
+
 	t.Run("returns ErrUpdateSourceClosed when source channel closes", func(t *testing.T) {
 		cl := &mockClient{}
 		closedCh := make(chan client.Update)
@@ -0,0 +1,159 @@
+// Package botapi describes errors reported by the Telegram Bot API.
+//
+// Failed Bot API calls answer with {"ok":false,"error_code":...,"description":...}
+// and optional parameters such as retry_after and migrate_to_chat_id. The
+// runtime packages return these as *APIError, wrapped with the failing
+// operation, so callers can inspect them with errors.As or the Is* helpers.
+package botapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIError is an unsuccessful Bot API response.
+type APIError struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+	// ErrorCode is Telegram's error code, usually equal to the HTTP status.
+	ErrorCode int
+	// Description is Telegram's human-readable explanation.
+	Description string
+	// RetryAfter is how long to wait before repeating a request rejected by
+	// flood control.
+	RetryAfter time.Duration
+	// MigrateToChatID is the supergroup a group was upgraded to, or 0.
+	MigrateToChatID int64
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("telegram error %d", e.ErrorCode)
+	}
+
+	return fmt.Sprintf("telegram error %d: %s", e.ErrorCode, e.Description)
+}
+
+// envelope is the part of a Bot API response that reports failures.
+type envelope struct {
+	Ok          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+	Parameters  *struct {
+		MigrateToChatID int64 `json:"migrate_to_chat_id"`
+		RetryAfter      int   `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// FromResponse returns the error reported by a Bot API response, or nil when
+// the response is successful. An error status whose body is not a Bot API
+// envelope, such as a proxy's error page, is reported with the status alone.
+func FromResponse(statusCode int, body []byte) *APIError {
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		if statusCode < http.StatusBadRequest {
+			return nil
+		}
+
+		return &APIError{StatusCode: statusCode, ErrorCode: statusCode, Description: http.StatusText(statusCode)}
+	}
+
+	if env.Ok && statusCode == http.StatusOK {
+		return nil
+	}
+
+	apiErr := &APIError{
+		StatusCode:  statusCode,
+		ErrorCode:   env.ErrorCode,
+		Description: env.Description,
+	}
+
+	if apiErr.ErrorCode == 0 {
+		apiErr.ErrorCode = statusCode
+	}
+
+	if apiErr.Description == "" {
+		apiErr.Description = http.StatusText(statusCode)
+	}
+
+	if env.Parameters != nil {
+		apiErr.MigrateToChatID = env.Parameters.MigrateToChatID
+		apiErr.RetryAfter = time.Duration(env.Parameters.RetryAfter) * time.Second
+	}
+
+	return apiErr
+}
+
+// As returns the *APIError in err's chain.
+func As(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+
+	return nil, false
+}
+
+// IsFlood reports whether err is a 429 Too Many Requests. RetryAfter tells how
+// long to wait.
+func IsFlood(err error) bool {
+	return hasCode(err, http.StatusTooManyRequests)
+}
+
+// IsBlockedByUser reports whether the user blocked the bot.
+func IsBlockedByUser(err error) bool {
+	return matches(err, http.StatusForbidden, "bot was blocked by the user")
+}
+
+// IsUserDeactivated reports whether the user deleted their account.
+func IsUserDeactivated(err error) bool {
+	return matches(err, http.StatusForbidden, "user is deactivated")
+}
+
+// IsKickedFromChat reports whether the bot was removed from the group or channel.
+func IsKickedFromChat(err error) bool {
+	return matches(err, http.StatusForbidden, "bot was kicked")
+}
+
+// IsChatNotFound reports whether the chat does not exist or the bot never had
+// access to it.
+func IsChatNotFound(err error) bool {
+	return matches(err, http.StatusBadRequest, "chat not found")
+}
+
+// IsMessageNotModified reports whether an edit left the message unchanged.
+func IsMessageNotModified(err error) bool {
+	return matches(err, http.StatusBadRequest, "message is not modified")
+}
+
+// IsParseError reports whether the text's HTML or MarkdownV2 markup is invalid.
+func IsParseError(err error) bool {
+	return matches(err, http.StatusBadRequest, "can't parse entities")
+}
+
+// IsChatMigrated reports whether the group was upgraded to a supergroup.
+// MigrateToChatID holds the new chat.
+func IsChatMigrated(err error) bool {
+	apiErr, ok := As(err)
+
+	return ok && apiErr.MigrateToChatID != 0
+}
+
+func hasCode(err error, code int) bool {
+	apiErr, ok := As(err)
+
+	return ok && apiErr.ErrorCode == code
+}
+
+// matches compares the description case-insensitively: Telegram prefixes it
+// with the status text, as in "Forbidden: bot was blocked by the user".
+func matches(err error, code int, description string) bool {
+	apiErr, ok := As(err)
+
+	return ok && apiErr.ErrorCode == code && strings.Contains(strings.ToLower(apiErr.Description), description)
+}
@@ -0,0 +1,112 @@
+package botapi_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tgbotkit/runtime/botapi"
+)
+
+func TestFromResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   *botapi.APIError
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			body:   `{"ok":true,"result":true}`,
+		},
+		{
+			name:   "flood",
+			status: http.StatusTooManyRequests,
+			body:   `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`,
+			want: &botapi.APIError{
+				StatusCode:  http.StatusTooManyRequests,
+				ErrorCode:   http.StatusTooManyRequests,
+				Description: "Too Many Requests: retry after 7",
+				RetryAfter:  7 * time.Second,
+			},
+		},
+		{
+			name:   "migrated",
+			status: http.StatusBadRequest,
+			body:   `{"ok":false,"error_code":400,"description":"Bad Request: group chat was upgraded to a supergroup chat","parameters":{"migrate_to_chat_id":-1001}}`,
+			want: &botapi.APIError{
+				StatusCode:      http.StatusBadRequest,
+				ErrorCode:       http.StatusBadRequest,
+				Description:     "Bad Request: group chat was upgraded to a supergroup chat",
+				MigrateToChatID: -1001,
+			},
+		},
+		{
+			name:   "not an envelope",
+			status: http.StatusBadGateway,
+			body:   `<html>bad gateway</html>`,
+			want: &botapi.APIError{
+				StatusCode:  http.StatusBadGateway,
+				ErrorCode:   http.StatusBadGateway,
+				Description: "Bad Gateway",
+			},
+		},
+		{
+			name:   "malformed success",
+			status: http.StatusOK,
+			body:   `{`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := botapi.FromResponse(tt.status, []byte(tt.body))
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Fatalf("FromResponse()=%+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassification(t *testing.T) {
+	t.Parallel()
+
+	wrap := func(code int, description string) error {
+		return fmt.Errorf("send message: %w", &botapi.APIError{ErrorCode: code, Description: description})
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		is   func(error) bool
+		want bool
+	}{
+		{"blocked", wrap(403, "Forbidden: bot was blocked by the user"), botapi.IsBlockedByUser, true},
+		{"deactivated", wrap(403, "Forbidden: user is deactivated"), botapi.IsUserDeactivated, true},
+		{"kicked", wrap(403, "Forbidden: bot was kicked from the supergroup chat"), botapi.IsKickedFromChat, true},
+		{"chat not found", wrap(400, "Bad Request: chat not found"), botapi.IsChatNotFound, true},
+		{"not modified", wrap(400, "Bad Request: message is not modified: specified new message content"), botapi.IsMessageNotModified, true},
+		{"parse", wrap(400, "Bad Request: can't parse entities: Unsupported start tag"), botapi.IsParseError, true},
+		{"flood", wrap(429, "Too Many Requests: retry after 3"), botapi.IsFlood, true},
+		{"other code", wrap(400, "Bad Request: bot was blocked by the user"), botapi.IsBlockedByUser, false},
+		{"plain error", errors.New("chat not found"), botapi.IsChatNotFound, false},
+		{"nil", nil, botapi.IsFlood, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.is(tt.err); got != tt.want {
+				t.Fatalf("classification of %v=%v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,437 @@
+// Package broadcast sends one message to many chats, such as an announcement
+// to every user of a bot.
+//
+// A Broadcaster paces sends below Telegram's flood limits, retries failures
+// that may pass, and classifies the ones that will not, so that users who
+// blocked the bot can be cleaned up. Progress is saved to a ProgressStore
+// every few recipients, and a broadcast run again with the same ID resumes
+// where it stopped.
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tgbotkit/runtime/botapi"
+	"github.com/tgbotkit/runtime/format"
+	"github.com/tgbotkit/runtime/logger"
+	"github.com/tgbotkit/runtime/respond"
+	"github.com/tgbotkit/runtime/throttle"
+)
+
+// retryBackoff is the delay before the first retry of a failure other than a
+// flood limit. It doubles with every attempt.
+const retryBackoff = time.Second
+
+// Sender sends the broadcast message to one chat.
+type Sender func(ctx context.Context, responder *respond.Responder, chatID int64) error
+
+// Text returns a Sender of a text message.
+func Text(text string, opts ...respond.SendTextOption) Sender {
+	return func(ctx context.Context, responder *respond.Responder, chatID int64) error {
+		_, err := responder.SendText(ctx, respond.ChatTarget{ChatID: chatID}, text, opts...)
+
+		return err
+	}
+}
+
+// Formatted returns a Sender of text built with the format package.
+func Formatted(text format.Text, opts ...respond.SendTextOption) Sender {
+	return func(ctx context.Context, responder *respond.Responder, chatID int64) error {
+		_, err := responder.SendFormatted(ctx, respond.ChatTarget{ChatID: chatID}, text, opts...)
+
+		return err
+	}
+}
+
+// Broadcaster sends broadcasts.
+type Broadcaster struct {
+	opts Options
+	log  logger.Logger
+}
+
+// New creates a new Broadcaster with the given options.
+func New(opts Options) (*Broadcaster, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid broadcast options: %w", err)
+	}
+
+	if opts.logger == nil {
+		opts.logger = logger.NewNop()
+	}
+
+	return &Broadcaster{
+		opts: opts,
+		log:  opts.logger,
+	}, nil
+}
+
+// Run sends a broadcast with send to every chat yielded by recipients and
+// returns its stats. id names the broadcast in the ProgressStore: a run with
+// the ID of an interrupted one skips the recipients it processed, so
+// recipients must come in the same order every time. A finished broadcast is
+// not sent again.
+//
+// Sends are made with throttle.PriorityLow, so that replies to users go first
+// through a shared throttle.Limiter. A few recipients in flight when the
+// broadcast was interrupted may get the message twice after resuming.
+//
+// Run stops when ctx ends or recipients fails, saving the progress, and
+// returns the stats so far with the error.
+func (b *Broadcaster) Run(
+	ctx context.Context,
+	id string,
+	recipients iter.Seq2[int64, error],
+	send Sender,
+) (Stats, error) {
+	progress, err := b.load(ctx, id)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	if progress.Done {
+		return progress.Stats, nil
+	}
+
+	state := newRunState(b, id, progress)
+	ctx = throttle.WithPriority(ctx, throttle.PriorityLow)
+	jobs := make(chan job)
+
+	var wg sync.WaitGroup
+
+	for range b.opts.workers {
+		wg.Go(func() {
+			for j := range jobs {
+				state.complete(ctx, j.index, b.deliver(ctx, state, j.chatID, send))
+			}
+		})
+	}
+
+	dispatched, err := b.dispatch(ctx, state, recipients, jobs)
+
+	wg.Wait()
+
+	return state.finish(ctx, dispatched, err)
+}
+
+type job struct {
+	index  int
+	chatID int64
+}
+
+func (b *Broadcaster) load(ctx context.Context, id string) (Progress, error) {
+	if b.opts.store == nil {
+		return Progress{}, nil
+	}
+
+	progress, err := b.opts.store.Load(ctx, id)
+	if err != nil {
+		return Progress{}, fmt.Errorf("load broadcast %q progress: %w", id, err)
+	}
+
+	return progress, nil
+}
+
+// dispatch hands the recipients not yet processed to the workers at the
+// configured rate and closes jobs. It returns how many recipients, counted
+// from the first, were handed out or skipped as processed.
+func (b *Broadcaster) dispatch(
+	ctx context.Context,
+	state *runState,
+	recipients iter.Seq2[int64, error],
+	jobs chan<- job,
+) (int, error) {
+	defer close(jobs)
+
+	ticker := time.NewTicker(time.Second / time.Duration(b.opts.rate))
+	defer ticker.Stop()
+
+	index := 0
+
+	for chatID, err := range recipients {
+		if err != nil {
+			return index, fmt.Errorf("list broadcast recipients: %w", err)
+		}
+
+		if index < state.start {
+			index++
+
+			continue
+		}
+
+		if err := state.waitPause(ctx); err != nil {
+			return index, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return index, ctx.Err()
+		case <-ticker.C:
+		}
+
+		select {
+		case <-ctx.Done():
+			return index, ctx.Err()
+		case jobs <- job{index: index, chatID: chatID}:
+		}
+
+		index++
+	}
+
+	return index, nil
+}
+
+// deliver sends to one chat, retrying failures that may pass. It returns
+// outcomeCanceled when ctx ended first.
+func (b *Broadcaster) deliver(ctx context.Context, state *runState, chatID int64, send Sender) Outcome {
+	for attempt := 0; ; attempt++ {
+		err := send(ctx, b.opts.responder, chatID)
+		if err == nil {
+			return OutcomeSent
+		}
+
+		if ctx.Err() != nil {
+			return outcomeCanceled
+		}
+
+		outcome := classify(err)
+		if outcome.Unreachable() {
+			if b.opts.onUnreachable != nil {
+				b.opts.onUnreachable(ctx, chatID, outcome)
+			}
+
+			return outcome
+		}
+
+		delay, ok := b.retry(state, err, attempt)
+		if !ok {
+			b.log.Errorf("broadcast to chat %d: %v", chatID, err)
+
+			return OutcomeFailed
+		}
+
+		state.retried()
+
+		if !sleep(ctx, delay) {
+			return outcomeCanceled
+		}
+	}
+}
+
+// retry reports whether to repeat a failed send, and how long to wait first.
+// A flood limit pauses every worker, since they would all hit it; it is not
+// retried when the responder is throttled, which already requeued the send.
+func (b *Broadcaster) retry(state *runState, err error, attempt int) (time.Duration, bool) {
+	delay, ok := retryDelay(err, attempt)
+	if !ok {
+		return 0, false
+	}
+
+	flood := botapi.IsFlood(err)
+	if flood {
+		state.pause(delay)
+	}
+
+	if attempt >= b.opts.maxRetries || (flood && b.opts.throttled) {
+		return 0, false
+	}
+
+	return delay, true
+}
+
+// outcomeCanceled marks a send interrupted by the end of the run; the
+// recipient is processed again on resume.
+const outcomeCanceled Outcome = -1
+
+func classify(err error) Outcome {
+	switch {
+	case botapi.IsBlockedByUser(err):
+		return OutcomeBlocked
+	case botapi.IsUserDeactivated(err):
+		return OutcomeDeactivated
+	case botapi.IsChatNotFound(err):
+		return OutcomeChatNotFound
+	case botapi.IsKickedFromChat(err):
+		return OutcomeKicked
+	default:
+		return OutcomeFailed
+	}
+}
+
+// retryDelay reports whether a failed send may pass when repeated, and how
+// long to wait first: as long as Telegram asks after a flood limit, or an
+// exponential backoff after a server error or a network failure.
+func retryDelay(err error, attempt int) (time.Duration, bool) {
+	apiErr, ok := botapi.As(err)
+
+	switch {
+	case !ok:
+		return retryBackoff << attempt, true
+	case apiErr.ErrorCode == http.StatusTooManyRequests:
+		return max(apiErr.RetryAfter, retryBackoff), true
+	case apiErr.ErrorCode >= http.StatusInternalServerError:
+		return retryBackoff << attempt, true
+	default:
+		return 0, false
+	}
+}
+
+func sleep(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// runState tracks the progress of one run. Recipients complete out of order
+// across workers; the saved position only moves past a recipient once every
+// earlier one completed.
+type runState struct {
+	b     *Broadcaster
+	id    string
+	start int
+
+	// saveMu orders the saves; it is taken before mu, never while holding it.
+	saveMu sync.Mutex
+
+	mu          sync.Mutex
+	progress    Progress
+	completed   map[int]Outcome
+	unsaved     int
+	pausedUntil time.Time
+}
+
+func newRunState(b *Broadcaster, id string, progress Progress) *runState {
+	return &runState{
+		b:         b,
+		id:        id,
+		start:     progress.Position,
+		progress:  progress,
+		completed: make(map[int]Outcome),
+	}
+}
+
+func (s *runState) complete(ctx context.Context, index int, outcome Outcome) {
+	if outcome == outcomeCanceled {
+		return
+	}
+
+	s.mu.Lock()
+	s.completed[index] = outcome
+
+	for {
+		outcome, ok := s.completed[s.progress.Position]
+		if !ok {
+			break
+		}
+
+		delete(s.completed, s.progress.Position)
+		s.progress.Stats.add(outcome)
+		s.progress.Position++
+		s.unsaved++
+	}
+
+	due := s.unsaved >= s.b.opts.checkpointEvery
+	s.mu.Unlock()
+
+	if !due {
+		return
+	}
+
+	if err := s.save(ctx, false); err != nil {
+		s.b.log.Errorf("%v", err)
+	}
+}
+
+func (s *runState) retried() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.progress.Stats.Retries++
+}
+
+func (s *runState) pause(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if until := time.Now().Add(delay); until.After(s.pausedUntil) {
+		s.pausedUntil = until
+	}
+}
+
+func (s *runState) waitPause(ctx context.Context) error {
+	s.mu.Lock()
+	until := s.pausedUntil
+	s.mu.Unlock()
+
+	if delay := time.Until(until); delay > 0 && !sleep(ctx, delay) {
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// finish saves the final progress, also when ctx ended, and reports it. The
+// broadcast is done only when every one of the dispatched recipients
+// completed: those interrupted by the end of ctx are sent on resume.
+func (s *runState) finish(ctx context.Context, dispatched int, err error) (Stats, error) {
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	s.mu.Lock()
+	s.progress.Done = err == nil && s.progress.Position >= dispatched
+	stats := s.progress.Stats
+	s.mu.Unlock()
+
+	if saveErr := s.save(context.WithoutCancel(ctx), true); saveErr != nil {
+		err = errors.Join(err, saveErr)
+	}
+
+	return stats, err
+}
+
+// save stores a snapshot of the progress. The store is written outside mu so
+// that workers keep completing recipients meanwhile; saveMu keeps the saves
+// in order. Unless final, it is skipped when another worker saved since the
+// checkpoint was due.
+func (s *runState) save(ctx context.Context, final bool) error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.Lock()
+
+	if !final && s.unsaved < s.b.opts.checkpointEvery {
+		s.mu.Unlock()
+
+		return nil
+	}
+
+	s.unsaved = 0
+	progress := s.progress
+	s.mu.Unlock()
+
+	if s.b.opts.onProgress != nil {
+		s.b.opts.onProgress(progress)
+	}
+
+	if s.b.opts.store == nil {
+		return nil
+	}
+
+	if err := s.b.opts.store.Save(ctx, s.id, progress); err != nil {
+		return fmt.Errorf("save broadcast %q progress: %w", s.id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,338 @@
+package broadcast_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/broadcast"
+	"github.com/tgbotkit/runtime/broadcast/progressstore"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+type mockClient struct {
+	client.ClientWithResponsesInterface
+
+	mu       sync.Mutex
+	sent     []int64
+	sendFunc func(chatID int64, attempt int) (int, string)
+	attempts map[int64]int
+}
+
+func (m *mockClient) SendMessageWithResponse(
+	_ context.Context,
+	body client.SendMessageJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.SendMessageResponse, error) {
+	m.mu.Lock()
+	if m.attempts == nil {
+		m.attempts = make(map[int64]int)
+	}
+
+	attempt := m.attempts[body.ChatId]
+	m.attempts[body.ChatId]++
+	m.sent = append(m.sent, body.ChatId)
+	m.mu.Unlock()
+
+	code, errBody := http.StatusOK, ""
+	if m.sendFunc != nil {
+		code, errBody = m.sendFunc(body.ChatId, attempt)
+	}
+
+	resp := &client.SendMessageResponse{
+		HTTPResponse: &http.Response{StatusCode: code, Status: http.StatusText(code)},
+	}
+
+	if code != http.StatusOK {
+		resp.Body = []byte(errBody)
+
+		return resp, nil
+	}
+
+	resp.JSON200 = &struct {
+		Ok     client.SendMessage200Ok `json:"ok"`
+		Result client.Message          `json:"result"`
+	}{Ok: true, Result: client.Message{Chat: client.Chat{Id: body.ChatId}}}
+
+	return resp, nil
+}
+
+func (m *mockClient) sentTo() []int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.sent)
+}
+
+func chats(ids ...int64) iter.Seq2[int64, error] {
+	return func(yield func(int64, error) bool) {
+		for _, id := range ids {
+			if !yield(id, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestRunClassifiesFailures(t *testing.T) {
+	t.Parallel()
+
+	api := &mockClient{sendFunc: func(chatID int64, _ int) (int, string) {
+		switch chatID {
+		case 2:
+			return http.StatusForbidden, `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`
+		case 3:
+			return http.StatusForbidden, `{"ok":false,"error_code":403,"description":"Forbidden: user is deactivated"}`
+		case 4:
+			return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`
+		case 5:
+			return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: message text is empty"}`
+		default:
+			return http.StatusOK, ""
+		}
+	}}
+
+	var (
+		mu          sync.Mutex
+		unreachable = make(map[int64]broadcast.Outcome)
+	)
+
+	b, err := broadcast.New(broadcast.NewOptions(respond.New(api),
+		broadcast.WithRate(1000),
+		broadcast.WithOnUnreachable(func(_ context.Context, chatID int64, outcome broadcast.Outcome) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			unreachable[chatID] = outcome
+		}),
+	))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	stats, err := b.Run(context.Background(), "news", chats(1, 2, 3, 4, 5, 6), broadcast.Text("hello"))
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	want := broadcast.Stats{Sent: 2, Blocked: 1, Deactivated: 1, ChatNotFound: 1, Failed: 1}
+	if stats != want {
+		t.Fatalf("Run() stats=%+v, want %+v", stats, want)
+	}
+
+	if len(unreachable) != 3 || unreachable[2] != broadcast.OutcomeBlocked || unreachable[4] != broadcast.OutcomeChatNotFound {
+		t.Fatalf("unreachable=%v, want chats 2, 3 and 4", unreachable)
+	}
+}
+
+func TestRunRetriesFloodLimits(t *testing.T) {
+	t.Parallel()
+
+	api := &mockClient{sendFunc: func(_ int64, attempt int) (int, string) {
+		if attempt == 0 {
+			return http.StatusTooManyRequests,
+				`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`
+		}
+
+		return http.StatusOK, ""
+	}}
+
+	b, err := broadcast.New(broadcast.NewOptions(respond.New(api), broadcast.WithRate(1000)))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	stats, err := b.Run(context.Background(), "news", chats(1), broadcast.Text("hello"))
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	if stats.Sent != 1 || stats.Retries != 1 {
+		t.Fatalf("Run() stats=%+v, want 1 sent after 1 retry", stats)
+	}
+}
+
+func TestRunLeavesFloodRetriesToThrottle(t *testing.T) {
+	t.Parallel()
+
+	api := &mockClient{sendFunc: func(_ int64, _ int) (int, string) {
+		return http.StatusTooManyRequests,
+			`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`
+	}}
+
+	b, err := broadcast.New(broadcast.NewOptions(respond.New(api),
+		broadcast.WithRate(1000),
+		broadcast.WithThrottled(true),
+	))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	stats, err := b.Run(context.Background(), "news", chats(1), broadcast.Text("hello"))
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	if stats.Failed != 1 || stats.Retries != 0 || len(api.sentTo()) != 1 {
+		t.Fatalf("Run() stats=%+v after %d sends, want 1 failed without retries", stats, len(api.sentTo()))
+	}
+}
+
+func TestRunIsNotDoneWhenCanceledDuringLastBatch(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	store := progressstore.NewInMemoryProgressStore()
+
+	b, err := broadcast.New(broadcast.NewOptions(respond.New(&mockClient{}),
+		broadcast.WithStore(store),
+		broadcast.WithRate(1000),
+		broadcast.WithWorkers(1),
+	))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	var (
+		sent        []int64
+		interrupted bool
+	)
+
+	send := func(ctx context.Context, _ *respond.Responder, chatID int64) error {
+		if chatID == 3 && !interrupted {
+			// Every recipient was handed out; the last one is still in flight.
+			interrupted = true
+
+			cancel()
+			<-ctx.Done()
+
+			return ctx.Err()
+		}
+
+		sent = append(sent, chatID)
+
+		return nil
+	}
+
+	if _, err := b.Run(ctx, "news", chats(1, 2, 3), send); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error=%v, want context.Canceled", err)
+	}
+
+	progress, err := store.Load(context.Background(), "news")
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if progress.Done || progress.Position != 2 {
+		t.Fatalf("saved progress=%+v, want not done at position 2", progress)
+	}
+
+	stats, err := b.Run(context.Background(), "news", chats(1, 2, 3), send)
+	if err != nil {
+		t.Fatalf("Run() resumed unexpected error: %v", err)
+	}
+	if stats.Sent != 3 || !slices.Equal(sent, []int64{1, 2, 3}) {
+		t.Fatalf("Run() resumed stats=%+v, sent to %v, want all 3 sent once", stats, sent)
+	}
+}
+
+func TestRunSavesProgressInOrder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu        sync.Mutex
+		positions []int
+	)
+
+	b, err := broadcast.New(broadcast.NewOptions(respond.New(&mockClient{}),
+		broadcast.WithRate(1000),
+		broadcast.WithWorkers(8),
+		broadcast.WithCheckpointEvery(1),
+		broadcast.WithOnProgress(func(progress broadcast.Progress) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			positions = append(positions, progress.Position)
+		}),
+	))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	if _, err := b.Run(context.Background(), "news", chats(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), broadcast.Text("hello")); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	if !slices.IsSorted(positions) || positions[len(positions)-1] != 10 {
+		t.Fatalf("saved positions=%v, want them in order up to 10", positions)
+	}
+}
+
+func TestRunResumesAfterCancellation(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	api := &mockClient{sendFunc: func(chatID int64, _ int) (int, string) {
+		if chatID == 3 {
+			cancel()
+
+			return http.StatusInternalServerError, `{"ok":false,"error_code":500,"description":"Internal Server Error"}`
+		}
+
+		return http.StatusOK, ""
+	}}
+
+	store := progressstore.NewInMemoryProgressStore()
+
+	var reports []broadcast.Progress
+
+	b, err := broadcast.New(broadcast.NewOptions(respond.New(api),
+		broadcast.WithStore(store),
+		broadcast.WithRate(1000),
+		broadcast.WithWorkers(1),
+		broadcast.WithOnProgress(func(progress broadcast.Progress) {
+			reports = append(reports, progress)
+		}),
+	))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	recipients := chats(1, 2, 3, 4, 5)
+
+	stats, err := b.Run(ctx, "news", recipients, broadcast.Text("hello"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error=%v, want context.Canceled", err)
+	}
+	if stats.Sent != 2 {
+		t.Fatalf("Run() stats=%+v, want 2 sent before cancellation", stats)
+	}
+
+	api.sendFunc = nil
+
+	stats, err = b.Run(context.Background(), "news", recipients, broadcast.Text("hello"))
+	if err != nil {
+		t.Fatalf("Run() resumed unexpected error: %v", err)
+	}
+	if stats.Sent != 5 {
+		t.Fatalf("Run() resumed stats=%+v, want 5 sent", stats)
+	}
+
+	// A finished broadcast is not sent again.
+	if _, err := b.Run(context.Background(), "news", recipients, broadcast.Text("hello")); err != nil {
+		t.Fatalf("Run() finished unexpected error: %v", err)
+	}
+
+	if got, want := api.sentTo(), []int64{1, 2, 3, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Fatalf("sent to %v, want %v", got, want)
+	}
+
+	last := reports[len(reports)-1]
+	if !last.Done || last.Position != 5 {
+		t.Fatalf("last progress=%+v, want done at position 5", last)
+	}
+}
@@ -0,0 +1,134 @@
+// Code generated by options-gen v0.55.3. DO NOT EDIT.
+
+package broadcast
+
+import (
+	"context"
+	fmt461e464ebed9 "fmt"
+
+	errors461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/errors"
+	validator461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/validator"
+	"github.com/tgbotkit/runtime/logger"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+type OptOptionsSetter func(o *Options)
+
+func NewOptions(
+	responder *respond.Responder,
+	options ...OptOptionsSetter,
+) Options {
+	var o Options
+
+	// Setting defaults from field tag (if present)
+
+	o.rate = 25
+	o.workers = 4
+	o.maxRetries = 3
+	o.checkpointEvery = 100
+
+	o.responder = responder
+
+	for _, opt := range options {
+		opt(&o)
+	}
+	return o
+}
+
+// store persists progress so that an interrupted broadcast resumes where
+// it stopped. Without it, a broadcast always starts from the beginning.
+func WithStore(opt ProgressStore) OptOptionsSetter {
+	return func(o *Options) { o.store = opt }
+}
+
+// rate is how many messages are sent per second. Defaults to 25, below
+// Telegram's limit of about 30 per second.
+func WithRate(opt int) OptOptionsSetter {
+	return func(o *Options) { o.rate = opt }
+}
+
+// workers is how many sends are in flight at once.
+func WithWorkers(opt int) OptOptionsSetter {
+	return func(o *Options) { o.workers = opt }
+}
+
+// maxRetries is how many times a send rejected by a flood limit, a server
+// error or a network failure is retried.
+func WithMaxRetries(opt int) OptOptionsSetter {
+	return func(o *Options) { o.maxRetries = opt }
+}
+
+// throttled tells that the responder sends through a throttle.Limiter,
+// which already waits out and requeues the sends rejected by a flood
+// limit. Those that still fail are then not retried again.
+func WithThrottled(opt bool) OptOptionsSetter {
+	return func(o *Options) { o.throttled = opt }
+}
+
+// checkpointEvery is how many recipients are processed between saves of
+// the progress.
+func WithCheckpointEvery(opt int) OptOptionsSetter {
+	return func(o *Options) { o.checkpointEvery = opt }
+}
+
+// onProgress is called with the progress after every save and when the
+// broadcast ends.
+func WithOnProgress(opt func(Progress)) OptOptionsSetter {
+	return func(o *Options) { o.onProgress = opt }
+}
+
+// onUnreachable is called for every recipient the bot can no longer
+// write to, such as to forget users who blocked the bot.
+func WithOnUnreachable(opt func(ctx context.Context, chatID int64, outcome Outcome)) OptOptionsSetter {
+	return func(o *Options) { o.onUnreachable = opt }
+}
+
+// logger is the logger to use.
+func WithLogger(opt logger.Logger) OptOptionsSetter {
+	return func(o *Options) { o.logger = opt }
+}
+
+func (o *Options) Validate() error {
+	errs := new(errors461e464ebed9.ValidationErrors)
+	errs.Add(errors461e464ebed9.NewValidationError("responder", _validate_Options_responder(o)))
+	errs.Add(errors461e464ebed9.NewValidationError("rate", _validate_Options_rate(o)))
+	errs.Add(errors461e464ebed9.NewValidationError("workers", _validate_Options_workers(o)))
+	errs.Add(errors461e464ebed9.NewValidationError("maxRetries", _validate_Options_maxRetries(o)))
+	errs.Add(errors461e464ebed9.NewValidationError("checkpointEvery", _validate_Options_checkpointEvery(o)))
+	return errs.AsError()
+}
+
+func _validate_Options_responder(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.responder, "required"); err != nil {
+		return fmt461e464ebed9.Errorf("field `responder` did not pass the test: %w", err)
+	}
+	return nil
+}
+
+func _validate_Options_rate(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.rate, "gt=0"); err != nil {
+		return fmt461e464ebed9.Errorf("field `rate` did not pass the test: %w", err)
+	}
+	return nil
+}
+
+func _validate_Options_workers(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.workers, "gt=0"); err != nil {
+		return fmt461e464ebed9.Errorf("field `workers` did not pass the test: %w", err)
+	}
+	return nil
+}
+
+func _validate_Options_maxRetries(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.maxRetries, "gte=0"); err != nil {
+		return fmt461e464ebed9.Errorf("field `maxRetries` did not pass the test: %w", err)
+	}
+	return nil
+}
+
+func _validate_Options_checkpointEvery(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.checkpointEvery, "gt=0"); err != nil {
+		return fmt461e464ebed9.Errorf("field `checkpointEvery` did not pass the test: %w", err)
+	}
+	return nil
+}
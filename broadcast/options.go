@@ -0,0 +1,42 @@
+package broadcast
+
+import (
+	"context"
+
+	"github.com/tgbotkit/runtime/logger"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+//go:generate go tool options-gen -out-filename=options.gen.go -from-struct=Options
+
+// Options is the options for the Broadcaster.
+type Options struct {
+	// responder sends the messages.
+	responder *respond.Responder `option:"mandatory" validate:"required"`
+	// store persists progress so that an interrupted broadcast resumes where
+	// it stopped. Without it, a broadcast always starts from the beginning.
+	store ProgressStore
+	// rate is how many messages are sent per second. Defaults to 25, below
+	// Telegram's limit of about 30 per second.
+	rate int `default:"25" validate:"gt=0"`
+	// workers is how many sends are in flight at once.
+	workers int `default:"4" validate:"gt=0"`
+	// maxRetries is how many times a send rejected by a flood limit, a server
+	// error or a network failure is retried.
+	maxRetries int `default:"3" validate:"gte=0"`
+	// throttled tells that the responder sends through a throttle.Limiter,
+	// which already waits out and requeues the sends rejected by a flood
+	// limit. Those that still fail are then not retried again.
+	throttled bool
+	// checkpointEvery is how many recipients are processed between saves of
+	// the progress.
+	checkpointEvery int `default:"100" validate:"gt=0"`
+	// onProgress is called with the progress after every save and when the
+	// broadcast ends.
+	onProgress func(Progress)
+	// onUnreachable is called for every recipient the bot can no longer
+	// write to, such as to forget users who blocked the bot.
+	onUnreachable func(ctx context.Context, chatID int64, outcome Outcome)
+	// logger is the logger to use.
+	logger logger.Logger
+}
@@ -0,0 +1,108 @@
+package broadcast
+
+import "context"
+
+// Outcome is the result of sending the broadcast to one recipient.
+type Outcome int
+
+// Outcomes of a send.
+const (
+	// OutcomeSent means the message was delivered.
+	OutcomeSent Outcome = iota
+	// OutcomeBlocked means the user blocked the bot.
+	OutcomeBlocked
+	// OutcomeDeactivated means the user deleted their account.
+	OutcomeDeactivated
+	// OutcomeChatNotFound means the chat does not exist or the bot never
+	// talked to the user.
+	OutcomeChatNotFound
+	// OutcomeKicked means the bot was removed from the group or channel.
+	OutcomeKicked
+	// OutcomeFailed means the send failed for another reason, after any
+	// retries.
+	OutcomeFailed
+)
+
+// String returns the name of the outcome.
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSent:
+		return "sent"
+	case OutcomeBlocked:
+		return "blocked"
+	case OutcomeDeactivated:
+		return "deactivated"
+	case OutcomeChatNotFound:
+		return "chat not found"
+	case OutcomeKicked:
+		return "kicked"
+	case OutcomeFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// Unreachable reports whether the bot can no longer write to the recipient.
+func (o Outcome) Unreachable() bool {
+	switch o {
+	case OutcomeBlocked, OutcomeDeactivated, OutcomeChatNotFound, OutcomeKicked:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stats counts the outcomes of a broadcast.
+type Stats struct {
+	Sent         int `json:"sent"`
+	Blocked      int `json:"blocked"`
+	Deactivated  int `json:"deactivated"`
+	ChatNotFound int `json:"chat_not_found"`
+	Kicked       int `json:"kicked"`
+	Failed       int `json:"failed"`
+	// Retries counts sends repeated after a flood limit, a server error or
+	// a network failure.
+	Retries int `json:"retries"`
+}
+
+// Processed returns how many recipients have an outcome.
+func (s Stats) Processed() int {
+	return s.Sent + s.Blocked + s.Deactivated + s.ChatNotFound + s.Kicked + s.Failed
+}
+
+func (s *Stats) add(outcome Outcome) {
+	switch outcome {
+	case OutcomeSent:
+		s.Sent++
+	case OutcomeBlocked:
+		s.Blocked++
+	case OutcomeDeactivated:
+		s.Deactivated++
+	case OutcomeChatNotFound:
+		s.ChatNotFound++
+	case OutcomeKicked:
+		s.Kicked++
+	case OutcomeFailed:
+		s.Failed++
+	}
+}
+
+// Progress is how far a broadcast got.
+type Progress struct {
+	// Position is how many recipients, in the order of the recipient
+	// iterator, were processed. A resumed broadcast skips them.
+	Position int `json:"position"`
+	// Stats counts the outcomes of the processed recipients.
+	Stats Stats `json:"stats"`
+	// Done reports whether every recipient was processed.
+	Done bool `json:"done"`
+}
+
+// ProgressStore persists the progress of broadcasts by ID.
+type ProgressStore interface {
+	// Load returns the progress saved for id, or a zero Progress.
+	Load(ctx context.Context, id string) (Progress, error)
+	// Save records the progress of id.
+	Save(ctx context.Context, id string, progress Progress) error
+}
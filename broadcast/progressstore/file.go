@@ -0,0 +1,114 @@
+package progressstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tgbotkit/runtime/broadcast"
+)
+
+// FileProgressStore is a ProgressStore that persists the progress of all
+// broadcasts to a JSON file, so that they resume after a restart.
+type FileProgressStore struct {
+	mu       sync.Mutex
+	path     string
+	progress map[string]broadcast.Progress
+}
+
+var _ broadcast.ProgressStore = (*FileProgressStore)(nil)
+
+// NewFileProgressStore creates a new FileProgressStore backed by path,
+// loading any previously persisted progress.
+func NewFileProgressStore(path string) (*FileProgressStore, error) {
+	s := &FileProgressStore{
+		path:     path,
+		progress: make(map[string]broadcast.Progress),
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Load returns the progress saved for id, or a zero Progress.
+func (s *FileProgressStore) Load(_ context.Context, id string) (broadcast.Progress, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.progress[id], nil
+}
+
+// Save records the progress of id. It is written to disk before Save
+// returns.
+func (s *FileProgressStore) Save(_ context.Context, id string, progress broadcast.Progress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.progress[id] = progress
+
+	return s.persist()
+}
+
+func (s *FileProgressStore) load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("read progress store: %w", err)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &s.progress); err != nil {
+		return fmt.Errorf("decode progress store: %w", err)
+	}
+
+	return nil
+}
+
+// persist atomically replaces the store file with the current progress.
+func (s *FileProgressStore) persist() error {
+	data, err := json.Marshal(s.progress)
+	if err != nil {
+		return fmt.Errorf("encode progress store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create progress store temp file: %w", err)
+	}
+
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+
+		return fmt.Errorf("write progress store: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+
+		return fmt.Errorf("close progress store: %w", err)
+	}
+
+	if err := os.Rename(tmpName, s.path); err != nil {
+		_ = os.Remove(tmpName)
+
+		return fmt.Errorf("replace progress store: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package progressstore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/tgbotkit/runtime/broadcast"
+)
+
+func TestFileProgressStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.json")
+	ctx := context.Background()
+
+	store, err := NewFileProgressStore(path)
+	if err != nil {
+		t.Fatalf("NewFileProgressStore() error = %v", err)
+	}
+
+	progress := broadcast.Progress{Position: 7, Stats: broadcast.Stats{Sent: 6, Blocked: 1}}
+	if err := store.Save(ctx, "news", progress); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A new store over the same file resumes the saved progress.
+	reopened, err := NewFileProgressStore(path)
+	if err != nil {
+		t.Fatalf("NewFileProgressStore() reopen error = %v", err)
+	}
+
+	got, err := reopened.Load(ctx, "news")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got != progress {
+		t.Errorf("Load() got = %+v, want %+v", got, progress)
+	}
+
+	if got, _ := reopened.Load(ctx, "other"); got != (broadcast.Progress{}) {
+		t.Errorf("Load() unknown id got = %+v, want zero progress", got)
+	}
+}
@@ -0,0 +1,42 @@
+// Package progressstore provides broadcast.ProgressStore implementations.
+package progressstore
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tgbotkit/runtime/broadcast"
+)
+
+// InMemoryProgressStore keeps the progress of broadcasts in memory. It is
+// lost on restart, so it only resumes broadcasts run again by the same
+// process, such as after a cancellation.
+type InMemoryProgressStore struct {
+	mu       sync.RWMutex
+	progress map[string]broadcast.Progress
+}
+
+var _ broadcast.ProgressStore = (*InMemoryProgressStore)(nil)
+
+// NewInMemoryProgressStore creates a new InMemoryProgressStore.
+func NewInMemoryProgressStore() *InMemoryProgressStore {
+	return &InMemoryProgressStore{progress: make(map[string]broadcast.Progress)}
+}
+
+// Load returns the progress saved for id, or a zero Progress.
+func (s *InMemoryProgressStore) Load(_ context.Context, id string) (broadcast.Progress, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.progress[id], nil
+}
+
+// Save records the progress of id.
+func (s *InMemoryProgressStore) Save(_ context.Context, id string, progress broadcast.Progress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.progress[id] = progress
+
+	return nil
+}
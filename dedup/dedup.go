@@ -0,0 +1,74 @@
+// Package dedup drops Telegram updates that were already delivered to the bot.
+//
+// Telegram redelivers webhook updates after timeouts or non-2xx responses, and the
+// poller can redeliver a batch when saving the offset fails. A Deduplicator remembers
+// recently seen update IDs in a SeenStore so handlers run once per update.
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+// SeenStore records update IDs that have already been processed.
+type SeenStore interface {
+	// MarkSeen records updateID and reports whether it had been recorded before.
+	MarkSeen(ctx context.Context, updateID int) (bool, error)
+}
+
+// Deduplicator filters out redelivered updates.
+type Deduplicator struct {
+	opts    Options
+	log     logger.Logger
+	dropped atomic.Uint64
+}
+
+// New creates a new Deduplicator with the given options.
+func New(opts Options) (*Deduplicator, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid deduplicator options: %w", err)
+	}
+
+	if opts.logger == nil {
+		opts.logger = logger.NewNop()
+	}
+
+	return &Deduplicator{
+		opts: opts,
+		log:  opts.logger,
+	}, nil
+}
+
+// IsDuplicate records the update and reports whether it should be dropped.
+// Store failures are logged and the update is let through: a rare duplicate
+// is preferable to a lost update.
+func (d *Deduplicator) IsDuplicate(ctx context.Context, update *client.Update) bool {
+	if update == nil {
+		return false
+	}
+
+	seen, err := d.opts.store.MarkSeen(ctx, update.UpdateId)
+	if err != nil {
+		if ctx.Err() == nil {
+			d.log.Errorf("mark update %d seen: %v", update.UpdateId, err)
+		}
+
+		return false
+	}
+
+	if seen {
+		d.dropped.Add(1)
+		d.log.Debugf("dropping duplicate update: %v", update.UpdateId)
+	}
+
+	return seen
+}
+
+// Dropped returns the number of duplicate updates dropped so far.
+func (d *Deduplicator) Dropped() uint64 {
+	return d.dropped.Load()
+}
@@ -0,0 +1,62 @@
+package dedup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/dedup"
+	"github.com/tgbotkit/runtime/dedup/seenstore"
+)
+
+type failingStore struct{}
+
+func (failingStore) MarkSeen(_ context.Context, _ int) (bool, error) {
+	return false, errors.New("store down")
+}
+
+func TestDeduplicator(t *testing.T) {
+	d, err := dedup.New(dedup.NewOptions(seenstore.NewInMemorySeenStore(0, 10)))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if d.IsDuplicate(ctx, &client.Update{UpdateId: 1}) {
+		t.Fatal("first delivery reported as duplicate")
+	}
+	if !d.IsDuplicate(ctx, &client.Update{UpdateId: 1}) {
+		t.Fatal("redelivery not reported as duplicate")
+	}
+	if d.IsDuplicate(ctx, &client.Update{UpdateId: 2}) {
+		t.Fatal("new update reported as duplicate")
+	}
+	if d.IsDuplicate(ctx, nil) {
+		t.Fatal("nil update reported as duplicate")
+	}
+	if got := d.Dropped(); got != 1 {
+		t.Fatalf("Dropped()=%d, want 1", got)
+	}
+}
+
+func TestDeduplicatorStoreErrorLetsUpdateThrough(t *testing.T) {
+	d, err := dedup.New(dedup.NewOptions(failingStore{}))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	if d.IsDuplicate(context.Background(), &client.Update{UpdateId: 1}) {
+		t.Fatal("update dropped on store error")
+	}
+	if got := d.Dropped(); got != 0 {
+		t.Fatalf("Dropped()=%d, want 0", got)
+	}
+}
+
+func TestNewRequiresStore(t *testing.T) {
+	if _, err := dedup.New(dedup.NewOptions(nil)); err == nil {
+		t.Fatal("New() error is nil, want validation error")
+	}
+}
@@ -0,0 +1,47 @@
+// Code generated by options-gen v0.55.3. DO NOT EDIT.
+
+package dedup
+
+import (
+	fmt461e464ebed9 "fmt"
+
+	errors461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/errors"
+	validator461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/validator"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+type OptOptionsSetter func(o *Options)
+
+func NewOptions(
+	store SeenStore,
+	options ...OptOptionsSetter,
+) Options {
+	var o Options
+
+	// Setting defaults from field tag (if present)
+
+	o.store = store
+
+	for _, opt := range options {
+		opt(&o)
+	}
+	return o
+}
+
+// logger is the logger to use.
+func WithLogger(opt logger.Logger) OptOptionsSetter {
+	return func(o *Options) { o.logger = opt }
+}
+
+func (o *Options) Validate() error {
+	errs := new(errors461e464ebed9.ValidationErrors)
+	errs.Add(errors461e464ebed9.NewValidationError("store", _validate_Options_store(o)))
+	return errs.AsError()
+}
+
+func _validate_Options_store(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.store, "required"); err != nil {
+		return fmt461e464ebed9.Errorf("field `store` did not pass the test: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package dedup
+
+import "github.com/tgbotkit/runtime/logger"
+
+//go:generate go tool options-gen -out-filename=options.gen.go -from-struct=Options
+
+// Options is the options for the Deduplicator.
+type Options struct {
+	// store is the seen-set store used to remember update IDs.
+	store SeenStore `option:"mandatory" validate:"required"`
+	// logger is the logger to use.
+	logger logger.Logger
+}
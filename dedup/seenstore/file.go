@@ -0,0 +1,119 @@
+package seenstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tgbotkit/runtime/dedup"
+)
+
+// FileSeenStore is a SeenStore that persists its window to a JSON file so that
+// redeliveries are still detected after a restart.
+type FileSeenStore struct {
+	mu     sync.Mutex
+	path   string
+	window *window
+	now    func() time.Time
+}
+
+var _ dedup.SeenStore = (*FileSeenStore)(nil)
+
+// NewFileSeenStore creates a new FileSeenStore backed by path, loading any
+// previously persisted entries. The ttl and capacity bounds behave as in
+// NewInMemorySeenStore.
+func NewFileSeenStore(path string, ttl time.Duration, capacity int) (*FileSeenStore, error) {
+	s := &FileSeenStore{
+		path:   path,
+		window: newWindow(ttl, capacity),
+		now:    time.Now,
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// MarkSeen records updateID and reports whether it had been recorded before.
+// New IDs are written to disk before MarkSeen returns.
+func (s *FileSeenStore) MarkSeen(_ context.Context, updateID int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.window.markSeen(updateID, s.now()) {
+		return true, nil
+	}
+
+	if err := s.persist(); err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (s *FileSeenStore) load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("read seen store: %w", err)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	var entries []seenEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("decode seen store: %w", err)
+	}
+
+	s.window.restore(entries, s.now())
+
+	return nil
+}
+
+// persist atomically replaces the store file with the current window.
+func (s *FileSeenStore) persist() error {
+	data, err := json.Marshal(s.window.entries())
+	if err != nil {
+		return fmt.Errorf("encode seen store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create seen store temp file: %w", err)
+	}
+
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+
+		return fmt.Errorf("write seen store: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+
+		return fmt.Errorf("close seen store: %w", err)
+	}
+
+	if err := os.Rename(tmpName, s.path); err != nil {
+		_ = os.Remove(tmpName)
+
+		return fmt.Errorf("replace seen store: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package seenstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileSeenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seen.json")
+
+	store, err := NewFileSeenStore(path, time.Hour, 10)
+	if err != nil {
+		t.Fatalf("NewFileSeenStore() error = %v", err)
+	}
+
+	assertMarkSeen(t, store.MarkSeen, 1, false)
+	assertMarkSeen(t, store.MarkSeen, 2, false)
+	assertMarkSeen(t, store.MarkSeen, 1, true)
+
+	// A new store over the same file remembers earlier updates.
+	reopened, err := NewFileSeenStore(path, time.Hour, 10)
+	if err != nil {
+		t.Fatalf("NewFileSeenStore() reopen error = %v", err)
+	}
+
+	assertMarkSeen(t, reopened.MarkSeen, 2, true)
+	assertMarkSeen(t, reopened.MarkSeen, 3, false)
+}
+
+func TestFileSeenStoreDropsExpiredEntriesOnLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seen.json")
+
+	store, err := NewFileSeenStore(path, time.Minute, 0)
+	if err != nil {
+		t.Fatalf("NewFileSeenStore() error = %v", err)
+	}
+
+	assertMarkSeen(t, store.MarkSeen, 1, false)
+
+	reopened := &FileSeenStore{
+		path:   path,
+		window: newWindow(time.Minute, 0),
+		now:    func() time.Time { return time.Now().Add(time.Hour) },
+	}
+	if err := reopened.load(); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	assertMarkSeen(t, reopened.MarkSeen, 1, false)
+}
+
+func TestFileSeenStoreInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seen.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := NewFileSeenStore(path, time.Minute, 0); err == nil {
+		t.Fatal("NewFileSeenStore() error is nil, want decode error")
+	}
+}
@@ -0,0 +1,37 @@
+package seenstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tgbotkit/runtime/dedup"
+)
+
+// InMemorySeenStore is an in-memory implementation of SeenStore that remembers
+// update IDs for a limited time and up to a limited count.
+type InMemorySeenStore struct {
+	mu     sync.Mutex
+	window *window
+	now    func() time.Time
+}
+
+var _ dedup.SeenStore = (*InMemorySeenStore)(nil)
+
+// NewInMemorySeenStore creates a new InMemorySeenStore. Entries expire after ttl
+// and the oldest entries are evicted once more than capacity IDs are stored.
+// A non-positive ttl or capacity disables that bound.
+func NewInMemorySeenStore(ttl time.Duration, capacity int) *InMemorySeenStore {
+	return &InMemorySeenStore{
+		window: newWindow(ttl, capacity),
+		now:    time.Now,
+	}
+}
+
+// MarkSeen records updateID and reports whether it had been recorded before.
+func (s *InMemorySeenStore) MarkSeen(_ context.Context, updateID int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.window.markSeen(updateID, s.now()), nil
+}
@@ -0,0 +1,43 @@
+package seenstore
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInMemorySeenStore(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	store := NewInMemorySeenStore(time.Minute, 2)
+	store.now = func() time.Time { return now }
+
+	assertMarkSeen(t, store.MarkSeen, 1, false)
+	assertMarkSeen(t, store.MarkSeen, 1, true)
+
+	// Capacity evicts the oldest entry.
+	assertMarkSeen(t, store.MarkSeen, 2, false)
+	assertMarkSeen(t, store.MarkSeen, 3, false)
+	assertMarkSeen(t, store.MarkSeen, 1, false)
+
+	// TTL expires every remaining entry.
+	now = now.Add(time.Minute)
+	assertMarkSeen(t, store.MarkSeen, 3, false)
+}
+
+func assertMarkSeen(
+	t *testing.T,
+	markSeen func(context.Context, int) (bool, error),
+	updateID int,
+	want bool,
+) {
+	t.Helper()
+
+	got, err := markSeen(context.Background(), updateID)
+	if err != nil {
+		t.Fatalf("MarkSeen(%d) error = %v", updateID, err)
+	}
+	if got != want {
+		t.Fatalf("MarkSeen(%d) got = %v, want %v", updateID, got, want)
+	}
+}
@@ -0,0 +1,96 @@
+// Package seenstore provides implementations of dedup.SeenStore.
+package seenstore
+
+import "time"
+
+// window is a bounded, time-limited set of update IDs kept in insertion order.
+type window struct {
+	ttl      time.Duration
+	capacity int
+	seen     map[int]time.Time
+	order    []seenEntry
+}
+
+type seenEntry struct {
+	UpdateID int       `json:"update_id"`
+	SeenAt   time.Time `json:"seen_at"`
+}
+
+func newWindow(ttl time.Duration, capacity int) *window {
+	return &window{
+		ttl:      ttl,
+		capacity: capacity,
+		seen:     make(map[int]time.Time),
+	}
+}
+
+// markSeen records updateID at now and reports whether it was already present.
+func (w *window) markSeen(updateID int, now time.Time) bool {
+	w.evict(now)
+
+	if _, ok := w.seen[updateID]; ok {
+		return true
+	}
+
+	w.seen[updateID] = now
+	w.order = append(w.order, seenEntry{UpdateID: updateID, SeenAt: now})
+
+	if w.capacity > 0 && len(w.order) > w.capacity {
+		w.drop(len(w.order) - w.capacity)
+	}
+
+	return false
+}
+
+// evict removes entries older than the TTL. A non-positive TTL keeps entries
+// until they are pushed out by capacity.
+func (w *window) evict(now time.Time) {
+	if w.ttl <= 0 {
+		return
+	}
+
+	expired := 0
+
+	for _, entry := range w.order {
+		if now.Sub(entry.SeenAt) < w.ttl {
+			break
+		}
+
+		expired++
+	}
+
+	w.drop(expired)
+}
+
+func (w *window) drop(n int) {
+	if n <= 0 {
+		return
+	}
+
+	for _, entry := range w.order[:n] {
+		delete(w.seen, entry.UpdateID)
+	}
+
+	w.order = append(w.order[:0], w.order[n:]...)
+}
+
+func (w *window) entries() []seenEntry {
+	return append([]seenEntry(nil), w.order...)
+}
+
+func (w *window) restore(entries []seenEntry, now time.Time) {
+	for _, entry := range entries {
+		if _, ok := w.seen[entry.UpdateID]; ok {
+			continue
+		}
+
+		w.seen[entry.UpdateID] = entry.SeenAt
+		w.order = append(w.order, entry)
+	}
+
+	if w.capacity > 0 && len(w.order) > w.capacity {
+		w.drop(len(w.order) - w.capacity)
+	}
+
+	w.evict(now)
+}
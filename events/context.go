@@ -0,0 +1,22 @@
+package events
+
+import (
+	"context"
+
+	"github.com/tgbotkit/client"
+)
+
+type updateKey struct{}
+
+// WithUpdate returns a copy of ctx carrying update. The bot puts the update
+// it handles into the context of every event the update causes.
+func WithUpdate(ctx context.Context, update *client.Update) context.Context {
+	return context.WithValue(ctx, updateKey{}, update)
+}
+
+// UpdateFromContext returns the update carried by ctx, or nil.
+func UpdateFromContext(ctx context.Context) *client.Update {
+	update, _ := ctx.Value(updateKey{}).(*client.Update)
+
+	return update
+}
@@ -5,6 +5,9 @@ package events
 const (
 	// OnUpdate is emitted when a new update is received from Telegram.
 	OnUpdate = "onUpdate"
+	// OnUnhandledUpdate is emitted with the UpdateEvent when the update carried no
+	// field the classifier knows about, e.g. a kind added in a newer Bot API version.
+	OnUnhandledUpdate = "onUnhandledUpdate"
 	// OnMessage is emitted when a new message is received, regardless of its type.
 	// The specific type is available in the MessageEvent.Type field.
 	OnMessage = "onMessage"
@@ -20,6 +23,8 @@ const (
 	OnEditedBusinessMessage = "onEditedBusinessMessage"
 	// OnGuestMessage is emitted when a guest message is received.
 	OnGuestMessage = "onGuestMessage"
+	// OnChatMigrated is emitted when a group is upgraded to a supergroup.
+	OnChatMigrated = "onChatMigrated"
 	// OnCommand is emitted when a command is received from a text message.
 	OnCommand = "onCommand"
 	// OnCallbackQuery is emitted when a callback query is received.
@@ -32,10 +37,16 @@ const (
 	OnShippingQuery = "onShippingQuery"
 	// OnPreCheckoutQuery is emitted when a pre-checkout query is received.
 	OnPreCheckoutQuery = "onPreCheckoutQuery"
+	// OnSuccessfulPayment is emitted when a message reports a successful payment.
+	OnSuccessfulPayment = "onSuccessfulPayment"
+	// OnRefundedPayment is emitted when a message reports a refunded payment.
+	OnRefundedPayment = "onRefundedPayment"
 	// OnPoll is emitted when a poll update is received.
 	OnPoll = "onPoll"
 	// OnPollAnswer is emitted when a poll answer update is received.
 	OnPollAnswer = "onPollAnswer"
+	// OnPollClosed is emitted by a polls.Tracker when a poll the bot sent is closed.
+	OnPollClosed = "onPollClosed"
 	// OnChatMember is emitted when a chat member update is received.
 	OnChatMember = "onChatMember"
 	// OnMyChatMember is emitted when the bot's chat member state changes.
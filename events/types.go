@@ -1,6 +1,8 @@
 package events
 
 import (
+	"encoding/json"
+
 	"github.com/tgbotkit/client"
 	"github.com/tgbotkit/runtime/messagetype"
 )
@@ -9,6 +11,9 @@ import (
 type UpdateEvent struct {
 	// Update is the received update.
 	Update *client.Update
+	// Raw is the JSON the update was decoded from, including fields the generated
+	// client does not know yet. It is nil when the update source does not preserve it.
+	Raw json.RawMessage
 }
 
 // MessageEvent is emitted when a new message is received.
@@ -29,6 +34,16 @@ type CommandEvent struct {
 	Args string
 }
 
+// ChatMigratedEvent is emitted when a group is upgraded to a supergroup.
+type ChatMigratedEvent struct {
+	// Message is the migration service message that triggered the event.
+	Message *client.Message
+	// FromChatID is the identifier of the original group.
+	FromChatID int64
+	// ToChatID is the identifier of the new supergroup.
+	ToChatID int64
+}
+
 // CallbackQueryEvent is emitted when a callback query is received.
 type CallbackQueryEvent struct {
 	CallbackQuery *client.CallbackQuery
@@ -54,6 +69,22 @@ type PreCheckoutQueryEvent struct {
 	PreCheckoutQuery *client.PreCheckoutQuery
 }
 
+// SuccessfulPaymentEvent is emitted when a message reports a successful payment.
+type SuccessfulPaymentEvent struct {
+	// Message is the service message carrying the payment.
+	Message *client.Message
+	// Payment is the payment reported by the message.
+	Payment *client.SuccessfulPayment
+}
+
+// RefundedPaymentEvent is emitted when a message reports a refunded payment.
+type RefundedPaymentEvent struct {
+	// Message is the service message carrying the refund.
+	Message *client.Message
+	// Payment is the refunded payment reported by the message.
+	Payment *client.RefundedPayment
+}
+
 // PollEvent is emitted when a poll update is received.
 type PollEvent struct {
 	Poll *client.Poll
@@ -64,6 +95,19 @@ type PollAnswerEvent struct {
 	PollAnswer *client.PollAnswer
 }
 
+// PollClosedEvent is emitted by a polls.Tracker when a poll the bot sent is closed.
+type PollClosedEvent struct {
+	// Poll is the final state of the poll. The voter count of each option is
+	// its tally.
+	Poll *client.Poll
+	// ChatID and MessageID identify the message of the poll.
+	ChatID    int64
+	MessageID int
+	// Votes maps each voter, a user or a chat voting on behalf of a channel,
+	// to the options it chose. It is empty for anonymous polls.
+	Votes map[int64][]int
+}
+
 // ChatMemberEvent is emitted when a chat member update is received.
 type ChatMemberEvent struct {
 	ChatMember *client.ChatMemberUpdated
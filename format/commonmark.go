@@ -0,0 +1,186 @@
+package format
+
+import (
+	"strconv"
+	"strings"
+)
+
+// commonMarkRenderer writes standard Markdown. Line starts are tracked to
+// prefix quoted lines and to escape characters that would start a block.
+type commonMarkRenderer struct {
+	buf []byte
+	// code is set inside code and code blocks, whose content is written as is.
+	code bool
+	// fence is the backtick run that closes the current inline code.
+	fence string
+	quote bool
+	// lineStart is set when nothing has been written on the current line.
+	lineStart bool
+	// blankPending is set after a quote: a blank line must end it, or the next
+	// line would continue it.
+	blankPending bool
+}
+
+var (
+	commonMarkEscaper    = newBackslashEscaper("\\`*_[]<>~|&")
+	commonMarkURLEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E")
+)
+
+var commonMarkDelimiters = map[string]string{
+	typeBold:          "**",
+	typeItalic:        "*",
+	typeStrikethrough: "~~",
+}
+
+func (r *commonMarkRenderer) text(s string) {
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			r.buf = append(r.buf, '\n')
+			r.lineStart = true
+		}
+
+		if line == "" {
+			continue
+		}
+
+		fresh := r.lineStart
+		r.startLine()
+
+		if r.code {
+			r.buf = append(r.buf, line...)
+
+			continue
+		}
+
+		line = commonMarkEscaper.Replace(line)
+		if fresh {
+			line = escapeBlockStart(line)
+		}
+
+		r.buf = append(r.buf, line...)
+	}
+}
+
+func (*commonMarkRenderer) block(t *Text) bool {
+	return t.isBlock() || t.kind == typePre
+}
+
+func (r *commonMarkRenderer) open(t *Text) {
+	switch t.kind {
+	case typeCode:
+		content := t.String()
+		r.fence = strings.Repeat("`", longestRun(content, '`')+1)
+
+		open := r.fence
+		if strings.HasPrefix(content, "`") || strings.HasSuffix(content, "`") {
+			open += " "
+		}
+
+		r.markup(open)
+		r.code = true
+	case typePre:
+		fence := strings.Repeat("`", max(longestRun(t.String(), '`')+1, 3)) //nolint:mnd // shortest code fence
+		r.fence = fence
+		r.markup(fence + t.language)
+		r.buf = append(r.buf, '\n')
+		r.lineStart = true
+		r.code = true
+	case typeTextLink, typeTextMention:
+		r.markup("[")
+	case typeBlockquote, typeExpandableBlockquote:
+		r.quote = true
+	default:
+		r.markup(commonMarkDelimiters[t.kind])
+	}
+}
+
+func (r *commonMarkRenderer) close(t *Text) {
+	switch t.kind {
+	case typeCode:
+		closing := r.fence
+		if content := t.String(); strings.HasPrefix(content, "`") || strings.HasSuffix(content, "`") {
+			closing = " " + closing
+		}
+
+		r.buf = append(r.buf, closing...)
+		r.code = false
+	case typePre:
+		if !r.lineStart {
+			r.buf = append(r.buf, '\n')
+			r.lineStart = true
+		}
+
+		r.markup(r.fence)
+		r.code = false
+	case typeTextLink:
+		r.buf = append(r.buf, "]("+commonMarkURLEscaper.Replace(t.url)+")"...)
+	case typeTextMention:
+		r.buf = append(r.buf, "](tg://user?id="+strconv.FormatInt(t.userID, 10)+")"...)
+	case typeBlockquote, typeExpandableBlockquote:
+		r.quote = false
+		r.blankPending = true
+	default:
+		r.buf = append(r.buf, commonMarkDelimiters[t.kind]...)
+	}
+}
+
+func (r *commonMarkRenderer) markup(s string) {
+	if s == "" {
+		return
+	}
+
+	r.startLine()
+	r.buf = append(r.buf, s...)
+}
+
+// startLine writes what a new line needs before its content: the blank line
+// ending a previous quote, or the prefix of a quoted line.
+func (r *commonMarkRenderer) startLine() {
+	if !r.lineStart {
+		return
+	}
+
+	if r.blankPending {
+		r.buf = append(r.buf, '\n')
+		r.blankPending = false
+	}
+
+	if r.quote {
+		r.buf = append(r.buf, "> "...)
+	}
+
+	r.lineStart = false
+}
+
+// escapeBlockStart escapes a character at the start of a line that would make
+// the line a heading, list item, quote or thematic break.
+func escapeBlockStart(line string) string {
+	if strings.IndexByte("#-+=", line[0]) >= 0 {
+		return `\` + line
+	}
+
+	digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+
+	if digits > 0 && digits < len(line) && (line[digits] == '.' || line[digits] == ')') {
+		return line[:digits] + `\` + line[digits:]
+	}
+
+	return line
+}
+
+func longestRun(s string, c byte) int {
+	longest, run := 0, 0
+
+	for i := range len(s) {
+		if s[i] != c {
+			run = 0
+
+			continue
+		}
+
+		run++
+		longest = max(longest, run)
+	}
+
+	return longest
+}
@@ -0,0 +1,210 @@
+package format
+
+import (
+	"slices"
+	"unicode"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/internal/utf16text"
+)
+
+// FromMessage builds a Text from the text or caption of message and its
+// entities. It returns empty text for a nil message or one without text.
+func FromMessage(message *client.Message) Text {
+	switch {
+	case message == nil:
+		return Text{}
+	case message.Text != nil:
+		return FromEntities(*message.Text, deref(message.Entities))
+	case message.Caption != nil:
+		return FromEntities(*message.Caption, deref(message.CaptionEntities))
+	default:
+		return Text{}
+	}
+}
+
+// FromEntities builds a Text from text and the entities Telegram reported for
+// it, so that received messages can be rendered as HTML, MarkdownV2 or
+// CommonMark. Overlapping entities are split so that they nest. Entities that
+// Telegram detects by itself, such as mentions, hashtags and URLs, become
+// plain text, as do types this package does not know.
+func FromEntities(text string, entities []client.MessageEntity) Text {
+	runes := []rune(text)
+	spans := entitySpans(runes, entities)
+
+	root := &spanNode{end: len(runes)}
+	stack := []*spanNode{root}
+
+	for len(spans) > 0 {
+		node := spans[0]
+		spans = spans[1:]
+
+		for stack[len(stack)-1].end <= node.start {
+			stack = stack[:len(stack)-1]
+		}
+
+		parent := stack[len(stack)-1]
+		if node.end > parent.end {
+			// The entity overlaps the end of its parent: the part past it is
+			// nested separately.
+			rest := *node
+			rest.start = parent.end
+
+			if rest.trim(runes) {
+				spans = insertSpan(spans, &rest)
+			}
+
+			node.end = parent.end
+			if !node.trim(runes) {
+				continue
+			}
+		}
+
+		parent.children = append(parent.children, node)
+		stack = append(stack, node)
+	}
+
+	return root.text(runes)
+}
+
+// spanNode is an entity as a range of runes, with the entities nested in it.
+type spanNode struct {
+	entity     client.MessageEntity
+	kind       string
+	start, end int
+	children   []*spanNode
+}
+
+func entitySpans(runes []rune, entities []client.MessageEntity) []*spanNode {
+	spans := make([]*spanNode, 0, len(entities))
+
+	for _, entity := range entities {
+		if !keptKinds[entity.Type] || (entity.Type == typeTextMention && entity.User == nil) {
+			continue
+		}
+
+		span := &spanNode{
+			entity: entity,
+			kind:   entity.Type,
+			start:  utf16text.RuneIndex(runes, entity.Offset),
+			end:    utf16text.RuneIndex(runes, entity.Offset+entity.Length),
+		}
+
+		if span.trim(runes) {
+			spans = append(spans, span)
+		}
+	}
+
+	slices.SortStableFunc(spans, compareSpans)
+
+	return spans
+}
+
+// trim drops whitespace at the edges of delimited styles, since styled
+// whitespace looks the same unstyled and markup around it is fragile in
+// Markdown. It reports whether the span still covers any text.
+func (n *spanNode) trim(runes []rune) bool {
+	if delimitedKinds[n.kind] {
+		for n.start < n.end && unicode.IsSpace(runes[n.start]) {
+			n.start++
+		}
+
+		for n.end > n.start && unicode.IsSpace(runes[n.end-1]) {
+			n.end--
+		}
+	}
+
+	return n.start < n.end
+}
+
+// compareSpans orders spans by start, outer spans first.
+func compareSpans(a, b *spanNode) int {
+	if a.start != b.start {
+		return a.start - b.start
+	}
+
+	return b.end - a.end
+}
+
+func insertSpan(spans []*spanNode, span *spanNode) []*spanNode {
+	i, _ := slices.BinarySearchFunc(spans, span, compareSpans)
+
+	return slices.Insert(spans, i, span)
+}
+
+func (n *spanNode) text(runes []rune) Text {
+	content := string(runes[n.start:n.end])
+
+	// These entities cannot contain others.
+	switch n.kind {
+	case typeCode:
+		return Code(content)
+	case typePre:
+		return Pre(content, deref(n.entity.Language))
+	case typeCustomEmoji:
+		return CustomEmoji(content, deref(n.entity.CustomEmojiId))
+	}
+
+	// Text before each child, the child, and a tail.
+	parts := make([]any, 0, 2*len(n.children)+1)
+	cursor := n.start
+
+	for _, child := range n.children {
+		if child.start > cursor {
+			parts = append(parts, string(runes[cursor:child.start]))
+		}
+
+		parts = append(parts, child.text(runes))
+		cursor = child.end
+	}
+
+	if cursor < n.end {
+		parts = append(parts, string(runes[cursor:n.end]))
+	}
+
+	t := span(n.kind, parts)
+
+	switch n.kind {
+	case typeTextLink:
+		t.url = deref(n.entity.Url)
+	case typeTextMention:
+		t.userID = n.entity.User.Id
+	}
+
+	return t
+}
+
+// keptKinds are the entity types FromEntities keeps; they match Text kinds.
+var keptKinds = map[string]bool{
+	typeBold:                 true,
+	typeItalic:               true,
+	typeUnderline:            true,
+	typeStrikethrough:        true,
+	typeSpoiler:              true,
+	typeCode:                 true,
+	typePre:                  true,
+	typeTextLink:             true,
+	typeTextMention:          true,
+	typeCustomEmoji:          true,
+	typeBlockquote:           true,
+	typeExpandableBlockquote: true,
+}
+
+// delimitedKinds are styles written with delimiters around the text in Markdown.
+var delimitedKinds = map[string]bool{
+	typeBold:          true,
+	typeItalic:        true,
+	typeUnderline:     true,
+	typeStrikethrough: true,
+	typeSpoiler:       true,
+}
+
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+
+		return zero
+	}
+
+	return *p
+}
@@ -0,0 +1,41 @@
+package format
+
+import "strings"
+
+var (
+	htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+	markdownV2Escaper = newBackslashEscaper("\\_*[]()~`>#+-=|{}.!")
+	markdownCode      = newBackslashEscaper("\\`")
+	markdownURL       = newBackslashEscaper("\\)")
+)
+
+// EscapeHTML escapes s for use as text or an attribute value in HTML parse mode.
+func EscapeHTML(s string) string {
+	return htmlEscaper.Replace(s)
+}
+
+// EscapeMarkdownV2 escapes s for use as text in MarkdownV2 parse mode.
+func EscapeMarkdownV2(s string) string {
+	return markdownV2Escaper.Replace(s)
+}
+
+// EscapeMarkdownV2Code escapes s for use inside inline code or a code block in
+// MarkdownV2 parse mode.
+func EscapeMarkdownV2Code(s string) string {
+	return markdownCode.Replace(s)
+}
+
+// EscapeMarkdownV2URL escapes s for use as the URL of a MarkdownV2 link.
+func EscapeMarkdownV2URL(s string) string {
+	return markdownURL.Replace(s)
+}
+
+func newBackslashEscaper(chars string) *strings.Replacer {
+	pairs := make([]string, 0, 2*len(chars)) //nolint:mnd // old and new string per character
+	for _, c := range chars {
+		pairs = append(pairs, string(c), `\`+string(c))
+	}
+
+	return strings.NewReplacer(pairs...)
+}
@@ -0,0 +1,254 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/format"
+)
+
+func TestTextRenders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		text       format.Text
+		plain      string
+		html       string
+		markdownV2 string
+	}{
+		{
+			name:       "escapes user input",
+			text:       format.Join("Hi ", format.Bold("<b>*x_y*</b>"), " 1.5!"),
+			plain:      "Hi <b>*x_y*</b> 1.5!",
+			html:       "Hi <b>&lt;b&gt;*x_y*&lt;/b&gt;</b> 1.5!",
+			markdownV2: `Hi *<b\>\*x\_y\*</b\>* 1\.5\!`,
+		},
+		{
+			name:       "nested styles",
+			text:       format.Italic(format.Underline("both"), " tail"),
+			plain:      "both tail",
+			html:       "<i><u>both</u> tail</i>",
+			markdownV2: "_\r__both__ tail_",
+		},
+		{
+			name:       "code keeps backslashes",
+			text:       format.Join(format.Code(`a\b`), " ", format.Pre("x := `y`", "go")),
+			plain:      "a\\b x := `y`",
+			html:       `<code>a\b</code> <pre><code class="language-go">x := ` + "`y`" + `</code></pre>`,
+			markdownV2: "`a\\\\b` ```go\nx := \\`y\\````",
+		},
+		{
+			name:       "links and mentions",
+			text:       format.Join(format.Link("https://x.y/(a)", "docs"), " ", format.Mention(42, "Ann")),
+			plain:      "docs Ann",
+			html:       `<a href="https://x.y/(a)">docs</a> <a href="tg://user?id=42">Ann</a>`,
+			markdownV2: `[docs](https://x.y/(a\)) [Ann](tg://user?id=42)`,
+		},
+		{
+			name:       "blockquote on its own lines",
+			text:       format.Join("said:", format.Blockquote("one\ntwo"), "ok"),
+			plain:      "said:\none\ntwo\nok",
+			html:       "said:\n<blockquote>one\ntwo</blockquote>\nok",
+			markdownV2: "said:\n>one\n>two\nok",
+		},
+		{
+			name:       "custom emoji and spoiler",
+			text:       format.Join(format.CustomEmoji("👍", "123"), format.Spoiler("secret")),
+			plain:      "👍secret",
+			html:       `<tg-emoji emoji-id="123">👍</tg-emoji><tg-spoiler>secret</tg-spoiler>`,
+			markdownV2: "![👍](tg://emoji?id=123)||secret||",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.text.String(); got != tt.plain {
+				t.Fatalf("String()=%q, want %q", got, tt.plain)
+			}
+
+			if got := tt.text.HTML(); got != tt.html {
+				t.Fatalf("HTML()=%q, want %q", got, tt.html)
+			}
+
+			if got := tt.text.MarkdownV2(); got != tt.markdownV2 {
+				t.Fatalf("MarkdownV2()=%q, want %q", got, tt.markdownV2)
+			}
+		})
+	}
+}
+
+func TestTextEntitiesUseUTF16Offsets(t *testing.T) {
+	t.Parallel()
+
+	text, entities := format.Join("😀 ", format.Bold("hi ", format.Italic("you")), " ", format.Pre("x", "go")).Entities()
+	if text != "😀 hi you x" {
+		t.Fatalf("text=%q, want %q", text, "😀 hi you x")
+	}
+
+	want := []client.MessageEntity{
+		{Type: "bold", Offset: 3, Length: 6},
+		{Type: "italic", Offset: 6, Length: 3},
+		{Type: "pre", Offset: 10, Length: 1},
+	}
+
+	if len(entities) != len(want) {
+		t.Fatalf("entities=%+v, want %+v", entities, want)
+	}
+
+	for i, e := range entities {
+		if e.Type != want[i].Type || e.Offset != want[i].Offset || e.Length != want[i].Length {
+			t.Fatalf("entity %d=%+v, want %+v", i, e, want[i])
+		}
+	}
+
+	if entities[2].Language == nil || *entities[2].Language != "go" {
+		t.Fatalf("pre language=%v, want go", entities[2].Language)
+	}
+}
+
+func TestTextEntitiesSkipEmptySpans(t *testing.T) {
+	t.Parallel()
+
+	_, entities := format.Join("a", format.Bold(), format.Mention(7, "b")).Entities()
+	if len(entities) != 1 || entities[0].Type != "text_mention" || entities[0].User == nil || entities[0].User.Id != 7 {
+		t.Fatalf("entities=%+v, want a single text_mention for user 7", entities)
+	}
+}
+
+func TestEscapeHelpers(t *testing.T) {
+	t.Parallel()
+
+	if got := format.EscapeMarkdownV2("a_b.c(d)"); got != `a\_b\.c\(d\)` {
+		t.Fatalf("EscapeMarkdownV2()=%q", got)
+	}
+
+	if got := format.EscapeMarkdownV2Code("`x`\\"); got != "\\`x\\`\\\\" {
+		t.Fatalf("EscapeMarkdownV2Code()=%q", got)
+	}
+
+	if got := format.EscapeMarkdownV2URL("https://x.y/a)b"); got != `https://x.y/a\)b` {
+		t.Fatalf("EscapeMarkdownV2URL()=%q", got)
+	}
+
+	if got := format.EscapeHTML(`<a href="x">&`); got != "&lt;a href=&quot;x&quot;&gt;&amp;" {
+		t.Fatalf("EscapeHTML()=%q", got)
+	}
+}
+
+func TestFromEntities(t *testing.T) {
+	t.Parallel()
+
+	url := "https://x.y/?a=1&b=2"
+	text := "Hello 😀 world, see more"
+	entities := []client.MessageEntity{
+		{Type: "bold", Offset: 0, Length: 9},
+		{Type: "italic", Offset: 6, Length: 8},
+		{Type: "text_link", Offset: 16, Length: 3, Url: &url},
+		{Type: "code", Offset: 20, Length: 4},
+		{Type: "hashtag", Offset: 0, Length: 5},
+	}
+
+	got := format.FromEntities(text, entities)
+
+	wantHTML := `<b>Hello <i>😀</i></b> <i>world</i>, <a href="https://x.y/?a=1&amp;b=2">see</a> <code>more</code>`
+	if html := got.HTML(); html != wantHTML {
+		t.Fatalf("HTML()=\n%q\nwant\n%q", html, wantHTML)
+	}
+
+	wantMarkdown := "*Hello _😀_* _world_, [see](https://x.y/?a=1&b=2) `more`"
+	if markdown := got.MarkdownV2(); markdown != wantMarkdown {
+		t.Fatalf("MarkdownV2()=\n%q\nwant\n%q", markdown, wantMarkdown)
+	}
+
+	if plain := got.String(); plain != text {
+		t.Fatalf("String()=%q, want %q", plain, text)
+	}
+}
+
+func TestFromMessageUsesCaption(t *testing.T) {
+	t.Parallel()
+
+	caption := "photo by Ann"
+	message := &client.Message{
+		Caption:         &caption,
+		CaptionEntities: &[]client.MessageEntity{{Type: "text_mention", Offset: 9, Length: 3, User: &client.User{Id: 5}}},
+	}
+
+	if got := format.FromMessage(message).HTML(); got != `photo by <a href="tg://user?id=5">Ann</a>` {
+		t.Fatalf("HTML()=%q", got)
+	}
+
+	if got := format.FromMessage(nil).String(); got != "" {
+		t.Fatalf("FromMessage(nil)=%q, want empty", got)
+	}
+}
+
+func TestCommonMark(t *testing.T) {
+	t.Parallel()
+
+	text := format.Join(
+		"# not a heading ", format.Bold("b"), format.Underline(" u"), "\n1. x ",
+		format.Link("https://x.y/a b", "l"), format.Blockquote("q1\nq2"), "after",
+		format.Pre("x := `y`", "go"), format.Code("a`b"), " ", format.Mention(3, "m"),
+	)
+
+	want := "\\# not a heading **b** u\n1\\. x [l](https://x.y/a%20b)\n> q1\n> q2\n\nafter\n```go\nx := `y`\n```\n``a`b`` [m](tg://user?id=3)"
+	if got := text.CommonMark(); got != want {
+		t.Fatalf("CommonMark()=\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestFromCommonMark(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		markdown string
+		html     string
+	}{
+		{
+			name:     "emphasis and headings",
+			markdown: "# Title\n\nSome *em*, __strong__, ***both*** and ~~gone~~ snake_case.\nline  \nbreak",
+			html:     "<b><u>Title</u></b>\n\nSome <i>em</i>, <b>strong</b>, <i><b>both</b></i> and <s>gone</s> snake_case.\nline\nbreak",
+		},
+		{
+			name:     "lists",
+			markdown: "- one\n- two\n  - nested\n- [ ] todo\n\n3. c\n4. d",
+			html:     "• one\n• two\n   ◦ nested\n☐ todo\n\n3. c\n4. d",
+		},
+		{
+			name:     "code",
+			markdown: "Run `go *test*`:\n\n```go\nx := 1 // *not* emphasis\n```\n\n    indented",
+			html:     "Run <code>go *test*</code>:\n\n<pre><code class=\"language-go\">x := 1 // *not* emphasis</code></pre>\n\n<pre>indented</pre>",
+		},
+		{
+			name:     "links and escapes",
+			markdown: `[docs *here*](https://x.y/a_(b) "title") ![logo](l.png) <https://a.b> \*raw\* &amp; <br>`,
+			html: `<a href="https://x.y/a_(b)">docs <i>here</i></a> <a href="l.png">logo</a> ` +
+				`<a href="https://a.b">https://a.b</a> *raw* &amp; &lt;br&gt;`,
+		},
+		{
+			name:     "quotes and rules",
+			markdown: "> quoted\nlazy\n> > nested\n\n***\n\nafter",
+			html:     "<blockquote>quoted\nlazy\n\nnested</blockquote>\n\n———\n\nafter",
+		},
+		{
+			name:     "tables",
+			markdown: "| Name | Qty |\n|:--|--:|\n| **apple** | 3 |\n| kiwi | 12 |",
+			html:     "<pre>Name  | Qty\n------+----\napple |   3\nkiwi  |  12</pre>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := format.FromCommonMark(tt.markdown).HTML(); got != tt.html {
+				t.Fatalf("HTML()=\n%q\nwant\n%q", got, tt.html)
+			}
+		})
+	}
+}
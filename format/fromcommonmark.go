@@ -0,0 +1,601 @@
+package format
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FromCommonMark converts standard Markdown, such as LLM output or release
+// notes, into a Text to send with entities. Emphasis, strikethrough, code,
+// links and quotes keep their formatting. Constructs Telegram lacks degrade
+// to text: headings become bold lines, list items get bullets or numbers,
+// thematic breaks become a line of dashes, images become links and tables
+// become preformatted text. Raw HTML and reference links stay as written.
+func FromCommonMark(src string) Text {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	lines := strings.Split(src, "\n")
+
+	for i, line := range lines {
+		lines[i] = expandIndent(line)
+	}
+
+	return blocksText(parseBlocks(lines), 0, false)
+}
+
+type mdKind int
+
+const (
+	mdParagraph mdKind = iota
+	mdHeading
+	mdCode
+	mdQuote
+	mdList
+	mdTable
+	mdRule
+)
+
+// mdBlock is a parsed Markdown block.
+type mdBlock struct {
+	kind mdKind
+	// text is the inline source of paragraphs and headings and the content
+	// of code blocks.
+	text     string
+	level    int
+	language string
+	children []mdBlock
+	items    [][]mdBlock
+	ordered  bool
+	start    int
+	rows     [][]string
+	align    []byte
+}
+
+// blockParser parses a block at the start of lines and reports how many
+// lines it used, or zero when lines do not start such a block.
+type blockParser func(lines []string) (mdBlock, int)
+
+var (
+	fenceRe      = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`\\s]*)")
+	atxHeadingRe = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+|$)(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	ruleRe       = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	quoteRe      = regexp.MustCompile(`^ {0,3}> ?`)
+	listItemRe   = regexp.MustCompile(`^( {0,3})([-+*]|(\d{1,9})[.)])( +|$)`)
+	setextRe     = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	tableSepRe   = regexp.MustCompile(`^[ \t]*\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+)
+
+const codeIndent = 4
+
+func parseBlocks(lines []string) []mdBlock {
+	var blocks []mdBlock
+
+	for len(lines) > 0 {
+		if isBlank(lines[0]) {
+			lines = lines[1:]
+
+			continue
+		}
+
+		block, n := parseBlock(lines)
+		blocks = append(blocks, block)
+		lines = lines[n:]
+	}
+
+	return blocks
+}
+
+// parseBlock tries the block parsers in order; a paragraph takes anything
+// else.
+func parseBlock(lines []string) (mdBlock, int) {
+	parsers := []blockParser{
+		parseFence,
+		parseATXHeading,
+		parseRule,
+		parseQuote,
+		parseList,
+		parseTable,
+		parseIndentedCode,
+	}
+
+	for _, parse := range parsers {
+		if block, n := parse(lines); n > 0 {
+			return block, n
+		}
+	}
+
+	return parseParagraph(lines)
+}
+
+func parseFence(lines []string) (mdBlock, int) {
+	m := fenceRe.FindStringSubmatch(lines[0])
+	if m == nil {
+		return mdBlock{}, 0
+	}
+
+	indent, fence := len(m[1]), m[2]
+	content := make([]string, 0, len(lines))
+
+	n := 1
+	for ; n < len(lines); n++ {
+		trimmed := strings.TrimLeft(lines[n], " ")
+		if indentOf(lines[n]) < codeIndent && strings.HasPrefix(trimmed, fence) &&
+			strings.Trim(trimmed, fence[:1]+" \t") == "" {
+			n++
+
+			break
+		}
+
+		content = append(content, trimIndent(lines[n], indent))
+	}
+
+	return mdBlock{kind: mdCode, text: strings.Join(content, "\n"), language: unescapeMarkdown(m[3])}, n
+}
+
+func parseATXHeading(lines []string) (mdBlock, int) {
+	m := atxHeadingRe.FindStringSubmatch(lines[0])
+	if m == nil {
+		return mdBlock{}, 0
+	}
+
+	return mdBlock{kind: mdHeading, level: len(m[1]), text: m[2]}, 1
+}
+
+func parseRule(lines []string) (mdBlock, int) {
+	if !ruleRe.MatchString(lines[0]) {
+		return mdBlock{}, 0
+	}
+
+	return mdBlock{kind: mdRule}, 1
+}
+
+func parseQuote(lines []string) (mdBlock, int) {
+	if !quoteRe.MatchString(lines[0]) {
+		return mdBlock{}, 0
+	}
+
+	content := make([]string, 0, len(lines))
+
+	n := 0
+	for ; n < len(lines); n++ {
+		line := lines[n]
+
+		switch {
+		case quoteRe.MatchString(line):
+			content = append(content, quoteRe.ReplaceAllString(line, ""))
+		case !isBlank(line) && !isBlank(content[len(content)-1]) && !startsBlock(lines[n:]):
+			// A lazy continuation of the quoted paragraph.
+			content = append(content, line)
+		default:
+			return mdBlock{kind: mdQuote, children: parseBlocks(content)}, n
+		}
+	}
+
+	return mdBlock{kind: mdQuote, children: parseBlocks(content)}, n
+}
+
+// listMarker is the marker of a list item.
+type listMarker struct {
+	ordered bool
+	// delim is the bullet character or the character after the number.
+	delim  byte
+	number int
+	// width is the indentation of the item content.
+	width int
+}
+
+func parseListMarker(line string) (listMarker, bool) {
+	m := listItemRe.FindStringSubmatch(line)
+	if m == nil {
+		return listMarker{}, false
+	}
+
+	marker := listMarker{delim: m[2][len(m[2])-1], width: len(m[0])}
+
+	if m[3] != "" {
+		marker.ordered = true
+		marker.number, _ = strconv.Atoi(m[3])
+	}
+
+	// Content indented further than a code block belongs to the item, so it
+	// starts one space after the marker.
+	if len(m[4]) > codeIndent || len(m[4]) == 0 {
+		marker.width = len(m[1]) + len(m[2]) + 1
+	}
+
+	return marker, true
+}
+
+func parseList(lines []string) (mdBlock, int) {
+	first, ok := parseListMarker(lines[0])
+	if !ok {
+		return mdBlock{}, 0
+	}
+
+	list := mdBlock{kind: mdList, ordered: first.ordered, start: first.number}
+
+	n := 0
+	for n < len(lines) {
+		marker, ok := parseListMarker(lines[n])
+		if !ok || marker.ordered != first.ordered || marker.delim != first.delim {
+			break
+		}
+
+		item, used := listItemLines(lines[n:], marker.width)
+		list.items = append(list.items, parseBlocks(item))
+		n += used
+
+		// Blank lines between items keep the list going.
+		if next := skipBlank(lines, n); next > n && next < len(lines) && listItemRe.MatchString(lines[next]) {
+			n = next
+		}
+	}
+
+	return list, n
+}
+
+// listItemLines returns the content of the list item starting lines with its
+// marker removed and the number of lines it takes.
+func listItemLines(lines []string, width int) ([]string, int) {
+	content := []string{lines[0][min(width, len(lines[0])):]}
+
+	n := 1
+	for ; n < len(lines); n++ {
+		line := lines[n]
+
+		switch {
+		case isBlank(line):
+			if next := skipBlank(lines, n); next == len(lines) || indentOf(lines[next]) < width {
+				return content, n
+			}
+
+			content = append(content, "")
+		case indentOf(line) >= width:
+			content = append(content, line[width:])
+		case listItemRe.MatchString(line):
+			return content, n
+		case !isBlank(content[len(content)-1]) && !startsBlock(lines[n:]):
+			// A lazy continuation of the item's paragraph.
+			content = append(content, strings.TrimLeft(line, " "))
+		default:
+			return content, n
+		}
+	}
+
+	return content, n
+}
+
+func parseTable(lines []string) (mdBlock, int) {
+	if !isTableStart(lines) {
+		return mdBlock{}, 0
+	}
+
+	table := mdBlock{kind: mdTable, rows: [][]string{splitTableRow(lines[0])}}
+
+	for _, cell := range splitTableRow(lines[1]) {
+		switch left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":"); {
+		case left && right:
+			table.align = append(table.align, 'c')
+		case right:
+			table.align = append(table.align, 'r')
+		default:
+			table.align = append(table.align, 'l')
+		}
+	}
+
+	n := 2
+	for ; n < len(lines) && !isBlank(lines[n]) && !startsBlock(lines[n:]); n++ {
+		table.rows = append(table.rows, splitTableRow(lines[n]))
+	}
+
+	return table, n
+}
+
+func isTableStart(lines []string) bool {
+	return len(lines) > 1 && strings.Contains(lines[0], "|") && tableSepRe.MatchString(lines[1]) &&
+		len(splitTableRow(lines[0])) == len(splitTableRow(lines[1]))
+}
+
+// splitTableRow splits a table row into trimmed cells on unescaped pipes.
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var (
+		cells []string
+		cell  strings.Builder
+	)
+
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			i++
+
+			cell.WriteByte('|')
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func parseIndentedCode(lines []string) (mdBlock, int) {
+	if indentOf(lines[0]) < codeIndent {
+		return mdBlock{}, 0
+	}
+
+	content := make([]string, 0, len(lines))
+
+	n := 0
+	for ; n < len(lines) && (isBlank(lines[n]) || indentOf(lines[n]) >= codeIndent); n++ {
+		content = append(content, trimIndent(lines[n], codeIndent))
+	}
+
+	// Trailing blank lines separate the block from what follows.
+	for isBlank(content[len(content)-1]) {
+		content = content[:len(content)-1]
+		n--
+	}
+
+	return mdBlock{kind: mdCode, text: strings.Join(content, "\n")}, n
+}
+
+func parseParagraph(lines []string) (mdBlock, int) {
+	content := []string{strings.TrimLeft(lines[0], " \t")}
+
+	n := 1
+	for ; n < len(lines); n++ {
+		line := lines[n]
+
+		if m := setextRe.FindStringSubmatch(line); m != nil {
+			level := 2
+			if m[1][0] == '=' {
+				level = 1
+			}
+
+			return mdBlock{kind: mdHeading, level: level, text: strings.Join(content, "\n")}, n + 1
+		}
+
+		if isBlank(line) || startsBlock(lines[n:]) {
+			break
+		}
+
+		// Keep trailing spaces, which make a hard line break.
+		content = append(content, strings.TrimLeft(line, " \t"))
+	}
+
+	return mdBlock{kind: mdParagraph, text: strings.TrimRight(strings.Join(content, "\n"), " \t")}, n
+}
+
+// startsBlock reports whether lines start a block that interrupts a
+// paragraph.
+func startsBlock(lines []string) bool {
+	line := lines[0]
+
+	if fenceRe.MatchString(line) || atxHeadingRe.MatchString(line) || ruleRe.MatchString(line) ||
+		quoteRe.MatchString(line) || isTableStart(lines) {
+		return true
+	}
+
+	// Only lists starting at one with content interrupt a paragraph.
+	marker, ok := parseListMarker(line)
+
+	return ok && (!marker.ordered || marker.number == 1) && !isBlank(line[min(marker.width, len(line)):])
+}
+
+// skipBlank returns the index of the first line from i that is not blank.
+func skipBlank(lines []string, i int) int {
+	for i < len(lines) && isBlank(lines[i]) {
+		i++
+	}
+
+	return i
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// trimIndent removes up to n leading spaces.
+func trimIndent(line string, n int) string {
+	return line[min(indentOf(line), n):]
+}
+
+// expandIndent replaces tabs in the indentation of line with spaces up to
+// the next multiple of four columns.
+func expandIndent(line string) string {
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+	if !strings.Contains(line[:indent], "\t") {
+		return line
+	}
+
+	var b strings.Builder
+
+	for _, c := range line[:indent] {
+		if c == '\t' {
+			b.WriteString(strings.Repeat(" ", codeIndent-b.Len()%codeIndent))
+		} else {
+			b.WriteRune(c)
+		}
+	}
+
+	return b.String() + line[indent:]
+}
+
+// bullets mark unordered list items by nesting depth.
+var bullets = []string{"•", "◦", "▪"}
+
+const (
+	horizontalRule = "———"
+	taskOpen       = "[ ] "
+	taskDone       = "[x] "
+)
+
+// blocksText renders blocks separated by blank lines. depth is the list
+// nesting depth and quoted is set inside a quote, which Telegram does not
+// allow to nest.
+func blocksText(blocks []mdBlock, depth int, quoted bool) Text {
+	parts := make([]any, 0, 2*len(blocks)) //nolint:mnd // separator and block
+
+	for i := range blocks {
+		if i > 0 {
+			parts = append(parts, "\n\n")
+		}
+
+		parts = append(parts, blockText(&blocks[i], depth, quoted))
+	}
+
+	return Join(parts...)
+}
+
+func blockText(b *mdBlock, depth int, quoted bool) Text {
+	switch b.kind {
+	case mdHeading:
+		if b.level == 1 {
+			return Bold(Underline(parseInline(b.text)))
+		}
+
+		return Bold(parseInline(b.text))
+	case mdCode:
+		return Pre(b.text, b.language)
+	case mdQuote:
+		if quoted {
+			return blocksText(b.children, depth, true)
+		}
+
+		return Blockquote(blocksText(b.children, depth, true))
+	case mdList:
+		return listText(b, depth, quoted)
+	case mdTable:
+		return Pre(tableText(b), "")
+	case mdRule:
+		return Plain(horizontalRule)
+	default:
+		return parseInline(b.text)
+	}
+}
+
+func listText(b *mdBlock, depth int, quoted bool) Text {
+	indent := strings.Repeat("   ", depth)
+	parts := make([]any, 0, 2*len(b.items)) //nolint:mnd // separator and item
+
+	for i, item := range b.items {
+		if i > 0 {
+			parts = append(parts, "\n")
+		}
+
+		marker := bullets[min(depth, len(bullets)-1)] + " "
+		if b.ordered {
+			marker = strconv.Itoa(b.start+i) + ". "
+		}
+
+		parts = append(parts, indent+taskMarker(item, marker), itemText(item, indent, depth+1, quoted))
+	}
+
+	return Join(parts...)
+}
+
+// taskMarker returns a checkbox for task list items, removing it from the
+// item text, or marker for other items.
+func taskMarker(item []mdBlock, marker string) string {
+	if len(item) == 0 || item[0].kind != mdParagraph {
+		return marker
+	}
+
+	switch text := item[0].text; {
+	case strings.HasPrefix(text, taskOpen):
+		item[0].text = text[len(taskOpen):]
+
+		return "☐ "
+	case strings.HasPrefix(strings.ToLower(text), taskDone):
+		item[0].text = text[len(taskDone):]
+
+		return "☑ "
+	default:
+		return marker
+	}
+}
+
+// itemText renders the blocks of a list item; paragraphs after the first
+// line up with it.
+func itemText(item []mdBlock, indent string, depth int, quoted bool) Text {
+	parts := make([]any, 0, 2*len(item)) //nolint:mnd // separator and block
+
+	for i := range item {
+		if i > 0 {
+			parts = append(parts, "\n")
+
+			if item[i].kind == mdParagraph {
+				parts = append(parts, indent+"   ")
+			}
+		}
+
+		parts = append(parts, blockText(&item[i], depth, quoted))
+	}
+
+	return Join(parts...)
+}
+
+// tableText lays out a table as aligned columns of plain text.
+func tableText(b *mdBlock) string {
+	columns := len(b.align)
+	widths := make([]int, columns)
+	cells := make([][]string, len(b.rows))
+
+	for i, row := range b.rows {
+		cells[i] = make([]string, columns)
+
+		for j := range min(len(row), columns) {
+			cells[i][j] = parseInline(row[j]).String()
+			widths[j] = max(widths[j], utf8.RuneCountInString(cells[i][j]))
+		}
+	}
+
+	lines := make([]string, 0, len(cells)+1)
+
+	for i, row := range cells {
+		padded := make([]string, columns)
+		for j, cell := range row {
+			padded[j] = pad(cell, widths[j], b.align[j])
+		}
+
+		lines = append(lines, strings.TrimRight(strings.Join(padded, " | "), " "))
+
+		if i == 0 {
+			rule := make([]string, columns)
+			for j, width := range widths {
+				rule[j] = strings.Repeat("-", width)
+			}
+
+			lines = append(lines, strings.Join(rule, "-+-"))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func pad(s string, width int, align byte) string {
+	space := width - utf8.RuneCountInString(s)
+
+	switch align {
+	case 'r':
+		return strings.Repeat(" ", space) + s
+	case 'c':
+		return strings.Repeat(" ", space/2) + s + strings.Repeat(" ", space-space/2) //nolint:mnd // halves
+	default:
+		return s + strings.Repeat(" ", space)
+	}
+}
@@ -0,0 +1,498 @@
+package format
+
+import (
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// inlineItem is a piece of a Markdown paragraph while emphasis is resolved:
+// literal text, a run of emphasis delimiters, a bracket that may open a link,
+// or finished Text.
+type inlineItem struct {
+	text string
+	node *Text
+	// delim is the delimiter character of a run of count delimiters.
+	delim    byte
+	count    int
+	canOpen  bool
+	canClose bool
+	bracket  bool
+	image    bool
+	// inactive is set on brackets that can no longer open a link, since links
+	// do not nest.
+	inactive bool
+}
+
+type inlineParser struct {
+	src   string
+	pos   int
+	buf   []byte
+	items []inlineItem
+}
+
+var inlineHandlers = map[byte]func(*inlineParser){
+	'\\': (*inlineParser).escape,
+	'`':  (*inlineParser).codeSpan,
+	'*':  (*inlineParser).delimiterRun,
+	'_':  (*inlineParser).delimiterRun,
+	'~':  (*inlineParser).delimiterRun,
+	'[':  (*inlineParser).openBracket,
+	'!':  (*inlineParser).openBracket,
+	']':  (*inlineParser).closeBracket,
+	'<':  (*inlineParser).autolink,
+	'&':  (*inlineParser).entity,
+	'\n': (*inlineParser).lineBreak,
+}
+
+var (
+	autolinkRe = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*)>`)
+	emailRe    = regexp.MustCompile(`^<([A-Za-z0-9.!#$%&'*+/=?^_{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9.-]*[A-Za-z0-9])?)>`)
+	entityRe   = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+)
+
+// parseInline converts the inline Markdown of a paragraph into Text.
+func parseInline(src string) Text {
+	p := &inlineParser{src: src}
+
+	for p.pos < len(p.src) {
+		if handle, ok := inlineHandlers[p.src[p.pos]]; ok {
+			handle(p)
+		} else {
+			p.literal(1)
+		}
+	}
+
+	p.flush()
+
+	return itemsText(processEmphasis(p.items))
+}
+
+func (p *inlineParser) literal(n int) {
+	p.buf = append(p.buf, p.src[p.pos:p.pos+n]...)
+	p.pos += n
+}
+
+func (p *inlineParser) flush() {
+	if len(p.buf) > 0 {
+		p.items = append(p.items, inlineItem{text: string(p.buf)})
+		p.buf = p.buf[:0]
+	}
+}
+
+func (p *inlineParser) push(item inlineItem) {
+	p.flush()
+	p.items = append(p.items, item)
+}
+
+func (p *inlineParser) escape() {
+	if p.pos+1 < len(p.src) && isASCIIPunct(p.src[p.pos+1]) || p.pos+1 < len(p.src) && p.src[p.pos+1] == '\n' {
+		p.pos++
+	}
+
+	p.literal(1)
+}
+
+func (p *inlineParser) codeSpan() {
+	n := runLength(p.src, p.pos)
+	start := p.pos + n
+
+	for i := start; i < len(p.src); {
+		if p.src[i] != '`' {
+			i++
+
+			continue
+		}
+
+		closing := runLength(p.src, i)
+		if closing != n {
+			i += closing
+
+			continue
+		}
+
+		content := strings.ReplaceAll(p.src[start:i], "\n", " ")
+		if len(content) > 2 && content[0] == ' ' && content[len(content)-1] == ' ' && strings.Trim(content, " ") != "" {
+			content = content[1 : len(content)-1]
+		}
+
+		code := Code(content)
+		p.push(inlineItem{node: &code})
+		p.pos = i + n
+
+		return
+	}
+
+	// Without a closing run the backticks are literal.
+	p.literal(n)
+}
+
+// delimiterRun reads a run of *, _ or ~ and records whether it can open or
+// close emphasis, following the flanking rules of CommonMark.
+func (p *inlineParser) delimiterRun() {
+	c := p.src[p.pos]
+	n := runLength(p.src, p.pos)
+
+	if c == '~' && n > 2 {
+		p.literal(n)
+
+		return
+	}
+
+	item := inlineItem{delim: c, count: n}
+	item.canOpen, item.canClose = flanking(p.src, p.pos, p.pos+n)
+
+	p.push(item)
+	p.pos += n
+}
+
+// flanking reports whether the delimiter run src[start:end] can open and
+// close emphasis.
+func flanking(src string, start, end int) (bool, bool) {
+	before, _ := utf8.DecodeLastRuneInString(src[:start])
+	after, _ := utf8.DecodeRuneInString(src[end:])
+
+	spaceBefore, punctBefore := classify(before, start == 0)
+	spaceAfter, punctAfter := classify(after, end == len(src))
+
+	left := leftFlanking(spaceBefore, punctBefore, spaceAfter, punctAfter)
+	right := leftFlanking(spaceAfter, punctAfter, spaceBefore, punctBefore)
+
+	if src[start] == '_' {
+		// Underscores inside words do not make emphasis.
+		return left && (!right || punctBefore), right && (!left || punctAfter)
+	}
+
+	return left, right
+}
+
+// leftFlanking reports whether a run is left-flanking; with the sides swapped
+// it reports whether it is right-flanking.
+func leftFlanking(spaceBefore, punctBefore, spaceAfter, punctAfter bool) bool {
+	return !spaceAfter && (!punctAfter || spaceBefore || punctBefore)
+}
+
+// classify reports whether r is white space or punctuation. The start and
+// end of the text count as white space.
+func classify(r rune, edge bool) (bool, bool) {
+	if edge || unicode.IsSpace(r) {
+		return true, false
+	}
+
+	return false, isPunct(r)
+}
+
+func (p *inlineParser) openBracket() {
+	switch {
+	case p.src[p.pos] == '[':
+		p.push(inlineItem{text: "[", bracket: true})
+		p.pos++
+	case strings.HasPrefix(p.src[p.pos:], "!["):
+		p.push(inlineItem{text: "![", bracket: true, image: true})
+		p.pos += 2
+	default:
+		p.literal(1)
+	}
+}
+
+// closeBracket makes a link from the text since the last open bracket when
+// a destination follows.
+func (p *inlineParser) closeBracket() {
+	p.flush()
+
+	opener := -1
+
+	for i := len(p.items) - 1; i >= 0; i-- {
+		if p.items[i].bracket {
+			opener = i
+
+			break
+		}
+	}
+
+	if opener < 0 {
+		p.literal(1)
+
+		return
+	}
+
+	url, n, ok := linkDestination(p.src[p.pos+1:])
+	if !ok || p.items[opener].inactive {
+		p.items[opener].bracket = false
+		p.literal(1)
+
+		return
+	}
+
+	content := itemsText(processEmphasis(p.items[opener+1:]))
+
+	var link Text
+
+	if p.items[opener].image {
+		alt := content.String()
+		if alt == "" {
+			alt = url
+		}
+
+		link = Link(url, alt)
+	} else {
+		link = Link(url, content)
+
+		for i := range opener {
+			p.items[i].inactive = true
+		}
+	}
+
+	p.items = append(p.items[:opener], inlineItem{node: &link})
+	p.pos += 1 + n
+}
+
+func (p *inlineParser) autolink() {
+	if m := autolinkRe.FindStringSubmatch(p.src[p.pos:]); m != nil {
+		link := Link(m[1], m[1])
+		p.push(inlineItem{node: &link})
+		p.pos += len(m[0])
+
+		return
+	}
+
+	if m := emailRe.FindStringSubmatch(p.src[p.pos:]); m != nil {
+		link := Link("mailto:"+m[1], m[1])
+		p.push(inlineItem{node: &link})
+		p.pos += len(m[0])
+
+		return
+	}
+
+	// Raw HTML stays as written.
+	p.literal(1)
+}
+
+func (p *inlineParser) entity() {
+	m := entityRe.FindString(p.src[p.pos:])
+	if m == "" {
+		p.literal(1)
+
+		return
+	}
+
+	p.buf = append(p.buf, html.UnescapeString(m)...)
+	p.pos += len(m)
+}
+
+// lineBreak keeps line breaks, as chat users expect, dropping the spaces
+// around them.
+func (p *inlineParser) lineBreak() {
+	for len(p.buf) > 0 && (p.buf[len(p.buf)-1] == ' ' || p.buf[len(p.buf)-1] == '\t') {
+		p.buf = p.buf[:len(p.buf)-1]
+	}
+
+	p.literal(1)
+
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// linkDestination parses "(destination "title")" at the start of s and
+// returns the destination and the length of the parsed text.
+func linkDestination(s string) (string, int, bool) {
+	if !strings.HasPrefix(s, "(") {
+		return "", 0, false
+	}
+
+	i := skipSpace(s, 1)
+
+	url, i, ok := scanDestination(s, i)
+	if !ok {
+		return "", 0, false
+	}
+
+	i = skipTitle(s, skipSpace(s, i))
+	i = skipSpace(s, i)
+
+	if i >= len(s) || s[i] != ')' {
+		return "", 0, false
+	}
+
+	return unescapeMarkdown(url), i + 1, true
+}
+
+func scanDestination(s string, i int) (string, int, bool) {
+	if i < len(s) && s[i] == '<' {
+		end := strings.IndexAny(s[i:], ">\n")
+		if end < 0 || s[i+end] != '>' {
+			return "", 0, false
+		}
+
+		return s[i+1 : i+end], i + end + 1, true
+	}
+
+	url, end := scanBareDestination(s, i)
+
+	return url, end, true
+}
+
+// scanBareDestination scans a destination up to white space or an
+// unbalanced parenthesis.
+func scanBareDestination(s string, i int) (string, int) {
+	start, depth := i, 0
+
+	for ; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			i++
+		case c == '(':
+			depth++
+		case c == ')' && depth == 0, c == ' ', c == '\t', c == '\n':
+			return s[start:i], i
+		case c == ')':
+			depth--
+		}
+	}
+
+	return s[start:i], i
+}
+
+// skipTitle skips a link title in quotes or parentheses at s[i].
+func skipTitle(s string, i int) int {
+	if i >= len(s) {
+		return i
+	}
+
+	closing, ok := map[byte]byte{'"': '"', '\'': '\'', '(': ')'}[s[i]]
+	if !ok {
+		return i
+	}
+
+	if end := strings.IndexByte(s[i+1:], closing); end >= 0 {
+		return i + end + 2 //nolint:mnd // both quotes
+	}
+
+	return i
+}
+
+func skipSpace(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n') {
+		i++
+	}
+
+	return i
+}
+
+// processEmphasis matches delimiter runs into emphasis, strong emphasis and
+// strikethrough, innermost first, as CommonMark does.
+func processEmphasis(items []inlineItem) []inlineItem {
+	for c := 0; c < len(items); c++ {
+		closer := &items[c]
+		if closer.delim == 0 || !closer.canClose || closer.count == 0 {
+			continue
+		}
+
+		o := findOpener(items, c)
+		if o < 0 {
+			continue
+		}
+
+		opener := &items[o]
+
+		use := 1
+		if closer.delim == '~' || opener.count >= 2 && closer.count >= 2 {
+			use = min(closer.count, 2) //nolint:mnd // strong emphasis uses two delimiters
+		}
+
+		node := emphasis(closer.delim, use, itemsText(items[o+1:c]))
+
+		opener.count -= use
+		closer.count -= use
+
+		items = slices.Concat(items[:o+1], []inlineItem{{node: &node}}, items[c:])
+		// Look at the closer again: delimiters it has left may close an
+		// outer span.
+		c = o + 1
+	}
+
+	return items
+}
+
+func emphasis(delim byte, use int, content Text) Text {
+	switch {
+	case delim == '~':
+		return Strikethrough(content)
+	case use == 1:
+		return Italic(content)
+	default:
+		return Bold(content)
+	}
+}
+
+func findOpener(items []inlineItem, c int) int {
+	for i := c - 1; i >= 0; i-- {
+		if matchesCloser(&items[i], &items[c]) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func matchesCloser(opener, closer *inlineItem) bool {
+	if opener.delim != closer.delim || !opener.canOpen || opener.count == 0 {
+		return false
+	}
+
+	if closer.delim == '~' {
+		return opener.count == closer.count
+	}
+
+	// The rule of three: a run that can both open and close matches only
+	// when the lengths do not add up to a multiple of three.
+	sum := opener.count + closer.count
+
+	return !(opener.canClose || closer.canOpen) || sum%3 != 0 || opener.count%3 == 0 && closer.count%3 == 0
+}
+
+func itemsText(items []inlineItem) Text {
+	parts := make([]any, 0, len(items))
+
+	for _, item := range items {
+		switch {
+		case item.node != nil:
+			parts = append(parts, *item.node)
+		case item.delim != 0:
+			parts = append(parts, strings.Repeat(string(item.delim), item.count))
+		default:
+			parts = append(parts, item.text)
+		}
+	}
+
+	return Join(parts...)
+}
+
+func runLength(s string, i int) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == s[i] {
+		n++
+	}
+
+	return n
+}
+
+func isASCIIPunct(c byte) bool {
+	return c < utf8.RuneSelf && isPunct(rune(c))
+}
+
+func isPunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+var markdownUnescaper = regexp.MustCompile(`\\([!-/:-@\[-` + "`" + `{-~])`)
+
+// unescapeMarkdown removes backslash escapes and decodes entities in link
+// destinations and code languages.
+func unescapeMarkdown(s string) string {
+	return html.UnescapeString(markdownUnescaper.ReplaceAllString(s, "$1"))
+}
@@ -0,0 +1,333 @@
+package format
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/tgbotkit/client"
+)
+
+// Entities returns the plain text and its entities, with offsets and lengths
+// in UTF-16 code units. Send them without a parse mode.
+func (t Text) Entities() (string, []client.MessageEntity) {
+	r := &entityRenderer{}
+	newWalker(r).walk(&t)
+
+	slices.SortStableFunc(r.entities, func(a, b client.MessageEntity) int {
+		return a.Offset - b.Offset
+	})
+
+	return r.buf.String(), r.entities
+}
+
+// HTML returns the text as escaped markup for the HTML parse mode.
+func (t Text) HTML() string {
+	r := &htmlRenderer{}
+	newWalker(r).walk(&t)
+
+	return r.buf.String()
+}
+
+// MarkdownV2 returns the text as escaped markup for the MarkdownV2 parse mode.
+func (t Text) MarkdownV2() string {
+	r := &markdownRenderer{}
+	newWalker(r).walk(&t)
+
+	return string(r.buf)
+}
+
+// CommonMark returns the text as standard Markdown. Styles Markdown lacks,
+// such as underline and spoilers, are dropped, and mentions become
+// tg://user links.
+func (t Text) CommonMark() string {
+	r := &commonMarkRenderer{lineStart: true}
+	newWalker(r).walk(&t)
+
+	return string(r.buf)
+}
+
+// renderer receives a Text as a stream of plain strings and span boundaries.
+type renderer interface {
+	text(s string)
+	open(t *Text)
+	close(t *Text)
+	// block reports whether t must stand on lines of its own.
+	block(t *Text) bool
+}
+
+// walker feeds a Text to a renderer, adding the line breaks that keep
+// blocks on lines of their own.
+type walker struct {
+	r           renderer
+	written     bool
+	atLineStart bool
+	// breakPending is set after a block; the next content starts a new line.
+	breakPending bool
+}
+
+func newWalker(r renderer) *walker {
+	return &walker{r: r, atLineStart: true}
+}
+
+func (w *walker) walk(t *Text) {
+	if t.kind == "" {
+		w.write(t.value)
+
+		for i := range t.children {
+			w.walk(&t.children[i])
+		}
+
+		return
+	}
+
+	block := w.r.block(t)
+	if block && !w.atLineStart {
+		w.write("\n")
+	}
+
+	w.write("")
+	w.r.open(t)
+
+	for i := range t.children {
+		w.walk(&t.children[i])
+	}
+
+	w.r.close(t)
+
+	if block {
+		w.breakPending = true
+	}
+}
+
+// write writes s, first ending a preceding block's line. An empty s only
+// flushes the pending break.
+func (w *walker) write(s string) {
+	if w.breakPending && !strings.HasPrefix(s, "\n") && (s != "" || w.written) {
+		w.r.text("\n")
+		w.atLineStart = true
+	}
+
+	w.breakPending = false
+
+	if s == "" {
+		return
+	}
+
+	w.r.text(s)
+	w.written = true
+	w.atLineStart = strings.HasSuffix(s, "\n")
+}
+
+type entityRenderer struct {
+	buf      strings.Builder
+	offset   int
+	starts   []int
+	entities []client.MessageEntity
+}
+
+func (r *entityRenderer) text(s string) {
+	r.buf.WriteString(s)
+
+	for _, c := range s {
+		r.offset += max(utf16.RuneLen(c), 1)
+	}
+}
+
+func (*entityRenderer) block(t *Text) bool {
+	return t.isBlock()
+}
+
+func (r *entityRenderer) open(*Text) {
+	r.starts = append(r.starts, r.offset)
+}
+
+func (r *entityRenderer) close(t *Text) {
+	start := r.starts[len(r.starts)-1]
+	r.starts = r.starts[:len(r.starts)-1]
+
+	if r.offset == start {
+		return
+	}
+
+	entity := client.MessageEntity{Type: t.kind, Offset: start, Length: r.offset - start}
+
+	switch t.kind {
+	case typePre:
+		if t.language != "" {
+			entity.Language = &t.language
+		}
+	case typeTextLink:
+		entity.Url = &t.url
+	case typeTextMention:
+		entity.User = &client.User{Id: t.userID}
+	case typeCustomEmoji:
+		entity.CustomEmojiId = &t.emojiID
+	}
+
+	r.entities = append(r.entities, entity)
+}
+
+type htmlRenderer struct {
+	buf strings.Builder
+}
+
+var htmlTags = map[string]string{
+	typeBold:          "b",
+	typeItalic:        "i",
+	typeUnderline:     "u",
+	typeStrikethrough: "s",
+	typeSpoiler:       "tg-spoiler",
+	typeCode:          "code",
+	typeBlockquote:    "blockquote",
+}
+
+func (r *htmlRenderer) text(s string) {
+	r.buf.WriteString(EscapeHTML(s))
+}
+
+func (*htmlRenderer) block(t *Text) bool {
+	return t.isBlock()
+}
+
+func (r *htmlRenderer) open(t *Text) {
+	switch t.kind {
+	case typePre:
+		r.buf.WriteString("<pre>")
+
+		if t.language != "" {
+			r.buf.WriteString(`<code class="language-` + EscapeHTML(t.language) + `">`)
+		}
+	case typeTextLink:
+		r.buf.WriteString(`<a href="` + EscapeHTML(t.url) + `">`)
+	case typeTextMention:
+		r.buf.WriteString(`<a href="tg://user?id=` + strconv.FormatInt(t.userID, 10) + `">`)
+	case typeCustomEmoji:
+		r.buf.WriteString(`<tg-emoji emoji-id="` + EscapeHTML(t.emojiID) + `">`)
+	case typeExpandableBlockquote:
+		r.buf.WriteString("<blockquote expandable>")
+	default:
+		r.buf.WriteString("<" + htmlTags[t.kind] + ">")
+	}
+}
+
+func (r *htmlRenderer) close(t *Text) {
+	switch t.kind {
+	case typePre:
+		if t.language != "" {
+			r.buf.WriteString("</code>")
+		}
+
+		r.buf.WriteString("</pre>")
+	case typeTextLink, typeTextMention:
+		r.buf.WriteString("</a>")
+	case typeCustomEmoji:
+		r.buf.WriteString("</tg-emoji>")
+	case typeExpandableBlockquote:
+		r.buf.WriteString("</blockquote>")
+	default:
+		r.buf.WriteString("</" + htmlTags[t.kind] + ">")
+	}
+}
+
+type markdownRenderer struct {
+	buf []byte
+	// code is set inside code and code blocks, where only ` and \ are escaped.
+	code bool
+	// quote is set inside blockquotes, where each line starts with ">".
+	quote bool
+	// delimited is set when buf ends with a style delimiter.
+	delimited bool
+}
+
+var markdownDelimiters = map[string]string{
+	typeBold:          "*",
+	typeItalic:        "_",
+	typeUnderline:     "__",
+	typeStrikethrough: "~",
+	typeSpoiler:       "||",
+	typeCode:          "`",
+}
+
+func (r *markdownRenderer) text(s string) {
+	if s == "" {
+		return
+	}
+
+	escaped := EscapeMarkdownV2(s)
+	if r.code {
+		escaped = EscapeMarkdownV2Code(s)
+	}
+
+	if r.quote {
+		escaped = strings.ReplaceAll(escaped, "\n", "\n>")
+	}
+
+	r.buf = append(r.buf, escaped...)
+	r.delimited = false
+}
+
+func (*markdownRenderer) block(t *Text) bool {
+	return t.isBlock()
+}
+
+func (r *markdownRenderer) open(t *Text) {
+	switch t.kind {
+	case typePre:
+		r.markup("```" + t.language + "\n")
+		r.code = true
+	case typeTextLink, typeTextMention:
+		r.markup("[")
+	case typeCustomEmoji:
+		r.markup("![")
+	case typeBlockquote:
+		r.markup(">")
+		r.quote = true
+	case typeExpandableBlockquote:
+		r.markup("**>")
+		r.quote = true
+	default:
+		r.delimiter(markdownDelimiters[t.kind])
+		r.code = t.kind == typeCode
+	}
+}
+
+func (r *markdownRenderer) close(t *Text) {
+	switch t.kind {
+	case typePre:
+		r.markup("```")
+		r.code = false
+	case typeTextLink:
+		r.markup("](" + EscapeMarkdownV2URL(t.url) + ")")
+	case typeTextMention:
+		r.markup("](tg://user?id=" + strconv.FormatInt(t.userID, 10) + ")")
+	case typeCustomEmoji:
+		r.markup("](tg://emoji?id=" + EscapeMarkdownV2URL(t.emojiID) + ")")
+	case typeBlockquote:
+		r.quote = false
+	case typeExpandableBlockquote:
+		r.markup("||")
+		r.quote = false
+	default:
+		r.delimiter(markdownDelimiters[t.kind])
+		r.code = false
+	}
+}
+
+func (r *markdownRenderer) markup(s string) {
+	r.buf = append(r.buf, s...)
+	r.delimited = false
+}
+
+// delimiter writes a style delimiter. Adjacent delimiters of the same
+// character, as in italic around underline, are ambiguous, so they are
+// separated with \r, which Telegram ignores.
+func (r *markdownRenderer) delimiter(s string) {
+	if r.delimited && r.buf[len(r.buf)-1] == s[0] {
+		r.buf = append(r.buf, '\r')
+	}
+
+	r.buf = append(r.buf, s...)
+	r.delimited = true
+}
@@ -0,0 +1,151 @@
+// Package format builds formatted Telegram text that cannot be broken by the
+// strings it contains.
+//
+// Text is composed from plain strings and styled spans:
+//
+//	msg := format.Join("Hello, ", format.Bold(name), "! See ", format.Link(url, "the docs"), ".")
+//
+// Strings are always treated as plain text, so user input never turns into
+// markup. A Text renders as plain text with MessageEntity offsets in UTF-16
+// units, as escaped HTML, or as escaped MarkdownV2.
+package format
+
+import "fmt"
+
+// Entity types as named by the Bot API.
+const (
+	typeBold                 = "bold"
+	typeItalic               = "italic"
+	typeUnderline            = "underline"
+	typeStrikethrough        = "strikethrough"
+	typeSpoiler              = "spoiler"
+	typeCode                 = "code"
+	typePre                  = "pre"
+	typeTextLink             = "text_link"
+	typeTextMention          = "text_mention"
+	typeCustomEmoji          = "custom_emoji"
+	typeBlockquote           = "blockquote"
+	typeExpandableBlockquote = "expandable_blockquote"
+)
+
+// Text is formatted text: a plain string, or a span with an optional style
+// around other Texts. The zero value is empty text.
+type Text struct {
+	kind     string
+	value    string
+	children []Text
+
+	url      string
+	language string
+	emojiID  string
+	userID   int64
+}
+
+// Plain returns s as unformatted text.
+func Plain(s string) Text {
+	return Text{value: s}
+}
+
+// Join concatenates parts without styling them. Each part is a Text or a
+// string; other values are formatted with fmt.Sprint.
+func Join(parts ...any) Text {
+	return span("", parts)
+}
+
+// Bold returns parts in bold.
+func Bold(parts ...any) Text {
+	return span(typeBold, parts)
+}
+
+// Italic returns parts in italics.
+func Italic(parts ...any) Text {
+	return span(typeItalic, parts)
+}
+
+// Underline returns parts underlined.
+func Underline(parts ...any) Text {
+	return span(typeUnderline, parts)
+}
+
+// Strikethrough returns parts struck through.
+func Strikethrough(parts ...any) Text {
+	return span(typeStrikethrough, parts)
+}
+
+// Spoiler returns parts hidden until tapped.
+func Spoiler(parts ...any) Text {
+	return span(typeSpoiler, parts)
+}
+
+// Code returns s as inline monospace code. Code cannot contain other styles.
+func Code(s string) Text {
+	return Text{kind: typeCode, children: []Text{Plain(s)}}
+}
+
+// Pre returns s as a code block highlighted as language, which may be empty.
+func Pre(s, language string) Text {
+	return Text{kind: typePre, language: language, children: []Text{Plain(s)}}
+}
+
+// Link returns parts linking to url.
+func Link(url string, parts ...any) Text {
+	t := span(typeTextLink, parts)
+	t.url = url
+
+	return t
+}
+
+// Mention returns parts mentioning the user with userID, which works for
+// users without a username.
+func Mention(userID int64, parts ...any) Text {
+	t := span(typeTextMention, parts)
+	t.userID = userID
+
+	return t
+}
+
+// CustomEmoji returns the custom emoji with id, shown as emoji where custom
+// emoji are not available. emoji must be a single regular emoji.
+func CustomEmoji(emoji, id string) Text {
+	return Text{kind: typeCustomEmoji, emojiID: id, children: []Text{Plain(emoji)}}
+}
+
+// Blockquote returns parts as a quote. A quote is a block: it starts and
+// ends on its own lines, and line breaks are added around it where needed.
+func Blockquote(parts ...any) Text {
+	return span(typeBlockquote, parts)
+}
+
+// ExpandableBlockquote returns parts as a quote collapsed to a few lines
+// until tapped.
+func ExpandableBlockquote(parts ...any) Text {
+	return span(typeExpandableBlockquote, parts)
+}
+
+// String returns the text without formatting.
+func (t Text) String() string {
+	text, _ := t.Entities()
+
+	return text
+}
+
+func span(kind string, parts []any) Text {
+	children := make([]Text, 0, len(parts))
+
+	for _, part := range parts {
+		switch p := part.(type) {
+		case Text:
+			children = append(children, p)
+		case string:
+			children = append(children, Plain(p))
+		default:
+			children = append(children, Plain(fmt.Sprint(p)))
+		}
+	}
+
+	return Text{kind: kind, children: children}
+}
+
+func (t Text) isBlock() bool {
+	return t.kind == typeBlockquote || t.kind == typeExpandableBlockquote
+}
@@ -19,6 +19,9 @@ type CommandHandler func(ctx context.Context, event *events.CommandEvent) error
 // CommandMatcher reports whether a command handler should run for an event.
 type CommandMatcher func(event *events.CommandEvent) bool
 
+// ChatMigratedHandler is a function that handles a group-to-supergroup migration event.
+type ChatMigratedHandler func(ctx context.Context, event *events.ChatMigratedEvent) error
+
 // CallbackQueryHandler is a function that handles a callback query event.
 type CallbackQueryHandler func(ctx context.Context, event *events.CallbackQueryEvent) error
 
@@ -40,12 +43,21 @@ type ShippingQueryHandler func(ctx context.Context, event *events.ShippingQueryE
 // PreCheckoutQueryHandler is a function that handles a pre-checkout query event.
 type PreCheckoutQueryHandler func(ctx context.Context, event *events.PreCheckoutQueryEvent) error
 
+// SuccessfulPaymentHandler is a function that handles a successful payment event.
+type SuccessfulPaymentHandler func(ctx context.Context, event *events.SuccessfulPaymentEvent) error
+
+// RefundedPaymentHandler is a function that handles a refunded payment event.
+type RefundedPaymentHandler func(ctx context.Context, event *events.RefundedPaymentEvent) error
+
 // PollHandler is a function that handles a poll event.
 type PollHandler func(ctx context.Context, event *events.PollEvent) error
 
 // PollAnswerHandler is a function that handles a poll answer event.
 type PollAnswerHandler func(ctx context.Context, event *events.PollAnswerEvent) error
 
+// PollClosedHandler is a function that handles a poll closed event.
+type PollClosedHandler func(ctx context.Context, event *events.PollClosedEvent) error
+
 // ChatMemberHandler is a function that handles a chat member event.
 type ChatMemberHandler func(ctx context.Context, event *events.ChatMemberEvent) error
 
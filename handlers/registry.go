@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"slices"
 
 	"github.com/tgbotkit/runtime/eventemitter"
 	"github.com/tgbotkit/runtime/events"
@@ -11,8 +12,20 @@ import (
 
 // Registry manages the subscription of handlers to events.
 type Registry struct {
-	em eventemitter.EventEmitter
-	l  logger.Logger
+	em         eventemitter.EventEmitter
+	l          logger.Logger
+	middleware []eventemitter.Middleware
+}
+
+// Option configures the handlers registered through a Registry returned by With.
+type Option func(*Registry)
+
+// WithMiddleware wraps handlers in middleware. Unlike middleware added to the
+// event emitter, it runs only when the handler's matcher accepts the event.
+func WithMiddleware(middleware ...eventemitter.Middleware) Option {
+	return func(r *Registry) {
+		r.middleware = append(r.middleware, middleware...)
+	}
 }
 
 // NewRegistry creates a new Registry.
@@ -23,21 +36,44 @@ func NewRegistry(em eventemitter.EventEmitter, l logger.Logger) *Registry {
 	}
 }
 
+// With returns a registry whose handlers are configured by opts, such as
+// per-handler middleware. Handlers registered before are not affected.
+func (r *Registry) With(opts ...Option) *Registry {
+	derived := &Registry{
+		em:         r.em,
+		l:          r.l,
+		middleware: slices.Clip(r.middleware),
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(derived)
+		}
+	}
+
+	return derived
+}
+
 // OnUpdate registers a handler for the OnUpdateReceived event.
 func (r *Registry) OnUpdate(handler UpdateHandler) eventemitter.UnsubscribeFunc {
 	r.l.Debugf("adding OnUpdate handler: %T", handler)
 
 	return eventemitter.On(r.em, events.OnUpdate, func(ctx context.Context, event *events.UpdateEvent) error {
-		return handler(ctx, event)
+		return dispatch(ctx, r, handler, event)
 	})
 }
 
+// OnUnhandledUpdate registers a handler for updates the classifier could not route to a typed event.
+func (r *Registry) OnUnhandledUpdate(handler UpdateHandler) eventemitter.UnsubscribeFunc {
+	return onEvent(r, events.OnUnhandledUpdate, "OnUnhandledUpdate", handler)
+}
+
 // OnMessage registers a handler for the OnMessageReceived event.
 func (r *Registry) OnMessage(handler MessageHandler) eventemitter.UnsubscribeFunc {
 	r.l.Debugf("adding OnMessage handler: %T", handler)
 
 	return eventemitter.On(r.em, events.OnMessage, func(ctx context.Context, event *events.MessageEvent) error {
-		return handler(ctx, event)
+		return dispatch(ctx, r, handler, event)
 	})
 }
 
@@ -57,7 +93,7 @@ func (r *Registry) OnMessageMatch(match MessageMatcher, handler MessageHandler)
 			return nil
 		}
 
-		return handler(ctx, event)
+		return dispatch(ctx, r, handler, event)
 	})
 }
 
@@ -96,7 +132,7 @@ func (r *Registry) OnCommand(handler CommandHandler) eventemitter.UnsubscribeFun
 	r.l.Debugf("adding OnCommand handler: %T", handler)
 
 	return eventemitter.On(r.em, events.OnCommand, func(ctx context.Context, event *events.CommandEvent) error {
-		return handler(ctx, event)
+		return dispatch(ctx, r, handler, event)
 	})
 }
 
@@ -114,10 +150,15 @@ func (r *Registry) OnCommandMatch(match CommandMatcher, handler CommandHandler)
 			return nil
 		}
 
-		return handler(ctx, event)
+		return dispatch(ctx, r, handler, event)
 	})
 }
 
+// OnChatMigrated registers a handler for group-to-supergroup migration events.
+func (r *Registry) OnChatMigrated(handler ChatMigratedHandler) eventemitter.UnsubscribeFunc {
+	return onEvent(r, events.OnChatMigrated, "OnChatMigrated", handler)
+}
+
 // OnCallbackQuery registers a handler for callback query events.
 func (r *Registry) OnCallbackQuery(handler CallbackQueryHandler) eventemitter.UnsubscribeFunc {
 	return onEvent(r, events.OnCallbackQuery, "OnCallbackQuery", handler)
@@ -148,7 +189,7 @@ func (r *Registry) OnCallbackQueryMatch(
 				return nil
 			}
 
-			return handler(ctx, event)
+			return dispatch(ctx, r, handler, event)
 		},
 	)
 }
@@ -173,6 +214,16 @@ func (r *Registry) OnPreCheckoutQuery(handler PreCheckoutQueryHandler) eventemit
 	return onEvent(r, events.OnPreCheckoutQuery, "OnPreCheckoutQuery", handler)
 }
 
+// OnSuccessfulPayment registers a handler for successful payment events.
+func (r *Registry) OnSuccessfulPayment(handler SuccessfulPaymentHandler) eventemitter.UnsubscribeFunc {
+	return onEvent(r, events.OnSuccessfulPayment, "OnSuccessfulPayment", handler)
+}
+
+// OnRefundedPayment registers a handler for refunded payment events.
+func (r *Registry) OnRefundedPayment(handler RefundedPaymentHandler) eventemitter.UnsubscribeFunc {
+	return onEvent(r, events.OnRefundedPayment, "OnRefundedPayment", handler)
+}
+
 // OnPoll registers a handler for poll events.
 func (r *Registry) OnPoll(handler PollHandler) eventemitter.UnsubscribeFunc {
 	return onEvent(r, events.OnPoll, "OnPoll", handler)
@@ -183,6 +234,11 @@ func (r *Registry) OnPollAnswer(handler PollAnswerHandler) eventemitter.Unsubscr
 	return onEvent(r, events.OnPollAnswer, "OnPollAnswer", handler)
 }
 
+// OnPollClosed registers a handler for the poll closed events of a polls.Tracker.
+func (r *Registry) OnPollClosed(handler PollClosedHandler) eventemitter.UnsubscribeFunc {
+	return onEvent(r, events.OnPollClosed, "OnPollClosed", handler)
+}
+
 // OnChatMember registers a handler for chat member events.
 func (r *Registry) OnChatMember(handler ChatMemberHandler) eventemitter.UnsubscribeFunc {
 	return onEvent(r, events.OnChatMember, "OnChatMember", handler)
@@ -251,7 +307,7 @@ func (r *Registry) onMessageEvent(
 	r.l.Debugf("adding %s handler: %T", name, handler)
 
 	return eventemitter.On(r.em, event, func(ctx context.Context, event *events.MessageEvent) error {
-		return handler(ctx, event)
+		return dispatch(ctx, r, handler, event)
 	})
 }
 
@@ -264,6 +320,23 @@ func onEvent[E any, H ~func(context.Context, *E) error](
 	r.l.Debugf("adding %s handler: %T", name, handler)
 
 	return eventemitter.On(r.em, event, func(ctx context.Context, event *E) error {
+		return dispatch(ctx, r, handler, event)
+	})
+}
+
+// dispatch calls handler through the registry's middleware.
+func dispatch[E any, H ~func(context.Context, *E) error](ctx context.Context, r *Registry, handler H, event *E) error {
+	if len(r.middleware) == 0 {
+		return handler(ctx, event)
+	}
+
+	var listener eventemitter.Listener = eventemitter.ListenerFunc(func(ctx context.Context, _ any) error {
 		return handler(ctx, event)
 	})
+
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		listener = r.middleware[i].Handle(listener)
+	}
+
+	return listener.Handle(ctx, event)
 }
@@ -380,3 +380,43 @@ func TestRegistry(t *testing.T) {
 		}
 	})
 }
+
+func TestRegistryWithMiddleware(t *testing.T) {
+	ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+	if err != nil {
+		t.Fatalf("NewSync() unexpected error: %v", err)
+	}
+
+	reg := handlers.NewRegistry(ee, logger.NewNop())
+
+	var wrapped []string
+	mw := eventemitter.MiddlewareFunc(func(next eventemitter.Listener) eventemitter.Listener {
+		return eventemitter.ListenerFunc(func(ctx context.Context, payload any) error {
+			wrapped = append(wrapped, payload.(*events.CommandEvent).Command)
+
+			return next.Handle(ctx, payload)
+		})
+	})
+
+	var called []string
+	handler := func(_ context.Context, event *events.CommandEvent) error {
+		called = append(called, event.Command)
+
+		return nil
+	}
+
+	reg.With(handlers.WithMiddleware(mw)).OnCommandName("slow", handler)
+	reg.OnCommandName("fast", handler)
+
+	for _, command := range []string{"slow", "fast"} {
+		ee.Emit(context.Background(), events.OnCommand, &events.CommandEvent{Command: command})
+	}
+
+	if len(called) != 2 {
+		t.Fatalf("called=%v, want both handlers", called)
+	}
+
+	if len(wrapped) != 1 || wrapped[0] != "slow" {
+		t.Fatalf("wrapped=%v, want only the matched handler with middleware", wrapped)
+	}
+}
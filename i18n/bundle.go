@@ -0,0 +1,255 @@
+// Package i18n translates bot messages into the language of each user.
+//
+// A Bundle holds message catalogs, one per locale, loaded from JSON, YAML or
+// TOML files. Messages are text/template templates and may have plural
+// forms. A Localizer translates keys for one locale, falling back to other
+// locales when a key is missing. The middleware.Localizer middleware picks the
+// locale of every event and puts its Localizer into the handler context.
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/tgbotkit/runtime/logger"
+)
+
+// Bundle holds the message catalogs of all locales.
+type Bundle struct {
+	mu       sync.RWMutex
+	opts     Options
+	log      logger.Logger
+	catalogs map[string]map[string]*message
+	rules    map[string]PluralRule
+}
+
+// New creates a new Bundle with the given options.
+func New(opts Options) (*Bundle, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid i18n options: %w", err)
+	}
+
+	if opts.logger == nil {
+		opts.logger = logger.NewNop()
+	}
+
+	return &Bundle{
+		opts:     opts,
+		log:      opts.logger,
+		catalogs: make(map[string]map[string]*message),
+		rules:    make(map[string]PluralRule),
+	}, nil
+}
+
+// DefaultLocale returns the locale that ends every fallback chain.
+func (b *Bundle) DefaultLocale() string {
+	return Normalize(b.opts.defaultLocale)
+}
+
+// Locales returns the locales that have a catalog, sorted.
+func (b *Bundle) Locales() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	locales := make([]string, 0, len(b.catalogs))
+	for locale := range b.catalogs {
+		locales = append(locales, locale)
+	}
+
+	slices.Sort(locales)
+
+	return locales
+}
+
+// LoadFile loads a catalog file. The locale is the last dot-separated part of
+// the file name before the extension, as in "en.yaml" or "messages.pt-BR.json".
+// Supported extensions are .json, .yaml, .yml and .toml.
+func (b *Bundle) LoadFile(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("read catalog: %w", err)
+	}
+
+	return b.loadNamed(name, data)
+}
+
+// LoadFS loads every catalog file in fsys, such as an embed.FS, named as for
+// LoadFile. Files with other extensions are skipped.
+func (b *Bundle) LoadFS(fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || formatOf(name) == "" {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("read catalog: %w", err)
+		}
+
+		return b.loadNamed(name, data)
+	})
+}
+
+// LoadData loads a catalog for locale from data in the given format: "json",
+// "yaml" or "toml". Keys of nested tables are joined with dots. A table whose
+// keys are all plural categories ("zero", "one", "two", "few", "many" and
+// "other") is a message with plural forms and must have "other". Messages
+// loaded later replace earlier ones with the same key.
+func (b *Bundle) LoadData(locale, format string, data []byte) error {
+	raw, err := decode(format, data)
+	if err != nil {
+		return fmt.Errorf("decode %s catalog for %q: %w", format, locale, err)
+	}
+
+	messages := make(map[string]*message)
+	if err := flatten("", raw, messages); err != nil {
+		return fmt.Errorf("load catalog for %q: %w", locale, err)
+	}
+
+	b.add(locale, messages)
+
+	return nil
+}
+
+// AddMessages adds messages for locale from a map of keys to templates.
+func (b *Bundle) AddMessages(locale string, messages map[string]string) error {
+	parsed := make(map[string]*message, len(messages))
+
+	for key, text := range messages {
+		msg, err := newMessage(key, map[string]string{PluralOther: text})
+		if err != nil {
+			return fmt.Errorf("load catalog for %q: %w", locale, err)
+		}
+
+		parsed[key] = msg
+	}
+
+	b.add(locale, parsed)
+
+	return nil
+}
+
+// SetPluralRule sets the plural rule of a language, replacing the built-in
+// one. language is a base language such as "en".
+func (b *Bundle) SetPluralRule(language string, rule PluralRule) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rules[Normalize(language)] = rule
+}
+
+// Localizer returns a Localizer for locale. An empty locale uses the default
+// locale.
+func (b *Bundle) Localizer(locale string) *Localizer {
+	return &Localizer{bundle: b, locale: Normalize(locale), chain: b.chain(locale)}
+}
+
+func (b *Bundle) loadNamed(name string, data []byte) error {
+	format := formatOf(name)
+	if format == "" {
+		return fmt.Errorf("load catalog %s: unsupported file extension", name)
+	}
+
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	locale := base[strings.LastIndexByte(base, '.')+1:]
+
+	return b.LoadData(locale, format, data)
+}
+
+func (b *Bundle) add(locale string, messages map[string]*message) {
+	locale = Normalize(locale)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	catalog := b.catalogs[locale]
+	if catalog == nil {
+		catalog = make(map[string]*message, len(messages))
+		b.catalogs[locale] = catalog
+	}
+
+	for key, msg := range messages {
+		catalog[key] = msg
+	}
+}
+
+// chain returns the locales tried for locale: the locale itself, its
+// configured fallbacks, its base language and the default locale.
+func (b *Bundle) chain(locale string) []string {
+	var chain []string
+
+	var visit func(locale string)
+
+	visit = func(locale string) {
+		if locale == "" || slices.Contains(chain, locale) {
+			return
+		}
+
+		chain = append(chain, locale)
+
+		for _, fallback := range b.opts.fallbacks[locale] {
+			visit(Normalize(fallback))
+		}
+
+		if base := baseLanguage(locale); base != locale {
+			visit(base)
+		}
+	}
+
+	visit(Normalize(locale))
+	visit(b.DefaultLocale())
+
+	return chain
+}
+
+// lookup finds key along chain and returns the message with the locale of
+// the catalog it came from.
+func (b *Bundle) lookup(chain []string, key string) (*message, string) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, locale := range chain {
+		if msg, ok := b.catalogs[locale][key]; ok {
+			return msg, locale
+		}
+	}
+
+	return nil, ""
+}
+
+func (b *Bundle) pluralRule(locale string) PluralRule {
+	language := baseLanguage(locale)
+
+	b.mu.RLock()
+	rule, ok := b.rules[language]
+	b.mu.RUnlock()
+
+	if ok {
+		return rule
+	}
+
+	return builtinPluralRule(language)
+}
+
+// Normalize returns locale as a lower-case IETF language tag, so that "pt_BR"
+// and "pt-BR" name the same locale.
+func Normalize(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+func baseLanguage(locale string) string {
+	if i := strings.IndexByte(locale, '-'); i > 0 {
+		return locale[:i]
+	}
+
+	return locale
+}
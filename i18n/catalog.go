@@ -0,0 +1,165 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Plural categories of CLDR plural rules.
+const (
+	PluralZero = "zero"
+	PluralOne  = "one"
+	PluralTwo  = "two"
+	PluralFew  = "few"
+	PluralMany = "many"
+	// PluralOther is the category every plural message must have.
+	PluralOther = "other"
+)
+
+var pluralCategories = []string{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther}
+
+var errNoOtherForm = errors.New(`plural message has no "other" form`)
+
+// message is a translated message with its plural forms. Messages without
+// plural forms have only the "other" form.
+type message struct {
+	forms map[string]*template.Template
+}
+
+func newMessage(key string, forms map[string]string) (*message, error) {
+	if _, ok := forms[PluralOther]; !ok {
+		return nil, fmt.Errorf("message %q: %w", key, errNoOtherForm)
+	}
+
+	msg := &message{forms: make(map[string]*template.Template, len(forms))}
+
+	for category, text := range forms {
+		tmpl, err := template.New(key).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("message %q: %w", key, err)
+		}
+
+		msg.forms[category] = tmpl
+	}
+
+	return msg, nil
+}
+
+// render executes the form for category, or the "other" form when the
+// message lacks it.
+func (m *message) render(category string, data any) (string, error) {
+	tmpl, ok := m.forms[category]
+	if !ok {
+		tmpl = m.forms[PluralOther]
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func formatOf(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".toml":
+		return "toml"
+	default:
+		return ""
+	}
+}
+
+func decode(format string, data []byte) (map[string]any, error) {
+	var raw map[string]any
+
+	var err error
+
+	switch format {
+	case "json":
+		err = json.Unmarshal(data, &raw)
+	case "yaml":
+		err = yaml.Unmarshal(data, &raw)
+	case "toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	return raw, err
+}
+
+// flatten adds the messages of a decoded catalog to out, joining the keys of
+// nested tables with dots.
+func flatten(prefix string, raw map[string]any, out map[string]*message) error {
+	for name, value := range raw {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		switch value := value.(type) {
+		case string:
+			msg, err := newMessage(key, map[string]string{PluralOther: value})
+			if err != nil {
+				return err
+			}
+
+			out[key] = msg
+		case map[string]any:
+			if err := flattenTable(key, value, out); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %q: unsupported value of type %T", key, value)
+		}
+	}
+
+	return nil
+}
+
+func flattenTable(key string, table map[string]any, out map[string]*message) error {
+	forms, ok := pluralForms(table)
+	if !ok {
+		return flatten(key, table, out)
+	}
+
+	msg, err := newMessage(key, forms)
+	if err != nil {
+		return err
+	}
+
+	out[key] = msg
+
+	return nil
+}
+
+// pluralForms returns the forms of a table whose keys are all plural
+// categories with text values.
+func pluralForms(table map[string]any) (map[string]string, bool) {
+	forms := make(map[string]string, len(table))
+
+	for category, value := range table {
+		text, ok := value.(string)
+		if !ok || !slices.Contains(pluralCategories, category) {
+			return nil, false
+		}
+
+		forms[category] = text
+	}
+
+	return forms, len(forms) > 0
+}
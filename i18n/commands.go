@@ -0,0 +1,95 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/botapi"
+)
+
+// Command is a bot command whose description is a message key.
+type Command struct {
+	// Name is the command without the leading slash.
+	Name string
+	// DescriptionKey is the key of the command description.
+	DescriptionKey string
+}
+
+// BotCommands returns commands with descriptions translated for locale.
+func (b *Bundle) BotCommands(locale string, commands []Command) []client.BotCommand {
+	l := b.Localizer(locale)
+	result := make([]client.BotCommand, 0, len(commands))
+
+	for _, command := range commands {
+		result = append(result, client.BotCommand{
+			Command:     command.Name,
+			Description: l.T(command.DescriptionKey, nil),
+		})
+	}
+
+	return result
+}
+
+// SetMyCommands registers commands with Telegram in every language of the
+// bundle. Descriptions in the default locale are set without a language code
+// and shown to users whose language has none. Catalogs of other two-letter
+// languages get their own translations; catalogs of regional locales such as
+// "pt-br" are skipped, since Telegram only accepts languages. A nil scope is
+// the default scope.
+func (b *Bundle) SetMyCommands(
+	ctx context.Context,
+	api client.ClientWithResponsesInterface,
+	commands []Command,
+	scope client.BotCommandScope,
+) error {
+	if err := setMyCommands(ctx, api, b.BotCommands(b.DefaultLocale(), commands), nil, scope); err != nil {
+		return err
+	}
+
+	for _, locale := range b.Locales() {
+		if locale == b.DefaultLocale() || baseLanguage(locale) != locale || len(locale) != 2 {
+			continue
+		}
+
+		if err := setMyCommands(ctx, api, b.BotCommands(locale, commands), &locale, scope); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setMyCommands(
+	ctx context.Context,
+	api client.ClientWithResponsesInterface,
+	commands []client.BotCommand,
+	languageCode *string,
+	scope client.BotCommandScope,
+) error {
+	body := client.SetMyCommandsJSONRequestBody{
+		Commands:     commands,
+		LanguageCode: languageCode,
+	}
+
+	if scope != nil {
+		body.Scope = &scope
+	}
+
+	resp, err := api.SetMyCommandsWithResponse(ctx, body)
+	if err != nil {
+		return fmt.Errorf("set my commands: %w", err)
+	}
+
+	if resp.JSON200 == nil {
+		if apiErr := botapi.FromResponse(resp.StatusCode(), resp.Body); apiErr != nil {
+			return fmt.Errorf("set my commands: %w", apiErr)
+		}
+	}
+
+	if resp.JSON200 == nil || !bool(resp.JSON200.Ok) {
+		return fmt.Errorf("set my commands: unexpected response: %s", resp.Status())
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package i18n_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/i18n"
+	"github.com/tgbotkit/runtime/i18n/localestore"
+)
+
+func newBundle(t *testing.T, opts ...i18n.OptOptionsSetter) *i18n.Bundle {
+	t.Helper()
+
+	bundle, err := i18n.New(i18n.NewOptions(opts...))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	err = bundle.LoadFS(fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{
+			"hello": "Hello, {{.Name}}!",
+			"cart": {"items": {"one": "{{.Count}} item", "other": "{{.Count}} items"}},
+			"cmd": {"start": "Start the bot"}
+		}`)},
+		"locales/ru.yaml": {Data: []byte(`
+hello: "Привет, {{.Name}}!"
+cart:
+  items:
+    one: "{{.Count}} товар"
+    few: "{{.Count}} товара"
+    many: "{{.Count}} товаров"
+    other: "{{.Count}} товара"
+cmd:
+  start: Запустить бота
+`)},
+		"locales/messages.pt-BR.toml": {Data: []byte(`hello = "Olá, {{.Name}}!"`)},
+		"locales/README.md":           {Data: []byte("not a catalog")},
+	})
+	if err != nil {
+		t.Fatalf("LoadFS() unexpected error: %v", err)
+	}
+
+	return bundle
+}
+
+func TestLocalizerTranslates(t *testing.T) {
+	t.Parallel()
+
+	bundle := newBundle(t, i18n.WithFallbacks(map[string][]string{"uk": {"ru"}}))
+
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{locale: "en", want: "Hello, Ann!"},
+		{locale: "ru", want: "Привет, Ann!"},
+		{locale: "pt_BR", want: "Olá, Ann!"},
+		{locale: "uk", want: "Привет, Ann!"},
+		{locale: "de", want: "Hello, Ann!"},
+		{locale: "", want: "Hello, Ann!"},
+	}
+
+	for _, tt := range tests {
+		if got := bundle.Localizer(tt.locale).T("hello", map[string]string{"Name": "Ann"}); got != tt.want {
+			t.Fatalf("T(%q)=%q, want %q", tt.locale, got, tt.want)
+		}
+	}
+
+	if got := bundle.Localizer("ru").T("missing.key", nil); got != "missing.key" {
+		t.Fatalf("T(missing)=%q, want the key", got)
+	}
+
+	if got := (*i18n.Localizer)(nil).T("hello", nil); got != "hello" {
+		t.Fatalf("nil T()=%q, want the key", got)
+	}
+
+	if got := bundle.Locales(); len(got) != 3 || got[0] != "en" || got[1] != "pt-br" || got[2] != "ru" {
+		t.Fatalf("Locales()=%v, want [en pt-br ru]", got)
+	}
+}
+
+func TestLocalizerPlural(t *testing.T) {
+	t.Parallel()
+
+	bundle := newBundle(t)
+
+	tests := []struct {
+		locale string
+		count  int
+		want   string
+	}{
+		{locale: "en", count: 1, want: "1 item"},
+		{locale: "en", count: 5, want: "5 items"},
+		{locale: "ru", count: 1, want: "1 товар"},
+		{locale: "ru", count: 3, want: "3 товара"},
+		{locale: "ru", count: 11, want: "11 товаров"},
+		{locale: "ru", count: 22, want: "22 товара"},
+		// pt-br has no plural message: English forms and rules apply.
+		{locale: "pt-br", count: 0, want: "0 items"},
+	}
+
+	for _, tt := range tests {
+		if got := bundle.Localizer(tt.locale).Plural("cart.items", tt.count, nil); got != tt.want {
+			t.Fatalf("Plural(%q, %d)=%q, want %q", tt.locale, tt.count, got, tt.want)
+		}
+	}
+
+	bundle.SetPluralRule("en", func(int) string { return i18n.PluralOne })
+
+	if got := bundle.Localizer("en").Plural("cart.items", 7, map[string]any{"Count": "seven"}); got != "seven item" {
+		t.Fatalf("Plural() with custom rule=%q, want %q", got, "seven item")
+	}
+}
+
+func TestBundleRejectsInvalidCatalogs(t *testing.T) {
+	t.Parallel()
+
+	bundle := newBundle(t)
+
+	if err := bundle.LoadData("en", "json", []byte(`{"items": {"one": "x"}}`)); err == nil {
+		t.Fatal("LoadData() without other form error is nil, want non-nil")
+	}
+
+	if err := bundle.LoadData("en", "yaml", []byte(`count: 3`)); err == nil {
+		t.Fatal("LoadData() with a number error is nil, want non-nil")
+	}
+
+	if err := bundle.AddMessages("en", map[string]string{"bad": "{{.Name"}); err == nil {
+		t.Fatal("AddMessages() with a broken template error is nil, want non-nil")
+	}
+}
+
+func TestPreferredLocale(t *testing.T) {
+	t.Parallel()
+
+	store := localestore.NewInMemoryLocaleStore()
+	resolve := i18n.PreferredLocale(store, nil)
+
+	language := "de"
+	event := &events.MessageEvent{Message: &client.Message{From: &client.User{Id: 5, LanguageCode: &language}}}
+
+	if got := resolve(context.Background(), event); got != "de" {
+		t.Fatalf("resolve()=%q, want the language code", got)
+	}
+
+	if err := store.SetLocale(context.Background(), 5, "ru"); err != nil {
+		t.Fatalf("SetLocale() unexpected error: %v", err)
+	}
+
+	if got := resolve(context.Background(), event); got != "ru" {
+		t.Fatalf("resolve()=%q, want the stored locale", got)
+	}
+
+	if got := resolve(context.Background(), &events.PollEvent{}); got != "" {
+		t.Fatalf("resolve(poll)=%q, want empty", got)
+	}
+}
+
+type commandsClient struct {
+	client.ClientWithResponsesInterface
+	calls []client.SetMyCommandsJSONRequestBody
+}
+
+func (c *commandsClient) SetMyCommandsWithResponse(
+	_ context.Context,
+	body client.SetMyCommandsJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.SetMyCommandsResponse, error) {
+	c.calls = append(c.calls, body)
+
+	return &client.SetMyCommandsResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"},
+		JSON200: &struct {
+			Ok     client.SetMyCommands200Ok `json:"ok"`
+			Result bool                      `json:"result"`
+		}{Ok: true, Result: true},
+	}, nil
+}
+
+func TestBundleSetMyCommands(t *testing.T) {
+	t.Parallel()
+
+	bundle := newBundle(t)
+	api := &commandsClient{}
+
+	err := bundle.SetMyCommands(context.Background(), api, []i18n.Command{{Name: "start", DescriptionKey: "cmd.start"}}, nil)
+	if err != nil {
+		t.Fatalf("SetMyCommands() unexpected error: %v", err)
+	}
+
+	if len(api.calls) != 2 {
+		t.Fatalf("calls=%d, want default and ru", len(api.calls))
+	}
+
+	if api.calls[0].LanguageCode != nil || api.calls[0].Commands[0].Description != "Start the bot" {
+		t.Fatalf("default call=%+v, want English without a language code", api.calls[0])
+	}
+
+	ru := api.calls[1]
+	if ru.LanguageCode == nil || *ru.LanguageCode != "ru" || ru.Commands[0].Description != "Запустить бота" {
+		t.Fatalf("ru call=%+v, want Russian descriptions", ru)
+	}
+}
@@ -0,0 +1,45 @@
+// Package localestore provides i18n.LocaleStore implementations.
+package localestore
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tgbotkit/runtime/i18n"
+)
+
+// InMemoryLocaleStore keeps the locales users picked in memory. They are lost
+// on restart.
+type InMemoryLocaleStore struct {
+	mu      sync.RWMutex
+	locales map[int64]string
+}
+
+var _ i18n.LocaleStore = (*InMemoryLocaleStore)(nil)
+
+// NewInMemoryLocaleStore creates a new InMemoryLocaleStore.
+func NewInMemoryLocaleStore() *InMemoryLocaleStore {
+	return &InMemoryLocaleStore{locales: make(map[int64]string)}
+}
+
+// Locale returns the locale userID picked, or an empty string.
+func (s *InMemoryLocaleStore) Locale(_ context.Context, userID int64) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.locales[userID], nil
+}
+
+// SetLocale records the locale userID picked. An empty locale forgets it.
+func (s *InMemoryLocaleStore) SetLocale(_ context.Context, userID int64, locale string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if locale == "" {
+		delete(s.locales, userID)
+	} else {
+		s.locales[userID] = locale
+	}
+
+	return nil
+}
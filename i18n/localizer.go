@@ -0,0 +1,108 @@
+package i18n
+
+import (
+	"context"
+	"maps"
+)
+
+// Localizer translates message keys for one locale. A nil Localizer returns
+// keys untranslated.
+type Localizer struct {
+	bundle *Bundle
+	locale string
+	chain  []string
+}
+
+// Locale returns the requested locale, normalized.
+func (l *Localizer) Locale() string {
+	if l == nil {
+		return ""
+	}
+
+	return l.locale
+}
+
+// Has reports whether key is translated in the locale or a fallback.
+func (l *Localizer) Has(key string) bool {
+	if l == nil {
+		return false
+	}
+
+	msg, _ := l.bundle.lookup(l.chain, key)
+
+	return msg != nil
+}
+
+// T translates key, executing its template with data. A missing key is
+// returned as is, so untranslated messages stay recognizable.
+func (l *Localizer) T(key string, data any) string {
+	return l.translate(key, PluralOther, data)
+}
+
+// Plural translates key choosing the plural form for count by the rule of
+// the catalog's language. When data is nil or a map[string]any, templates
+// see count as .Count.
+func (l *Localizer) Plural(key string, count int, data any) string {
+	switch values := data.(type) {
+	case nil:
+		data = map[string]any{"Count": count}
+	case map[string]any:
+		if _, ok := values["Count"]; !ok {
+			values = maps.Clone(values)
+			values["Count"] = count
+			data = values
+		}
+	}
+
+	if l == nil {
+		return key
+	}
+
+	msg, locale := l.bundle.lookup(l.chain, key)
+	if msg == nil {
+		return key
+	}
+
+	return l.render(key, msg, l.bundle.pluralRule(locale)(count), data)
+}
+
+func (l *Localizer) translate(key, category string, data any) string {
+	if l == nil {
+		return key
+	}
+
+	msg, _ := l.bundle.lookup(l.chain, key)
+	if msg == nil {
+		return key
+	}
+
+	return l.render(key, msg, category, data)
+}
+
+// render executes a message. A template that fails is logged and its key
+// returned.
+func (l *Localizer) render(key string, msg *message, category string, data any) string {
+	text, err := msg.render(category, data)
+	if err != nil {
+		l.bundle.log.Errorf("translate %q for %q: %v", key, l.locale, err)
+
+		return key
+	}
+
+	return text
+}
+
+type contextKey struct{}
+
+// WithLocalizer returns a new context carrying l.
+func WithLocalizer(ctx context.Context, l *Localizer) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the Localizer of ctx, or nil when there is none. The
+// nil Localizer returns keys untranslated.
+func FromContext(ctx context.Context) *Localizer {
+	l, _ := ctx.Value(contextKey{}).(*Localizer)
+
+	return l
+}
@@ -0,0 +1,57 @@
+// Code generated by options-gen v0.55.3. DO NOT EDIT.
+
+package i18n
+
+import (
+	fmt461e464ebed9 "fmt"
+
+	errors461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/errors"
+	validator461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/validator"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+type OptOptionsSetter func(o *Options)
+
+func NewOptions(
+	options ...OptOptionsSetter,
+) Options {
+	var o Options
+
+	// Setting defaults from field tag (if present)
+
+	o.defaultLocale = "en"
+
+	for _, opt := range options {
+		opt(&o)
+	}
+	return o
+}
+
+// defaultLocale ends every fallback chain and is used when no locale is known.
+func WithDefaultLocale(opt string) OptOptionsSetter {
+	return func(o *Options) { o.defaultLocale = opt }
+}
+
+// fallbacks lists the locales tried after a locale and before its base
+// language, such as "uk" falling back to "ru".
+func WithFallbacks(opt map[string][]string) OptOptionsSetter {
+	return func(o *Options) { o.fallbacks = opt }
+}
+
+// logger is the logger to use.
+func WithLogger(opt logger.Logger) OptOptionsSetter {
+	return func(o *Options) { o.logger = opt }
+}
+
+func (o *Options) Validate() error {
+	errs := new(errors461e464ebed9.ValidationErrors)
+	errs.Add(errors461e464ebed9.NewValidationError("defaultLocale", _validate_Options_defaultLocale(o)))
+	return errs.AsError()
+}
+
+func _validate_Options_defaultLocale(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.defaultLocale, "required"); err != nil {
+		return fmt461e464ebed9.Errorf("field `defaultLocale` did not pass the test: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package i18n
+
+import "github.com/tgbotkit/runtime/logger"
+
+//go:generate go tool options-gen -out-filename=options.gen.go -from-struct=Options
+
+// Options is the options for the Bundle.
+type Options struct {
+	// defaultLocale ends every fallback chain and is used when no locale is known.
+	defaultLocale string `default:"en" validate:"required"`
+	// fallbacks lists the locales tried after a locale and before its base
+	// language, such as "uk" falling back to "ru".
+	fallbacks map[string][]string
+	// logger is the logger to use.
+	logger logger.Logger
+}
@@ -0,0 +1,112 @@
+package i18n
+
+// PluralRule returns the plural category, such as PluralOne, of a count.
+type PluralRule func(n int) string
+
+// builtinPluralRule returns the CLDR cardinal rule of language for whole
+// numbers. Languages without a built-in rule use the English rule.
+func builtinPluralRule(language string) PluralRule {
+	switch language {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms", "lo", "my", "km":
+		return pluralOtherOnly
+	case "fr", "pt", "hi", "bn", "fa", "am", "zu":
+		return pluralZeroOrOne
+	case "ru", "uk", "be":
+		return pluralEastSlavic
+	case "pl":
+		return pluralPolish
+	case "cs", "sk":
+		return pluralCzech
+	case "ar":
+		return pluralArabic
+	default:
+		return pluralOneOther
+	}
+}
+
+func pluralOtherOnly(int) string {
+	return PluralOther
+}
+
+func pluralOneOther(n int) string {
+	if n == 1 {
+		return PluralOne
+	}
+
+	return PluralOther
+}
+
+func pluralZeroOrOne(n int) string {
+	if n == 0 || n == 1 {
+		return PluralOne
+	}
+
+	return PluralOther
+}
+
+//nolint:mnd // CLDR rule
+func pluralEastSlavic(n int) string {
+	n = abs(n)
+
+	switch mod10, mod100 := n%10, n%100; {
+	case mod10 == 1 && mod100 != 11:
+		return PluralOne
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+//nolint:mnd // CLDR rule
+func pluralPolish(n int) string {
+	n = abs(n)
+
+	switch mod10, mod100 := n%10, n%100; {
+	case n == 1:
+		return PluralOne
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+func pluralCzech(n int) string {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n >= 2 && n <= 4:
+		return PluralFew
+	default:
+		return PluralOther
+	}
+}
+
+//nolint:mnd // CLDR rule
+func pluralArabic(n int) string {
+	n = abs(n)
+
+	switch mod100 := n % 100; {
+	case n == 0:
+		return PluralZero
+	case n == 1:
+		return PluralOne
+	case n == 2:
+		return PluralTwo
+	case mod100 >= 3 && mod100 <= 10:
+		return PluralFew
+	case mod100 >= 11:
+		return PluralMany
+	default:
+		return PluralOther
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
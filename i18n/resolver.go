@@ -0,0 +1,84 @@
+package i18n
+
+import (
+	"context"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/events"
+)
+
+// Resolver picks the locale of an event payload. An empty locale stands for
+// the bundle's default locale.
+type Resolver func(ctx context.Context, payload any) string
+
+// LocaleStore keeps the locales users picked, such as with a /language
+// command.
+type LocaleStore interface {
+	// Locale returns the locale userID picked, or an empty string.
+	Locale(ctx context.Context, userID int64) (string, error)
+	// SetLocale records the locale userID picked. An empty locale forgets it.
+	SetLocale(ctx context.Context, userID int64, locale string) error
+}
+
+// LanguageCode resolves the language Telegram reports for the user who
+// caused the event.
+func LanguageCode(_ context.Context, payload any) string {
+	user := EventUser(payload)
+	if user == nil || user.LanguageCode == nil {
+		return ""
+	}
+
+	return *user.LanguageCode
+}
+
+// PreferredLocale returns a Resolver that uses the locale the user picked,
+// kept in store, and asks next otherwise. A nil next uses LanguageCode.
+// Store failures are treated as no preference.
+func PreferredLocale(store LocaleStore, next Resolver) Resolver {
+	if next == nil {
+		next = LanguageCode
+	}
+
+	return func(ctx context.Context, payload any) string {
+		if user := EventUser(payload); user != nil {
+			if locale, err := store.Locale(ctx, user.Id); err == nil && locale != "" {
+				return locale
+			}
+		}
+
+		return next(ctx, payload)
+	}
+}
+
+// EventUser returns the user who caused the event carried by payload, or nil
+// for events without one.
+func EventUser(payload any) *client.User {
+	switch event := payload.(type) {
+	case *events.MessageEvent:
+		return sender(event.Message, func(m *client.Message) *client.User { return m.From })
+	case *events.CommandEvent:
+		return sender(event.Message, func(m *client.Message) *client.User { return m.From })
+	case *events.CallbackQueryEvent:
+		return sender(event.CallbackQuery, func(q *client.CallbackQuery) *client.User { return &q.From })
+	case *events.InlineQueryEvent:
+		return sender(event.InlineQuery, func(q *client.InlineQuery) *client.User { return &q.From })
+	case *events.ChosenInlineResultEvent:
+		return sender(event.ChosenInlineResult, func(r *client.ChosenInlineResult) *client.User { return &r.From })
+	case *events.PreCheckoutQueryEvent:
+		return sender(event.PreCheckoutQuery, func(q *client.PreCheckoutQuery) *client.User { return &q.From })
+	case *events.ShippingQueryEvent:
+		return sender(event.ShippingQuery, func(q *client.ShippingQuery) *client.User { return &q.From })
+	case *events.ChatJoinRequestEvent:
+		return sender(event.ChatJoinRequest, func(r *client.ChatJoinRequest) *client.User { return &r.From })
+	default:
+		return nil
+	}
+}
+
+func sender[T any](update *T, from func(*T) *client.User) *client.User {
+	if update == nil {
+		return nil
+	}
+
+	return from(update)
+}
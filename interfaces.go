@@ -1,6 +1,9 @@
 package runtime
 
 import (
+	"context"
+	"encoding/json"
+
 	"github.com/metalagman/appkit/lifecycle"
 	"github.com/tgbotkit/client"
 )
@@ -15,3 +18,19 @@ type UpdateSource interface {
 	// UpdateChan returns a channel that receives updates.
 	UpdateChan() <-chan client.Update
 }
+
+// RawUpdateSource is implemented by update sources that preserve the raw JSON of
+// each update, so fields not yet known to the generated client are not lost.
+type RawUpdateSource interface {
+	// RawUpdate returns the raw JSON of an update received from UpdateChan and forgets it.
+	RawUpdate(updateID int) (json.RawMessage, bool)
+}
+
+// UpdateContextSource is implemented by update sources that attach state of
+// their own to the context handlers get, such as a webhook waiting for a reply
+// to write in its response.
+type UpdateContextSource interface {
+	// UpdateContext returns the context for handling an update received from
+	// UpdateChan and a function the bot calls once the update is handled.
+	UpdateContext(ctx context.Context, updateID int) (context.Context, func())
+}
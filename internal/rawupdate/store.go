@@ -0,0 +1,75 @@
+// Package rawupdate keeps the raw JSON of delivered updates until the consumer claims it.
+package rawupdate
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Store is a bounded map from update ID to raw update JSON. When it is full, the
+// oldest entry is evicted so an update source whose consumer never claims raw
+// JSON does not grow without bound.
+type Store struct {
+	mu       sync.Mutex
+	capacity int
+	raw      map[int]json.RawMessage
+	order    []int
+}
+
+// entriesPerBufferedUpdate leaves room for updates already taken off the channel
+// but not yet claimed, on top of those still buffered.
+const entriesPerBufferedUpdate = 2
+
+// NewStore creates a Store sized for an update channel with the given buffer size.
+func NewStore(bufferSize int) *Store {
+	capacity := max(bufferSize*entriesPerBufferedUpdate, 1)
+
+	return &Store{
+		capacity: capacity,
+		raw:      make(map[int]json.RawMessage, capacity),
+	}
+}
+
+// Put records raw JSON for updateID.
+func (s *Store) Put(updateID int, raw json.RawMessage) {
+	if len(raw) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.raw[updateID]; !ok {
+		s.order = append(s.order, updateID)
+	}
+
+	s.raw[updateID] = raw
+
+	for len(s.order) > s.capacity {
+		delete(s.raw, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// Take returns and forgets the raw JSON recorded for updateID.
+func (s *Store) Take(updateID int) (json.RawMessage, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, ok := s.raw[updateID]
+	if !ok {
+		return nil, false
+	}
+
+	delete(s.raw, updateID)
+
+	for i, id := range s.order {
+		if id == updateID {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+
+			break
+		}
+	}
+
+	return raw, true
+}
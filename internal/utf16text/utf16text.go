@@ -0,0 +1,23 @@
+// Package utf16text converts between Telegram's UTF-16 offsets and Go strings.
+package utf16text
+
+import "unicode/utf16"
+
+// RuneIndex returns the index in runes at which the UTF-16 offset falls.
+// Offsets past the end return len(runes).
+func RuneIndex(runes []rune, offset int) int {
+	if offset <= 0 {
+		return 0
+	}
+
+	units := 0
+	for i, r := range runes {
+		if units >= offset {
+			return i
+		}
+
+		units += utf16.RuneLen(r)
+	}
+
+	return len(runes)
+}
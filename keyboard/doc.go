@@ -0,0 +1,3 @@
+// Package keyboard builds inline and reply keyboards and validates them
+// against Telegram's limits before they are sent.
+package keyboard
@@ -0,0 +1,30 @@
+package keyboard
+
+import "errors"
+
+// ErrEmptyText is returned when a button has no text.
+var ErrEmptyText = errors.New("button text is empty")
+
+// ErrNoAction is returned when an inline button has no action such as callback data or URL.
+var ErrNoAction = errors.New("inline button has no action")
+
+// ErrCallbackDataTooLong is returned when callback data exceeds 64 bytes.
+var ErrCallbackDataTooLong = errors.New("callback data exceeds 64 bytes")
+
+// ErrCopyTextTooLong is returned when copy text exceeds 256 characters.
+var ErrCopyTextTooLong = errors.New("copy text exceeds 256 characters")
+
+// ErrPayNotFirst is returned when a pay button is not the first button of the first row.
+var ErrPayNotFirst = errors.New("pay button must be the first button in the first row")
+
+// ErrRowTooWide is returned when a row has more buttons than Telegram allows.
+var ErrRowTooWide = errors.New("too many buttons in row")
+
+// ErrTooManyButtons is returned when a keyboard has more than 100 buttons.
+var ErrTooManyButtons = errors.New("too many buttons in keyboard")
+
+// ErrEmptyKeyboard is returned when a keyboard has no buttons.
+var ErrEmptyKeyboard = errors.New("keyboard has no buttons")
+
+// ErrPlaceholderTooLong is returned when an input field placeholder exceeds 64 characters.
+var ErrPlaceholderTooLong = errors.New("input field placeholder exceeds 64 characters")
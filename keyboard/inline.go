@@ -0,0 +1,146 @@
+package keyboard
+
+import (
+	"unicode/utf8"
+
+	"github.com/tgbotkit/client"
+)
+
+const (
+	maxInlineRowButtons = 8
+	maxCallbackDataSize = 64
+	maxCopyTextLength   = 256
+)
+
+// InlineBuilder builds an inline keyboard attached to a message.
+type InlineBuilder struct {
+	rows rows[client.InlineKeyboardButton]
+}
+
+// Inline starts an inline keyboard.
+func Inline() *InlineBuilder {
+	return &InlineBuilder{}
+}
+
+// Width wraps buttons added with Add into rows of at most n buttons.
+// Zero, the default, keeps them in one row.
+func (b *InlineBuilder) Width(n int) *InlineBuilder {
+	b.rows.width = n
+
+	return b
+}
+
+// Add appends buttons, wrapping them into new rows at the configured width.
+func (b *InlineBuilder) Add(buttons ...client.InlineKeyboardButton) *InlineBuilder {
+	b.rows.add(buttons)
+
+	return b
+}
+
+// Row appends buttons as a row of their own.
+func (b *InlineBuilder) Row(buttons ...client.InlineKeyboardButton) *InlineBuilder {
+	b.rows.row(buttons)
+
+	return b
+}
+
+// Build validates the keyboard and returns its markup.
+func (b *InlineBuilder) Build() (*client.InlineKeyboardMarkup, error) {
+	if err := b.rows.validate(maxInlineRowButtons, validateInlineButton); err != nil {
+		return nil, err
+	}
+
+	return &client.InlineKeyboardMarkup{InlineKeyboard: b.rows.copyRows()}, nil
+}
+
+// Callback returns a button that sends data in a callback query.
+func Callback(text, data string) client.InlineKeyboardButton {
+	return client.InlineKeyboardButton{Text: text, CallbackData: &data}
+}
+
+// URL returns a button that opens url.
+func URL(text, url string) client.InlineKeyboardButton {
+	return client.InlineKeyboardButton{Text: text, Url: &url}
+}
+
+// WebApp returns a button that launches the Web App at url.
+func WebApp(text, url string) client.InlineKeyboardButton {
+	return client.InlineKeyboardButton{Text: text, WebApp: &client.WebAppInfo{Url: url}}
+}
+
+// Login returns a button that authorizes the user on url via Telegram Login.
+func Login(text, url string) client.InlineKeyboardButton {
+	return client.InlineKeyboardButton{Text: text, LoginUrl: &client.LoginUrl{Url: url}}
+}
+
+// SwitchInline returns a button that lets the user pick a chat and inserts
+// the bot's username and query into the input field.
+func SwitchInline(text, query string) client.InlineKeyboardButton {
+	return client.InlineKeyboardButton{Text: text, SwitchInlineQuery: &query}
+}
+
+// SwitchInlineCurrentChat returns a button that inserts the bot's username
+// and query into the current chat's input field.
+func SwitchInlineCurrentChat(text, query string) client.InlineKeyboardButton {
+	return client.InlineKeyboardButton{Text: text, SwitchInlineQueryCurrentChat: &query}
+}
+
+// SwitchInlineChosenChat returns a button that lets the user pick a chat of
+// the allowed types and inserts the bot's username and query there.
+func SwitchInlineChosenChat(text string, chat client.SwitchInlineQueryChosenChat) client.InlineKeyboardButton {
+	return client.InlineKeyboardButton{Text: text, SwitchInlineQueryChosenChat: &chat}
+}
+
+// CopyText returns a button that copies value to the clipboard.
+func CopyText(text, value string) client.InlineKeyboardButton {
+	return client.InlineKeyboardButton{Text: text, CopyText: &client.CopyTextButton{Text: value}}
+}
+
+// Pay returns a pay button for invoices. It must be the first button of the
+// first row.
+func Pay(text string) client.InlineKeyboardButton {
+	value := true
+
+	return client.InlineKeyboardButton{Text: text, Pay: &value}
+}
+
+func validateInlineButton(row, col int, button client.InlineKeyboardButton) error {
+	if button.Text == "" {
+		return ErrEmptyText
+	}
+
+	if button.CallbackData != nil && len(*button.CallbackData) > maxCallbackDataSize {
+		return ErrCallbackDataTooLong
+	}
+
+	if button.CopyText != nil && utf8.RuneCountInString(button.CopyText.Text) > maxCopyTextLength {
+		return ErrCopyTextTooLong
+	}
+
+	if isPay(button) && (row != 0 || col != 0) {
+		return ErrPayNotFirst
+	}
+
+	if !hasInlineAction(button) {
+		return ErrNoAction
+	}
+
+	return nil
+}
+
+func hasInlineAction(button client.InlineKeyboardButton) bool {
+	return button.CallbackData != nil ||
+		button.Url != nil ||
+		button.WebApp != nil ||
+		button.LoginUrl != nil ||
+		button.SwitchInlineQuery != nil ||
+		button.SwitchInlineQueryCurrentChat != nil ||
+		button.SwitchInlineQueryChosenChat != nil ||
+		button.CopyText != nil ||
+		button.CallbackGame != nil ||
+		isPay(button)
+}
+
+func isPay(button client.InlineKeyboardButton) bool {
+	return button.Pay != nil && *button.Pay
+}
@@ -0,0 +1,150 @@
+package keyboard_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/keyboard"
+)
+
+func TestInlineBuilderWrapsRows(t *testing.T) {
+	t.Parallel()
+
+	markup, err := keyboard.Inline().
+		Width(2).
+		Add(
+			keyboard.Callback("1", "one"),
+			keyboard.Callback("2", "two"),
+			keyboard.Callback("3", "three"),
+		).
+		Row(keyboard.URL("Docs", "https://example.com")).
+		Add(keyboard.CopyText("Copy", "code")).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+
+	var widths []int
+	for _, row := range markup.InlineKeyboard {
+		widths = append(widths, len(row))
+	}
+
+	want := []int{2, 1, 1, 1}
+	if len(widths) != len(want) {
+		t.Fatalf("row widths=%v, want %v", widths, want)
+	}
+	for i := range want {
+		if widths[i] != want[i] {
+			t.Fatalf("row widths=%v, want %v", widths, want)
+		}
+	}
+	if got := *markup.InlineKeyboard[0][1].CallbackData; got != "two" {
+		t.Fatalf("callback data=%q, want two", got)
+	}
+	if got := *markup.InlineKeyboard[2][0].Url; got != "https://example.com" {
+		t.Fatalf("url=%q, want https://example.com", got)
+	}
+}
+
+func TestInlineBuilderValidation(t *testing.T) {
+	t.Parallel()
+
+	manyButtons := make([]client.InlineKeyboardButton, 101)
+	for i := range manyButtons {
+		manyButtons[i] = keyboard.Callback(strconv.Itoa(i), "x")
+	}
+
+	tests := []struct {
+		name    string
+		builder *keyboard.InlineBuilder
+		want    error
+	}{
+		{name: "empty keyboard", builder: keyboard.Inline(), want: keyboard.ErrEmptyKeyboard},
+		{name: "empty text", builder: keyboard.Inline().Add(keyboard.Callback("", "x")), want: keyboard.ErrEmptyText},
+		{
+			name:    "no action",
+			builder: keyboard.Inline().Add(client.InlineKeyboardButton{Text: "x"}),
+			want:    keyboard.ErrNoAction,
+		},
+		{
+			name:    "long callback data",
+			builder: keyboard.Inline().Add(keyboard.Callback("x", strings.Repeat("a", 65))),
+			want:    keyboard.ErrCallbackDataTooLong,
+		},
+		{
+			name:    "pay not first",
+			builder: keyboard.Inline().Add(keyboard.Callback("x", "x"), keyboard.Pay("Pay")),
+			want:    keyboard.ErrPayNotFirst,
+		},
+		{
+			name:    "row too wide",
+			builder: keyboard.Inline().Add(manyButtons[:9]...),
+			want:    keyboard.ErrRowTooWide,
+		},
+		{
+			name:    "too many buttons",
+			builder: keyboard.Inline().Width(5).Add(manyButtons...),
+			want:    keyboard.ErrTooManyButtons,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := tt.builder.Build(); !errors.Is(err, tt.want) {
+				t.Fatalf("Build() error=%v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := keyboard.Inline().Add(keyboard.Pay("Pay"), keyboard.Callback("x", "x")).Build(); err != nil {
+		t.Fatalf("Build() with leading pay button unexpected error: %v", err)
+	}
+}
+
+func TestReplyBuilder(t *testing.T) {
+	t.Parallel()
+
+	markup, err := keyboard.Reply().
+		Width(2).
+		Add(keyboard.Text("Yes"), keyboard.Text("No"), keyboard.RequestContact("Share phone")).
+		Row(keyboard.RequestLocation("Share location")).
+		Resize().
+		OneTime().
+		Placeholder("Pick one").
+		Build()
+	if err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(markup)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+	if got["resize_keyboard"] != true || got["one_time_keyboard"] != true {
+		t.Fatalf("markup=%s, want resize and one-time", data)
+	}
+	if got["input_field_placeholder"] != "Pick one" {
+		t.Fatalf("placeholder=%v, want Pick one", got["input_field_placeholder"])
+	}
+	if rows, _ := got["keyboard"].([]any); len(rows) != 3 {
+		t.Fatalf("rows=%v, want 3", got["keyboard"])
+	}
+	if _, ok := got["is_persistent"]; ok {
+		t.Fatalf("is_persistent set without Persistent(): %s", data)
+	}
+
+	if _, err := keyboard.Reply().Add(keyboard.Text("x")).Placeholder(strings.Repeat("p", 65)).Build(); !errors.Is(err, keyboard.ErrPlaceholderTooLong) {
+		t.Fatalf("Build() error=%v, want ErrPlaceholderTooLong", err)
+	}
+}
@@ -0,0 +1,182 @@
+package keyboard
+
+import (
+	"unicode/utf8"
+
+	"github.com/tgbotkit/client"
+)
+
+const (
+	maxReplyRowButtons   = 12
+	maxPlaceholderLength = 64
+)
+
+// ReplyMarkup is a custom reply keyboard shown instead of the user's keyboard.
+type ReplyMarkup struct {
+	Keyboard              [][]client.KeyboardButton `json:"keyboard"`
+	IsPersistent          bool                      `json:"is_persistent,omitempty"`
+	ResizeKeyboard        bool                      `json:"resize_keyboard,omitempty"`
+	OneTimeKeyboard       bool                      `json:"one_time_keyboard,omitempty"`
+	InputFieldPlaceholder string                    `json:"input_field_placeholder,omitempty"`
+	Selective             bool                      `json:"selective,omitempty"`
+}
+
+// RemoveMarkup removes a custom reply keyboard.
+type RemoveMarkup struct {
+	RemoveKeyboard bool `json:"remove_keyboard"`
+	Selective      bool `json:"selective,omitempty"`
+}
+
+// ForceReplyMarkup shows the reply interface as if the user tapped Reply.
+type ForceReplyMarkup struct {
+	ForceReply            bool   `json:"force_reply"`
+	InputFieldPlaceholder string `json:"input_field_placeholder,omitempty"`
+	Selective             bool   `json:"selective,omitempty"`
+}
+
+// Remove returns markup that removes the current reply keyboard.
+func Remove() *RemoveMarkup {
+	return &RemoveMarkup{RemoveKeyboard: true}
+}
+
+// ForceReply returns markup that asks the user to reply, showing placeholder
+// in the input field when it is not empty.
+func ForceReply(placeholder string) *ForceReplyMarkup {
+	return &ForceReplyMarkup{ForceReply: true, InputFieldPlaceholder: placeholder}
+}
+
+// ReplyBuilder builds a custom reply keyboard.
+type ReplyBuilder struct {
+	rows   rows[client.KeyboardButton]
+	markup ReplyMarkup
+}
+
+// Reply starts a reply keyboard.
+func Reply() *ReplyBuilder {
+	return &ReplyBuilder{}
+}
+
+// Width wraps buttons added with Add into rows of at most n buttons.
+// Zero, the default, keeps them in one row.
+func (b *ReplyBuilder) Width(n int) *ReplyBuilder {
+	b.rows.width = n
+
+	return b
+}
+
+// Add appends buttons, wrapping them into new rows at the configured width.
+func (b *ReplyBuilder) Add(buttons ...client.KeyboardButton) *ReplyBuilder {
+	b.rows.add(buttons)
+
+	return b
+}
+
+// Row appends buttons as a row of their own.
+func (b *ReplyBuilder) Row(buttons ...client.KeyboardButton) *ReplyBuilder {
+	b.rows.row(buttons)
+
+	return b
+}
+
+// Resize asks clients to fit the keyboard height to its rows.
+func (b *ReplyBuilder) Resize() *ReplyBuilder {
+	b.markup.ResizeKeyboard = true
+
+	return b
+}
+
+// OneTime hides the keyboard after a button is pressed.
+func (b *ReplyBuilder) OneTime() *ReplyBuilder {
+	b.markup.OneTimeKeyboard = true
+
+	return b
+}
+
+// Persistent keeps the keyboard shown when the regular keyboard is hidden.
+func (b *ReplyBuilder) Persistent() *ReplyBuilder {
+	b.markup.IsPersistent = true
+
+	return b
+}
+
+// Selective shows the keyboard only to mentioned users or the replied-to sender.
+func (b *ReplyBuilder) Selective() *ReplyBuilder {
+	b.markup.Selective = true
+
+	return b
+}
+
+// Placeholder sets the input field placeholder shown while the keyboard is active.
+func (b *ReplyBuilder) Placeholder(text string) *ReplyBuilder {
+	b.markup.InputFieldPlaceholder = text
+
+	return b
+}
+
+// Build validates the keyboard and returns its markup.
+func (b *ReplyBuilder) Build() (*ReplyMarkup, error) {
+	if utf8.RuneCountInString(b.markup.InputFieldPlaceholder) > maxPlaceholderLength {
+		return nil, ErrPlaceholderTooLong
+	}
+
+	err := b.rows.validate(maxReplyRowButtons, func(_, _ int, button client.KeyboardButton) error {
+		if button.Text == "" {
+			return ErrEmptyText
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	markup := b.markup
+	markup.Keyboard = b.rows.copyRows()
+
+	return &markup, nil
+}
+
+// Text returns a button that sends its text as a message.
+func Text(text string) client.KeyboardButton {
+	return client.KeyboardButton{Text: text}
+}
+
+// RequestContact returns a button that shares the user's phone number.
+func RequestContact(text string) client.KeyboardButton {
+	value := true
+
+	return client.KeyboardButton{Text: text, RequestContact: &value}
+}
+
+// RequestLocation returns a button that shares the user's location.
+func RequestLocation(text string) client.KeyboardButton {
+	value := true
+
+	return client.KeyboardButton{Text: text, RequestLocation: &value}
+}
+
+// RequestUsers returns a button that lets the user pick users to share.
+func RequestUsers(text string, request client.KeyboardButtonRequestUsers) client.KeyboardButton {
+	return client.KeyboardButton{Text: text, RequestUsers: &request}
+}
+
+// RequestChat returns a button that lets the user pick a chat to share.
+func RequestChat(text string, request client.KeyboardButtonRequestChat) client.KeyboardButton {
+	return client.KeyboardButton{Text: text, RequestChat: &request}
+}
+
+// RequestPoll returns a button that opens poll creation. pollType is "quiz",
+// "regular", or empty to allow both.
+func RequestPoll(text, pollType string) client.KeyboardButton {
+	request := client.KeyboardButtonPollType{}
+	if pollType != "" {
+		request.Type = &pollType
+	}
+
+	return client.KeyboardButton{Text: text, RequestPoll: &request}
+}
+
+// ReplyWebApp returns a reply button that launches the Web App at url.
+func ReplyWebApp(text, url string) client.KeyboardButton {
+	return client.KeyboardButton{Text: text, WebApp: &client.WebAppInfo{Url: url}}
+}
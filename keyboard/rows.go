@@ -0,0 +1,74 @@
+package keyboard
+
+import "fmt"
+
+const maxButtons = 100
+
+// rows collects buttons into rows, wrapping buttons added with add at width.
+type rows[T any] struct {
+	rows  [][]T
+	width int
+	open  bool
+}
+
+// add appends buttons to the open row, starting a new row whenever the open
+// row has width buttons. A width of zero never wraps.
+func (r *rows[T]) add(buttons []T) {
+	for _, button := range buttons {
+		if !r.open || (r.width > 0 && len(r.rows[len(r.rows)-1]) >= r.width) {
+			r.rows = append(r.rows, nil)
+			r.open = true
+		}
+
+		last := len(r.rows) - 1
+		r.rows[last] = append(r.rows[last], button)
+	}
+}
+
+// row appends buttons as a complete row; later add calls start a new row.
+func (r *rows[T]) row(buttons []T) {
+	if len(buttons) > 0 {
+		r.rows = append(r.rows, append([]T(nil), buttons...))
+	}
+
+	r.open = false
+}
+
+// validate checks the layout and each button with check.
+func (r *rows[T]) validate(maxRowButtons int, check func(row, col int, button T) error) error {
+	total := 0
+
+	for i, row := range r.rows {
+		if len(row) > maxRowButtons {
+			return fmt.Errorf("row %d: %w: %d > %d", i, ErrRowTooWide, len(row), maxRowButtons)
+		}
+
+		for j, button := range row {
+			if err := check(i, j, button); err != nil {
+				return fmt.Errorf("row %d button %d: %w", i, j, err)
+			}
+		}
+
+		total += len(row)
+	}
+
+	if total == 0 {
+		return ErrEmptyKeyboard
+	}
+
+	if total > maxButtons {
+		return fmt.Errorf("%w: %d > %d", ErrTooManyButtons, total, maxButtons)
+	}
+
+	return nil
+}
+
+// copyRows returns the rows without sharing backing arrays with the builder.
+func (r *rows[T]) copyRows() [][]T {
+	out := make([][]T, 0, len(r.rows))
+	for _, row := range r.rows {
+		out = append(out, append([]T(nil), row...))
+	}
+
+	return out
+}
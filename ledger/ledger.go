@@ -0,0 +1,332 @@
+// Package ledger keeps account of the Telegram Stars users pay to the bot, for
+// digital goods and subscriptions.
+//
+// A Ledger registered on a handlers.Registry records Stars payments, refunds
+// and paid media purchases from updates, and follows subscription changes.
+// Reconcile pages through the bot's Star transactions to catch up on the
+// updates that were missed, such as while the bot was down. Balance and
+// Entitled answer what a user paid and what they have access to.
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/handlers"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+// currencyStars is the currency code of payments in Telegram Stars.
+const currencyStars = "XTR"
+
+// ErrNilClient is returned by Reconcile when the ledger has no Telegram API client.
+var ErrNilClient = errors.New("nil telegram client")
+
+// Ledger records Stars transactions and subscriptions.
+type Ledger struct {
+	opts Options
+	log  logger.Logger
+
+	// mu serializes the read-modify-write cycles on the store.
+	mu sync.Mutex
+
+	// reconcileMu serializes the calls to Reconcile.
+	reconcileMu sync.Mutex
+}
+
+// New creates a new Ledger with the given options.
+func New(opts Options) (*Ledger, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ledger options: %w", err)
+	}
+
+	if opts.logger == nil {
+		opts.logger = logger.NewNop()
+	}
+
+	return &Ledger{
+		opts: opts,
+		log:  opts.logger,
+	}, nil
+}
+
+// Register makes the ledger record the payments, refunds, paid media
+// purchases and subscription changes received by registry. It returns a
+// function that unregisters the handlers.
+func (l *Ledger) Register(registry *handlers.Registry) eventemitter.UnsubscribeFunc {
+	unsubscribe := []eventemitter.UnsubscribeFunc{
+		registry.OnSuccessfulPayment(l.handleSuccessfulPayment),
+		registry.OnRefundedPayment(l.handleRefundedPayment),
+		registry.OnPurchasedPaidMedia(l.handlePurchasedPaidMedia),
+		registry.OnSubscription(l.handleSubscription),
+	}
+
+	return func() {
+		for _, fn := range unsubscribe {
+			fn()
+		}
+	}
+}
+
+// Balance returns the net number of Stars userID paid: payments and paid
+// media purchases less refunds.
+func (l *Ledger) Balance(ctx context.Context, userID int64) (int, error) {
+	txs, err := l.opts.store.Transactions(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("load transactions of user %d: %w", userID, err)
+	}
+
+	balance := 0
+
+	for _, tx := range txs {
+		if tx.Kind == KindRefund {
+			balance -= tx.Amount
+		} else {
+			balance += tx.Amount
+		}
+	}
+
+	return balance, nil
+}
+
+// Entitled reports whether userID has access to the product of payload: a
+// subscription to it that has not ended, or a purchase of it that was not
+// refunded.
+func (l *Ledger) Entitled(ctx context.Context, userID int64, payload string) (bool, error) {
+	sub, found, err := l.opts.store.Subscription(ctx, userID, payload)
+	if err != nil {
+		return false, fmt.Errorf("load subscription of user %d: %w", userID, err)
+	}
+
+	if found {
+		return sub.State != SubscriptionExpired && time.Now().Before(sub.ExpiresAt), nil
+	}
+
+	txs, err := l.opts.store.Transactions(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("load transactions of user %d: %w", userID, err)
+	}
+
+	return purchased(txs, payload), nil
+}
+
+// purchased reports whether txs hold a purchase of payload that was not
+// refunded.
+func purchased(txs []Transaction, payload string) bool {
+	refunded := make(map[string]bool)
+
+	for _, tx := range txs {
+		if tx.Kind == KindRefund {
+			refunded[tx.ChargeID] = true
+		}
+	}
+
+	for _, tx := range txs {
+		if tx.Kind != KindRefund && tx.Payload == payload && (tx.ChargeID == "" || !refunded[tx.ChargeID]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *Ledger) handleSuccessfulPayment(ctx context.Context, event *events.SuccessfulPaymentEvent) error {
+	payment := event.Payment
+	if payment == nil || event.Message == nil || payment.Currency != currencyStars {
+		return nil
+	}
+
+	tx := Transaction{
+		ID:       paymentID(payment.TelegramPaymentChargeId),
+		Kind:     KindPayment,
+		ChargeID: payment.TelegramPaymentChargeId,
+		UserID:   payer(event.Message),
+		Payload:  payment.InvoicePayload,
+		Amount:   payment.TotalAmount,
+		Date:     time.Unix(int64(event.Message.Date), 0),
+	}
+
+	if payment.SubscriptionExpirationDate != nil {
+		tx.ExpiresAt = time.Unix(int64(*payment.SubscriptionExpirationDate), 0)
+	}
+
+	_, err := l.record(ctx, tx)
+
+	return err
+}
+
+func (l *Ledger) handleRefundedPayment(ctx context.Context, event *events.RefundedPaymentEvent) error {
+	refund := event.Payment
+	if refund == nil || event.Message == nil || refund.Currency != currencyStars {
+		return nil
+	}
+
+	_, err := l.record(ctx, Transaction{
+		ID:       refundID(refund.TelegramPaymentChargeId),
+		Kind:     KindRefund,
+		ChargeID: refund.TelegramPaymentChargeId,
+		UserID:   payer(event.Message),
+		Payload:  refund.InvoicePayload,
+		Amount:   refund.TotalAmount,
+		Date:     time.Unix(int64(event.Message.Date), 0),
+	})
+
+	return err
+}
+
+func (l *Ledger) handlePurchasedPaidMedia(ctx context.Context, event *events.PurchasedPaidMediaEvent) error {
+	purchase := event.PurchasedPaidMedia
+	if purchase == nil {
+		return nil
+	}
+
+	_, err := l.record(ctx, Transaction{
+		ID:      paidMediaID(purchase.From.Id, purchase.PaidMediaPayload),
+		Kind:    KindPaidMedia,
+		UserID:  purchase.From.Id,
+		Payload: purchase.PaidMediaPayload,
+		Date:    time.Now(),
+	})
+
+	return err
+}
+
+func (l *Ledger) handleSubscription(ctx context.Context, event *events.SubscriptionEvent) error {
+	update := event.Subscription
+	if update == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sub, found, err := l.opts.store.Subscription(ctx, update.User.Id, update.InvoicePayload)
+	if err != nil {
+		return fmt.Errorf("load subscription of user %d: %w", update.User.Id, err)
+	}
+
+	if found && sub.State == SubscriptionExpired {
+		return nil
+	}
+
+	sub.UserID = update.User.Id
+	sub.Payload = update.InvoicePayload
+	sub.State = SubscriptionState(update.State)
+
+	return l.saveSubscription(ctx, sub)
+}
+
+// record saves tx unless it is known, and reports whether it was saved. A
+// reconciled paid media purchase replaces the one recorded from its update,
+// which lacks the price.
+func (l *Ledger) record(ctx context.Context, tx Transaction) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	existing, found, err := l.opts.store.Transaction(ctx, tx.ID)
+	if err != nil {
+		return false, fmt.Errorf("load transaction %s: %w", tx.ID, err)
+	}
+
+	if found && (existing.Amount != 0 || tx.Amount == 0) {
+		return false, nil
+	}
+
+	if err := l.opts.store.SaveTransaction(ctx, tx); err != nil {
+		return false, fmt.Errorf("save transaction %s: %w", tx.ID, err)
+	}
+
+	switch {
+	case tx.Kind == KindPayment && !tx.ExpiresAt.IsZero():
+		err = l.renew(ctx, tx)
+	case tx.Kind == KindRefund:
+		err = l.refund(ctx, tx)
+	}
+
+	return true, err
+}
+
+// renew extends the subscription paid by tx. Payments reconciled out of
+// order do not shorten it.
+func (l *Ledger) renew(ctx context.Context, tx Transaction) error {
+	sub, found, err := l.opts.store.Subscription(ctx, tx.UserID, tx.Payload)
+	if err != nil {
+		return fmt.Errorf("load subscription of user %d: %w", tx.UserID, err)
+	}
+
+	if found && !sub.ExpiresAt.Before(tx.ExpiresAt) {
+		return nil
+	}
+
+	return l.saveSubscription(ctx, Subscription{
+		UserID:    tx.UserID,
+		Payload:   tx.Payload,
+		State:     SubscriptionActive,
+		ExpiresAt: tx.ExpiresAt,
+		ChargeID:  tx.ChargeID,
+	})
+}
+
+// refund ends the subscription whose last payment tx refunds.
+func (l *Ledger) refund(ctx context.Context, tx Transaction) error {
+	sub, found, err := l.opts.store.Subscription(ctx, tx.UserID, tx.Payload)
+	if err != nil {
+		return fmt.Errorf("load subscription of user %d: %w", tx.UserID, err)
+	}
+
+	if !found || sub.ChargeID != tx.ChargeID || sub.State == SubscriptionExpired {
+		return nil
+	}
+
+	return l.expire(ctx, sub)
+}
+
+func (l *Ledger) expire(ctx context.Context, sub Subscription) error {
+	sub.State = SubscriptionExpired
+
+	if err := l.saveSubscription(ctx, sub); err != nil {
+		return err
+	}
+
+	if l.opts.onExpired != nil {
+		l.opts.onExpired(ctx, sub)
+	}
+
+	return nil
+}
+
+func (l *Ledger) saveSubscription(ctx context.Context, sub Subscription) error {
+	if err := l.opts.store.SaveSubscription(ctx, sub); err != nil {
+		return fmt.Errorf("save subscription of user %d: %w", sub.UserID, err)
+	}
+
+	return nil
+}
+
+// payer returns the user who sent a payment service message.
+func payer(message *client.Message) int64 {
+	if message.From != nil {
+		return message.From.Id
+	}
+
+	return message.Chat.Id
+}
+
+func paymentID(chargeID string) string {
+	return "payment:" + chargeID
+}
+
+func refundID(chargeID string) string {
+	return "refund:" + chargeID
+}
+
+func paidMediaID(userID int64, payload string) string {
+	return "paid_media:" + strconv.FormatInt(userID, 10) + ":" + payload
+}
@@ -0,0 +1,279 @@
+package ledger_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/handlers"
+	"github.com/tgbotkit/runtime/ledger"
+	"github.com/tgbotkit/runtime/ledger/ledgerstore"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+type mockClient struct {
+	client.ClientWithResponsesInterface
+
+	transactions []client.StarTransaction
+	offsets      []int
+}
+
+func (m *mockClient) GetStarTransactionsWithResponse(
+	_ context.Context,
+	body client.GetStarTransactionsJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.GetStarTransactionsResponse, error) {
+	offset := *body.Offset
+	m.offsets = append(m.offsets, offset)
+	end := min(offset+*body.Limit, len(m.transactions))
+
+	resp := &client.GetStarTransactionsResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}}
+	resp.JSON200 = &struct {
+		Ok     client.GetStarTransactions200Ok `json:"ok"`
+		Result client.StarTransactions         `json:"result"`
+	}{Ok: true, Result: client.StarTransactions{Transactions: m.transactions[min(offset, end):end]}}
+
+	return resp, nil
+}
+
+func newLedger(t *testing.T, opts ...ledger.OptOptionsSetter) (*ledger.Ledger, eventemitter.EventEmitter) {
+	t.Helper()
+
+	ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+	if err != nil {
+		t.Fatalf("NewSync() unexpected error: %v", err)
+	}
+
+	l, err := ledger.New(ledger.NewOptions(ledgerstore.NewInMemoryStore(), opts...))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	l.Register(handlers.NewRegistry(ee, logger.NewNop()))
+
+	return l, ee
+}
+
+func paymentMessage(userID int64, payment client.SuccessfulPayment) *events.SuccessfulPaymentEvent {
+	msg := &client.Message{From: &client.User{Id: userID}, Chat: client.Chat{Id: userID}, Date: 1700000000}
+
+	return &events.SuccessfulPaymentEvent{Message: msg, Payment: &payment}
+}
+
+func userPartner(transactionType string, userID int64, fields map[string]any) *client.TransactionPartner {
+	partner := client.TransactionPartner{
+		"type":             "user",
+		"transaction_type": transactionType,
+		"user":             map[string]any{"id": userID},
+	}
+
+	for key, value := range fields {
+		partner[key] = value
+	}
+
+	return &partner
+}
+
+func TestLedgerRecordsPaymentsAndRefunds(t *testing.T) {
+	t.Parallel()
+
+	l, ee := newLedger(t)
+	ctx := context.Background()
+
+	payment := client.SuccessfulPayment{
+		Currency:                "XTR",
+		InvoicePayload:          "sticker-pack",
+		TelegramPaymentChargeId: "charge-1",
+		TotalAmount:             100,
+	}
+
+	// Redelivered updates are recorded once, and fiat payments not at all.
+	ee.Emit(ctx, events.OnSuccessfulPayment, paymentMessage(7, payment))
+	ee.Emit(ctx, events.OnSuccessfulPayment, paymentMessage(7, payment))
+	ee.Emit(ctx, events.OnSuccessfulPayment, paymentMessage(7, client.SuccessfulPayment{
+		Currency: "USD", InvoicePayload: "t-shirt", TelegramPaymentChargeId: "charge-2", TotalAmount: 1500,
+	}))
+
+	if balance, _ := l.Balance(ctx, 7); balance != 100 {
+		t.Fatalf("Balance()=%d, want 100", balance)
+	}
+	if ok, _ := l.Entitled(ctx, 7, "sticker-pack"); !ok {
+		t.Fatal("Entitled() = false after payment")
+	}
+
+	ee.Emit(ctx, events.OnRefundedPayment, &events.RefundedPaymentEvent{
+		Message: &client.Message{From: &client.User{Id: 7}, Chat: client.Chat{Id: 7}},
+		Payment: &client.RefundedPayment{
+			Currency:                "XTR",
+			InvoicePayload:          "sticker-pack",
+			TelegramPaymentChargeId: "charge-1",
+			TotalAmount:             100,
+		},
+	})
+
+	if balance, _ := l.Balance(ctx, 7); balance != 0 {
+		t.Fatalf("Balance()=%d after refund, want 0", balance)
+	}
+	if ok, _ := l.Entitled(ctx, 7, "sticker-pack"); ok {
+		t.Fatal("Entitled() = true after refund")
+	}
+}
+
+func TestLedgerTracksSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	var expired []ledger.Subscription
+
+	l, ee := newLedger(t, ledger.WithOnExpired(func(_ context.Context, sub ledger.Subscription) {
+		expired = append(expired, sub)
+	}))
+	ctx := context.Background()
+
+	subscribe := func(userID int64, charge string, expiresAt time.Time) {
+		expiration := int(expiresAt.Unix())
+		recurring := true
+
+		ee.Emit(ctx, events.OnSuccessfulPayment, paymentMessage(userID, client.SuccessfulPayment{
+			Currency:                   "XTR",
+			InvoicePayload:             "pro",
+			TelegramPaymentChargeId:    charge,
+			TotalAmount:                250,
+			IsRecurring:                &recurring,
+			SubscriptionExpirationDate: &expiration,
+		}))
+	}
+
+	subscribe(1, "charge-1", time.Now().Add(time.Hour))
+	subscribe(2, "charge-2", time.Now().Add(-time.Minute))
+
+	// A canceled subscription lasts until the end of its period.
+	ee.Emit(ctx, events.OnSubscription, &events.SubscriptionEvent{
+		Subscription: &client.BotSubscriptionUpdated{User: client.User{Id: 1}, InvoicePayload: "pro", State: "canceled"},
+	})
+
+	if ok, _ := l.Entitled(ctx, 1, "pro"); !ok {
+		t.Fatal("Entitled(1) = false for a canceled subscription within its period")
+	}
+	if ok, _ := l.Entitled(ctx, 2, "pro"); ok {
+		t.Fatal("Entitled(2) = true for an ended subscription")
+	}
+
+	n, err := l.ExpireSubscriptions(ctx)
+	if err != nil {
+		t.Fatalf("ExpireSubscriptions() unexpected error: %v", err)
+	}
+	if n != 1 || len(expired) != 1 || expired[0].UserID != 2 || expired[0].State != ledger.SubscriptionExpired {
+		t.Fatalf("expired=%+v, want the subscription of user 2", expired)
+	}
+
+	if n, _ := l.ExpireSubscriptions(ctx); n != 0 {
+		t.Fatalf("ExpireSubscriptions() again expired %d, want 0", n)
+	}
+}
+
+func TestLedgerReconcileResumesAcrossRestarts(t *testing.T) {
+	t.Parallel()
+
+	api := &mockClient{}
+	for i := range 150 {
+		api.transactions = append(api.transactions, client.StarTransaction{
+			Id:     fmt.Sprintf("charge-%d", i),
+			Amount: 10,
+			Source: userPartner("invoice_payment", 1, map[string]any{"invoice_payload": "coins"}),
+		})
+	}
+
+	store := ledgerstore.NewInMemoryStore()
+	ctx := context.Background()
+
+	for range 2 {
+		// A new ledger on the same store stands for a restarted bot.
+		l, err := ledger.New(ledger.NewOptions(store, ledger.WithClient(api)))
+		if err != nil {
+			t.Fatalf("New() unexpected error: %v", err)
+		}
+
+		if _, err := l.Reconcile(ctx); err != nil {
+			t.Fatalf("Reconcile() unexpected error: %v", err)
+		}
+	}
+
+	if want := []int{0, 100, 150}; fmt.Sprint(api.offsets) != fmt.Sprint(want) {
+		t.Fatalf("offsets=%v, want %v", api.offsets, want)
+	}
+}
+
+func TestLedgerReconcile(t *testing.T) {
+	t.Parallel()
+
+	api := &mockClient{}
+	for i := range 100 {
+		api.transactions = append(api.transactions, client.StarTransaction{
+			Id:     fmt.Sprintf("charge-%d", i),
+			Amount: 10,
+			Date:   1700000000 + i,
+			Source: userPartner("invoice_payment", 1, map[string]any{"invoice_payload": "coins"}),
+		})
+	}
+
+	api.transactions = append(api.transactions,
+		client.StarTransaction{
+			Id:     "media-1",
+			Amount: 40,
+			Source: userPartner("paid_media_payment", 2, map[string]any{"paid_media_payload": "album"}),
+		},
+		client.StarTransaction{
+			Id:       "charge-0",
+			Amount:   10,
+			Receiver: userPartner("invoice_payment", 1, map[string]any{"invoice_payload": "coins"}),
+		},
+		client.StarTransaction{
+			Id:       "withdrawal",
+			Amount:   500,
+			Receiver: &client.TransactionPartner{"type": "fragment"},
+		},
+	)
+
+	l, ee := newLedger(t, ledger.WithClient(api))
+	ctx := context.Background()
+
+	// The update of the paid media purchase lacks its price.
+	ee.Emit(ctx, events.OnPurchasedPaidMedia, &events.PurchasedPaidMediaEvent{
+		PurchasedPaidMedia: &client.PaidMediaPurchased{From: client.User{Id: 2}, PaidMediaPayload: "album"},
+	})
+
+	if balance, _ := l.Balance(ctx, 2); balance != 0 {
+		t.Fatalf("Balance(2)=%d before reconciling, want 0", balance)
+	}
+
+	recorded, err := l.Reconcile(ctx)
+	if err != nil {
+		t.Fatalf("Reconcile() unexpected error: %v", err)
+	}
+	if recorded != 102 {
+		t.Fatalf("Reconcile() recorded %d, want 102", recorded)
+	}
+
+	if balance, _ := l.Balance(ctx, 1); balance != 990 {
+		t.Fatalf("Balance(1)=%d, want 990", balance)
+	}
+	if balance, _ := l.Balance(ctx, 2); balance != 40 {
+		t.Fatalf("Balance(2)=%d, want 40", balance)
+	}
+	if ok, _ := l.Entitled(ctx, 2, "album"); !ok {
+		t.Fatal("Entitled(2) = false for purchased paid media")
+	}
+
+	if recorded, _ := l.Reconcile(ctx); recorded != 0 {
+		t.Fatalf("Reconcile() again recorded %d, want 0", recorded)
+	}
+
+	if last := api.offsets[len(api.offsets)-1]; last != len(api.transactions) {
+		t.Fatalf("last offset=%d, want %d", last, len(api.transactions))
+	}
+}
@@ -0,0 +1,143 @@
+// Package ledgerstore provides ledger.Store implementations.
+package ledgerstore
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tgbotkit/runtime/ledger"
+)
+
+// InMemoryStore keeps the ledger in memory. It is lost on restart, so it
+// suits tests and bots that rebuild the ledger with Reconcile on startup.
+type InMemoryStore struct {
+	mu            sync.RWMutex
+	transactions  map[string]ledger.Transaction
+	byUser        map[int64][]string
+	subscriptions map[subscriptionKey]ledger.Subscription
+	offset        int
+}
+
+type subscriptionKey struct {
+	userID  int64
+	payload string
+}
+
+var _ ledger.Store = (*InMemoryStore)(nil)
+
+// NewInMemoryStore creates a new InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		transactions:  make(map[string]ledger.Transaction),
+		byUser:        make(map[int64][]string),
+		subscriptions: make(map[subscriptionKey]ledger.Subscription),
+	}
+}
+
+// Transaction returns the transaction with id and whether it exists.
+func (s *InMemoryStore) Transaction(_ context.Context, id string) (ledger.Transaction, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tx, ok := s.transactions[id]
+
+	return tx, ok, nil
+}
+
+// SaveTransaction records tx, replacing a transaction with the same ID.
+func (s *InMemoryStore) SaveTransaction(_ context.Context, tx ledger.Transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.transactions[tx.ID]; !ok {
+		s.byUser[tx.UserID] = append(s.byUser[tx.UserID], tx.ID)
+	}
+
+	s.transactions[tx.ID] = tx
+
+	return nil
+}
+
+// Transactions returns the transactions of userID in the order they were
+// first saved.
+func (s *InMemoryStore) Transactions(_ context.Context, userID int64) ([]ledger.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.byUser[userID]
+	txs := make([]ledger.Transaction, 0, len(ids))
+
+	for _, id := range ids {
+		txs = append(txs, s.transactions[id])
+	}
+
+	return txs, nil
+}
+
+// Subscription returns the subscription of userID to payload and whether it
+// exists.
+func (s *InMemoryStore) Subscription(
+	_ context.Context,
+	userID int64,
+	payload string,
+) (ledger.Subscription, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sub, ok := s.subscriptions[subscriptionKey{userID: userID, payload: payload}]
+
+	return sub, ok, nil
+}
+
+// SaveSubscription records sub, replacing the one of the same user and
+// payload.
+func (s *InMemoryStore) SaveSubscription(_ context.Context, sub ledger.Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscriptions[subscriptionKey{userID: sub.UserID, payload: sub.Payload}] = sub
+
+	return nil
+}
+
+// ExpiredSubscriptions returns the subscriptions that ended before now and
+// are not in the SubscriptionExpired state, earliest first.
+func (s *InMemoryStore) ExpiredSubscriptions(_ context.Context, now time.Time) ([]ledger.Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var subs []ledger.Subscription
+
+	for _, sub := range s.subscriptions {
+		if sub.State != ledger.SubscriptionExpired && !sub.ExpiresAt.IsZero() && sub.ExpiresAt.Before(now) {
+			subs = append(subs, sub)
+		}
+	}
+
+	slices.SortFunc(subs, func(a, b ledger.Subscription) int {
+		return a.ExpiresAt.Compare(b.ExpiresAt)
+	})
+
+	return subs, nil
+}
+
+// ReconcileOffset returns how many Star transactions Reconcile went through.
+func (s *InMemoryStore) ReconcileOffset(context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.offset, nil
+}
+
+// SaveReconcileOffset records how many Star transactions Reconcile went
+// through.
+func (s *InMemoryStore) SaveReconcileOffset(_ context.Context, offset int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.offset = offset
+
+	return nil
+}
@@ -0,0 +1,78 @@
+// Code generated by options-gen v0.55.3. DO NOT EDIT.
+
+package ledger
+
+import (
+	"context"
+	fmt461e464ebed9 "fmt"
+	"time"
+
+	errors461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/errors"
+	validator461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/validator"
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+type OptOptionsSetter func(o *Options)
+
+func NewOptions(
+	store Store,
+	options ...OptOptionsSetter,
+) Options {
+	var o Options
+
+	// Setting defaults from field tag (if present)
+
+	o.interval, _ = time.ParseDuration("1h")
+
+	o.store = store
+
+	for _, opt := range options {
+		opt(&o)
+	}
+	return o
+}
+
+// client is the Telegram API client used by Reconcile. Without it, the
+// ledger only records the payments it receives as updates.
+func WithClient(opt client.ClientWithResponsesInterface) OptOptionsSetter {
+	return func(o *Options) { o.client = opt }
+}
+
+// interval is how often Run reconciles the ledger and expires
+// subscriptions.
+func WithInterval(opt time.Duration) OptOptionsSetter {
+	return func(o *Options) { o.interval = opt }
+}
+
+// onExpired is called for every subscription that ended or was refunded,
+// such as to revoke access to the product.
+func WithOnExpired(opt func(ctx context.Context, sub Subscription)) OptOptionsSetter {
+	return func(o *Options) { o.onExpired = opt }
+}
+
+// logger is the logger to use.
+func WithLogger(opt logger.Logger) OptOptionsSetter {
+	return func(o *Options) { o.logger = opt }
+}
+
+func (o *Options) Validate() error {
+	errs := new(errors461e464ebed9.ValidationErrors)
+	errs.Add(errors461e464ebed9.NewValidationError("store", _validate_Options_store(o)))
+	errs.Add(errors461e464ebed9.NewValidationError("interval", _validate_Options_interval(o)))
+	return errs.AsError()
+}
+
+func _validate_Options_store(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.store, "required"); err != nil {
+		return fmt461e464ebed9.Errorf("field `store` did not pass the test: %w", err)
+	}
+	return nil
+}
+
+func _validate_Options_interval(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.interval, "gt=0"); err != nil {
+		return fmt461e464ebed9.Errorf("field `interval` did not pass the test: %w", err)
+	}
+	return nil
+}
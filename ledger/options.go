@@ -0,0 +1,28 @@
+package ledger
+
+import (
+	"context"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+//go:generate go tool options-gen -out-filename=options.gen.go -from-struct=Options
+
+// Options is the options for the Ledger.
+type Options struct {
+	// store persists the transactions and subscriptions.
+	store Store `option:"mandatory" validate:"required"`
+	// client is the Telegram API client used by Reconcile. Without it, the
+	// ledger only records the payments it receives as updates.
+	client client.ClientWithResponsesInterface
+	// interval is how often Run reconciles the ledger and expires
+	// subscriptions.
+	interval time.Duration `default:"1h" validate:"gt=0"`
+	// onExpired is called for every subscription that ended or was refunded,
+	// such as to revoke access to the product.
+	onExpired func(ctx context.Context, sub Subscription)
+	// logger is the logger to use.
+	logger logger.Logger
+}
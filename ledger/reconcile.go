@@ -0,0 +1,230 @@
+package ledger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/botapi"
+)
+
+// starTransactionsPage is how many Star transactions getStarTransactions
+// returns at most.
+const starTransactionsPage = 100
+
+// Run reconciles the ledger, when it has a client, and expires the ended
+// subscriptions at the configured interval until ctx ends. Failures are
+// logged and retried at the next interval.
+func (l *Ledger) Run(ctx context.Context) error {
+	ticker := time.NewTicker(l.opts.interval)
+	defer ticker.Stop()
+
+	for {
+		if l.opts.client != nil {
+			if _, err := l.Reconcile(ctx); err != nil && ctx.Err() == nil {
+				l.log.Errorf("%v", err)
+			}
+		}
+
+		if _, err := l.ExpireSubscriptions(ctx); err != nil && ctx.Err() == nil {
+			l.log.Errorf("%v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile records the Stars payments, refunds and paid media purchases
+// listed by getStarTransactions that the ledger does not know, and returns
+// how many it recorded. Transactions are paged through in chronological
+// order, resuming after the last one seen. The position is kept in the
+// store after every page, so a restarted bot does not go through the whole
+// history again.
+func (l *Ledger) Reconcile(ctx context.Context) (int, error) {
+	if l.opts.client == nil {
+		return 0, fmt.Errorf("reconcile star transactions: %w", ErrNilClient)
+	}
+
+	l.reconcileMu.Lock()
+	defer l.reconcileMu.Unlock()
+
+	offset, err := l.opts.store.ReconcileOffset(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("load reconcile offset: %w", err)
+	}
+
+	recorded := 0
+
+	for {
+		page, err := l.starTransactions(ctx, offset)
+		if err != nil {
+			return recorded, err
+		}
+
+		saved, err := l.recordPage(ctx, page)
+		recorded += saved
+
+		if err != nil {
+			return recorded, err
+		}
+
+		offset += len(page)
+
+		if err := l.opts.store.SaveReconcileOffset(ctx, offset); err != nil {
+			return recorded, fmt.Errorf("save reconcile offset: %w", err)
+		}
+
+		if len(page) < starTransactionsPage {
+			return recorded, nil
+		}
+	}
+}
+
+// recordPage records the transactions of page the ledger does not know and
+// returns how many it recorded.
+func (l *Ledger) recordPage(ctx context.Context, page []client.StarTransaction) (int, error) {
+	recorded := 0
+
+	for _, st := range page {
+		tx, ok := fromStarTransaction(st)
+		if !ok {
+			continue
+		}
+
+		saved, err := l.record(ctx, tx)
+		if err != nil {
+			return recorded, err
+		}
+
+		if saved {
+			recorded++
+		}
+	}
+
+	return recorded, nil
+}
+
+// ExpireSubscriptions marks the subscriptions that ended as expired, calling
+// the expiry callback for each, and returns how many it expired.
+func (l *Ledger) ExpireSubscriptions(ctx context.Context) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	subs, err := l.opts.store.ExpiredSubscriptions(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("load expired subscriptions: %w", err)
+	}
+
+	for i, sub := range subs {
+		if err := l.expire(ctx, sub); err != nil {
+			return i, err
+		}
+	}
+
+	return len(subs), nil
+}
+
+func (l *Ledger) starTransactions(ctx context.Context, offset int) ([]client.StarTransaction, error) {
+	limit := starTransactionsPage
+
+	resp, err := l.opts.client.GetStarTransactionsWithResponse(ctx, client.GetStarTransactionsJSONRequestBody{
+		Offset: &offset,
+		Limit:  &limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get star transactions: %w", err)
+	}
+
+	if resp.JSON200 == nil {
+		if apiErr := botapi.FromResponse(resp.StatusCode(), resp.Body); apiErr != nil {
+			return nil, fmt.Errorf("get star transactions: %w", apiErr)
+		}
+	}
+
+	if resp.JSON200 == nil || !bool(resp.JSON200.Ok) {
+		return nil, fmt.Errorf("get star transactions: unexpected response: %s", resp.Status())
+	}
+
+	return resp.JSON200.Result.Transactions, nil
+}
+
+// userPartner is the part of a TransactionPartnerUser the ledger reads.
+type userPartner struct {
+	Type            string `json:"type"`
+	TransactionType string `json:"transaction_type"`
+	User            struct {
+		ID int64 `json:"id"`
+	} `json:"user"`
+	InvoicePayload     string `json:"invoice_payload"`
+	SubscriptionPeriod int    `json:"subscription_period"`
+	PaidMediaPayload   string `json:"paid_media_payload"`
+}
+
+// fromStarTransaction returns the ledger transaction of a Star transaction
+// with a user: an incoming invoice or paid media payment, or an outgoing
+// refund of an invoice payment. Other transactions are skipped.
+func fromStarTransaction(st client.StarTransaction) (Transaction, bool) {
+	tx := Transaction{
+		ChargeID: st.Id,
+		Amount:   st.Amount,
+		Date:     time.Unix(int64(st.Date), 0),
+	}
+
+	if source, ok := decodePartner(st.Source); ok {
+		tx.UserID = source.User.ID
+
+		switch source.TransactionType {
+		case "invoice_payment":
+			tx.ID = paymentID(st.Id)
+			tx.Kind = KindPayment
+			tx.Payload = source.InvoicePayload
+
+			if source.SubscriptionPeriod > 0 {
+				tx.ExpiresAt = tx.Date.Add(time.Duration(source.SubscriptionPeriod) * time.Second)
+			}
+
+			return tx, true
+		case "paid_media_payment":
+			tx.ID = paidMediaID(source.User.ID, source.PaidMediaPayload)
+			tx.Kind = KindPaidMedia
+			tx.Payload = source.PaidMediaPayload
+
+			return tx, true
+		}
+	}
+
+	if receiver, ok := decodePartner(st.Receiver); ok && receiver.TransactionType == "invoice_payment" {
+		tx.ID = refundID(st.Id)
+		tx.Kind = KindRefund
+		tx.UserID = receiver.User.ID
+		tx.Payload = receiver.InvoicePayload
+
+		return tx, true
+	}
+
+	return Transaction{}, false
+}
+
+func decodePartner(partner *client.TransactionPartner) (userPartner, bool) {
+	if partner == nil {
+		return userPartner{}, false
+	}
+
+	data, err := json.Marshal(*partner)
+	if err != nil {
+		return userPartner{}, false
+	}
+
+	var user userPartner
+	if err := json.Unmarshal(data, &user); err != nil || user.Type != "user" {
+		return userPartner{}, false
+	}
+
+	return user, true
+}
@@ -0,0 +1,110 @@
+package ledger
+
+import (
+	"context"
+	"time"
+)
+
+// Kind is the kind of a Transaction.
+type Kind int
+
+// Kinds of transactions.
+const (
+	// KindPayment is a payment of an invoice.
+	KindPayment Kind = iota
+	// KindRefund is a refund of an invoice payment.
+	KindRefund
+	// KindPaidMedia is a purchase of paid media.
+	KindPaidMedia
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case KindPayment:
+		return "payment"
+	case KindRefund:
+		return "refund"
+	case KindPaidMedia:
+		return "paid media"
+	default:
+		return "unknown"
+	}
+}
+
+// Transaction is a movement of Telegram Stars between a user and the bot.
+type Transaction struct {
+	// ID identifies the transaction in the ledger.
+	ID   string
+	Kind Kind
+	// ChargeID is the Telegram payment charge ID. Refunds carry the ID of
+	// the payment they refund. It is empty for paid media purchases that
+	// were not reconciled yet.
+	ChargeID string
+	UserID   int64
+	// Payload is the invoice or paid media payload set by the bot.
+	Payload string
+	// Amount is the number of Stars, positive for refunds too. It is zero for
+	// paid media purchases that were not reconciled yet, since Telegram does
+	// not report their price in the update.
+	Amount int
+	Date   time.Time
+	// ExpiresAt is when the subscription paid by the transaction ends, or
+	// zero for a one-time payment.
+	ExpiresAt time.Time
+}
+
+// SubscriptionState is the state of a Subscription.
+type SubscriptionState string
+
+// States of a subscription. The first three are reported by Telegram.
+const (
+	// SubscriptionActive means the subscription renews at the end of its period.
+	SubscriptionActive SubscriptionState = "active"
+	// SubscriptionCanceled means the user canceled the subscription; it ends
+	// at the end of its period.
+	SubscriptionCanceled SubscriptionState = "canceled"
+	// SubscriptionFailed means the renewal payment failed.
+	SubscriptionFailed SubscriptionState = "failed"
+	// SubscriptionExpired means the subscription ended or was refunded.
+	SubscriptionExpired SubscriptionState = "expired"
+)
+
+// Subscription is a user's subscription to the product of an invoice payload.
+type Subscription struct {
+	UserID  int64
+	Payload string
+	State   SubscriptionState
+	// ExpiresAt is when the last paid period ends.
+	ExpiresAt time.Time
+	// ChargeID is the charge ID of the last payment.
+	ChargeID string
+}
+
+// Store persists the transactions and subscriptions of a Ledger. The Ledger
+// serializes its writes.
+type Store interface {
+	// Transaction returns the transaction with id and whether it exists.
+	Transaction(ctx context.Context, id string) (Transaction, bool, error)
+	// SaveTransaction records tx, replacing a transaction with the same ID.
+	SaveTransaction(ctx context.Context, tx Transaction) error
+	// Transactions returns the transactions of userID in the order they were
+	// first saved.
+	Transactions(ctx context.Context, userID int64) ([]Transaction, error)
+	// Subscription returns the subscription of userID to payload and whether
+	// it exists.
+	Subscription(ctx context.Context, userID int64, payload string) (Subscription, bool, error)
+	// SaveSubscription records sub, replacing the one of the same user and
+	// payload.
+	SaveSubscription(ctx context.Context, sub Subscription) error
+	// ExpiredSubscriptions returns the subscriptions that ended before now
+	// and are not in the SubscriptionExpired state. Subscriptions with a zero
+	// ExpiresAt, whose end is not known, are not returned.
+	ExpiredSubscriptions(ctx context.Context, now time.Time) ([]Subscription, error)
+	// ReconcileOffset returns how many Star transactions Reconcile went
+	// through, zero before the first call.
+	ReconcileOffset(ctx context.Context) (int, error)
+	// SaveReconcileOffset records how many Star transactions Reconcile went
+	// through.
+	SaveReconcileOffset(ctx context.Context, offset int) error
+}
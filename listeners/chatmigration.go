@@ -0,0 +1,141 @@
+package listeners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+)
+
+// ChatMigrationHook is implemented by components that key data by chat ID, such as
+// sessions, FSM state, or stores, so they can move that data to the new supergroup ID.
+type ChatMigrationHook interface {
+	// MigrateChat moves data stored for fromChatID to toChatID.
+	MigrateChat(ctx context.Context, fromChatID, toChatID int64) error
+}
+
+// ChatMigrationHookFunc is an adapter to allow the use of ordinary functions as ChatMigrationHook.
+type ChatMigrationHookFunc func(ctx context.Context, fromChatID, toChatID int64) error
+
+var _ ChatMigrationHook = ChatMigrationHookFunc(nil)
+
+// MigrateChat calls f(ctx, fromChatID, toChatID).
+func (f ChatMigrationHookFunc) MigrateChat(ctx context.Context, fromChatID, toChatID int64) error {
+	return f(ctx, fromChatID, toChatID)
+}
+
+// chatMigrations is how many recent migrations ChatMigration remembers, so
+// that the second service message of each does not run the hooks again.
+const chatMigrations = 1024
+
+// ChatMigration returns a listener that detects group-to-supergroup migration service
+// messages, runs the hooks, and emits OnChatMigrated.
+//
+// Telegram sends both a migrate_to_chat_id message in the old group and a
+// migrate_from_chat_id message in the new supergroup. The event is emitted once per
+// migration, for whichever message arrives first. The hooks run for that message too,
+// and run again for the second one if any of them failed, so they should tolerate
+// being called twice.
+func ChatMigration(emitter eventemitter.EventEmitter, hooks ...ChatMigrationHook) eventemitter.Listener {
+	migrated := &chatMigrationLog{done: make(map[int64]bool)}
+
+	return eventemitter.ListenerFunc(func(ctx context.Context, payload any) error {
+		event, ok := payload.(*events.MessageEvent)
+		if !ok || event == nil || event.Message == nil {
+			return nil
+		}
+
+		fromChatID, toChatID, ok := chatMigration(event.Message)
+		if !ok {
+			return nil
+		}
+
+		seen, err := migrated.migrate(fromChatID, func() error {
+			return runChatMigrationHooks(ctx, hooks, fromChatID, toChatID)
+		})
+		if seen {
+			return err
+		}
+
+		emitter.Emit(ctx, events.OnChatMigrated, &events.ChatMigratedEvent{
+			Message:    event.Message,
+			FromChatID: fromChatID,
+			ToChatID:   toChatID,
+		})
+
+		return err
+	})
+}
+
+// chatMigrationLog records the recent migrations by the ID of the old group,
+// and whether their hooks succeeded.
+type chatMigrationLog struct {
+	// mu guards done and order, and serializes the hooks so that the two
+	// messages of a migration do not run them at the same time.
+	mu    sync.Mutex
+	done  map[int64]bool
+	order []int64
+}
+
+// migrate runs hooks for the migration of fromChatID unless they already
+// succeeded, and reports whether the migration was seen before.
+func (l *chatMigrationLog) migrate(fromChatID int64, hooks func() error) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	done, seen := l.done[fromChatID]
+	if done {
+		return true, nil
+	}
+
+	err := hooks()
+
+	if !seen {
+		if len(l.order) == chatMigrations {
+			delete(l.done, l.order[0])
+			l.order = l.order[1:]
+		}
+
+		l.order = append(l.order, fromChatID)
+	}
+
+	l.done[fromChatID] = err == nil
+
+	return seen, err
+}
+
+func chatMigration(message *client.Message) (int64, int64, bool) {
+	if message.MigrateToChatId != nil {
+		return message.Chat.Id, *message.MigrateToChatId, true
+	}
+
+	if message.MigrateFromChatId != nil {
+		return *message.MigrateFromChatId, message.Chat.Id, true
+	}
+
+	return 0, 0, false
+}
+
+func runChatMigrationHooks(ctx context.Context, hooks []ChatMigrationHook, fromChatID, toChatID int64) error {
+	var errs []error
+
+	for _, hook := range hooks {
+		if hook == nil {
+			continue
+		}
+
+		if err := hook.MigrateChat(ctx, fromChatID, toChatID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("migrate chat %d to %d: %w", fromChatID, toChatID, err)
+	}
+
+	return nil
+}
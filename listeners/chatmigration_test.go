@@ -0,0 +1,188 @@
+package listeners_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/listeners"
+)
+
+func TestChatMigration(t *testing.T) {
+	t.Run("runs hooks and emits event once per migration", func(t *testing.T) {
+		ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+		if err != nil {
+			t.Fatalf("NewSync() unexpected error: %v", err)
+		}
+
+		var received []*events.ChatMigratedEvent
+		eventemitter.On(ee, events.OnChatMigrated, func(_ context.Context, event *events.ChatMigratedEvent) error {
+			received = append(received, event)
+			return nil
+		})
+
+		var hookCalls [][2]int64
+		listener := listeners.ChatMigration(ee, listeners.ChatMigrationHookFunc(
+			func(_ context.Context, fromChatID, toChatID int64) error {
+				hookCalls = append(hookCalls, [2]int64{fromChatID, toChatID})
+				return nil
+			},
+		))
+
+		toChatID := int64(-1001234)
+		fromChatID := int64(-42)
+		oldGroupMessage := &client.Message{Chat: client.Chat{Id: fromChatID}, MigrateToChatId: &toChatID}
+		newGroupMessage := &client.Message{Chat: client.Chat{Id: toChatID}, MigrateFromChatId: &fromChatID}
+
+		for _, msg := range []*client.Message{oldGroupMessage, newGroupMessage} {
+			if err := listener.Handle(context.Background(), &events.MessageEvent{Message: msg}); err != nil {
+				t.Fatalf("Handle() unexpected error: %v", err)
+			}
+		}
+
+		if len(received) != 1 {
+			t.Fatalf("events=%d, want 1", len(received))
+		}
+		if received[0].FromChatID != fromChatID || received[0].ToChatID != toChatID {
+			t.Fatalf("event=%+v, want %d -> %d", received[0], fromChatID, toChatID)
+		}
+		if received[0].Message != oldGroupMessage {
+			t.Fatalf("event message=%p, want %p", received[0].Message, oldGroupMessage)
+		}
+		if len(hookCalls) != 1 || hookCalls[0] != [2]int64{fromChatID, toChatID} {
+			t.Fatalf("hook calls=%v, want [[%d %d]]", hookCalls, fromChatID, toChatID)
+		}
+	})
+
+	t.Run("detects migration from new supergroup message", func(t *testing.T) {
+		ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+		if err != nil {
+			t.Fatalf("NewSync() unexpected error: %v", err)
+		}
+
+		var received *events.ChatMigratedEvent
+		eventemitter.On(ee, events.OnChatMigrated, func(_ context.Context, event *events.ChatMigratedEvent) error {
+			received = event
+			return nil
+		})
+
+		fromChatID := int64(-7)
+		msg := &client.Message{Chat: client.Chat{Id: -1007}, MigrateFromChatId: &fromChatID}
+		if err := listeners.ChatMigration(ee).Handle(context.Background(), &events.MessageEvent{Message: msg}); err != nil {
+			t.Fatalf("Handle() unexpected error: %v", err)
+		}
+
+		if received == nil || received.FromChatID != -7 || received.ToChatID != -1007 {
+			t.Fatalf("event=%+v, want -7 -> -1007", received)
+		}
+	})
+
+	t.Run("returns hook errors after emitting", func(t *testing.T) {
+		ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+		if err != nil {
+			t.Fatalf("NewSync() unexpected error: %v", err)
+		}
+
+		var emitted bool
+		eventemitter.On(ee, events.OnChatMigrated, func(_ context.Context, _ *events.ChatMigratedEvent) error {
+			emitted = true
+			return nil
+		})
+
+		wantErr := errors.New("rekey failed")
+		listener := listeners.ChatMigration(ee, listeners.ChatMigrationHookFunc(
+			func(_ context.Context, _, _ int64) error {
+				return wantErr
+			},
+		))
+
+		toChatID := int64(-1009)
+		msg := &client.Message{Chat: client.Chat{Id: -9}, MigrateToChatId: &toChatID}
+
+		err = listener.Handle(context.Background(), &events.MessageEvent{Message: msg})
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("Handle() error=%v, want %v", err, wantErr)
+		}
+		if !emitted {
+			t.Fatal("OnChatMigrated was not emitted")
+		}
+	})
+
+	t.Run("ignores regular messages", func(t *testing.T) {
+		ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+		if err != nil {
+			t.Fatalf("NewSync() unexpected error: %v", err)
+		}
+
+		var emitted bool
+		eventemitter.On(ee, events.OnChatMigrated, func(_ context.Context, _ *events.ChatMigratedEvent) error {
+			emitted = true
+			return nil
+		})
+
+		text := "hello"
+		listener := listeners.ChatMigration(ee)
+		if err := listener.Handle(context.Background(), &events.MessageEvent{Message: &client.Message{Text: &text}}); err != nil {
+			t.Fatalf("Handle() unexpected error: %v", err)
+		}
+		if err := listener.Handle(context.Background(), "invalid-payload"); err != nil {
+			t.Fatalf("Handle() unexpected error: %v", err)
+		}
+		if emitted {
+			t.Fatal("OnChatMigrated emitted for regular message")
+		}
+	})
+}
+
+func TestChatMigrationRetriesFailedHooks(t *testing.T) {
+	ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+	if err != nil {
+		t.Fatalf("NewSync() unexpected error: %v", err)
+	}
+
+	var emitted int
+	eventemitter.On(ee, events.OnChatMigrated, func(_ context.Context, _ *events.ChatMigratedEvent) error {
+		emitted++
+		return nil
+	})
+
+	var calls int
+	listener := listeners.ChatMigration(ee, listeners.ChatMigrationHookFunc(
+		func(_ context.Context, _, _ int64) error {
+			calls++
+			if calls == 1 {
+				return errors.New("store unavailable")
+			}
+
+			return nil
+		},
+	))
+
+	toChatID := int64(-1005)
+	fromChatID := int64(-5)
+	messages := []*client.Message{
+		{Chat: client.Chat{Id: fromChatID}, MigrateToChatId: &toChatID},
+		{Chat: client.Chat{Id: toChatID}, MigrateFromChatId: &fromChatID},
+		{Chat: client.Chat{Id: toChatID}, MigrateFromChatId: &fromChatID},
+	}
+
+	if err := listener.Handle(context.Background(), &events.MessageEvent{Message: messages[0]}); err == nil {
+		t.Fatal("Handle() expected the hook error")
+	}
+
+	for _, msg := range messages[1:] {
+		if err := listener.Handle(context.Background(), &events.MessageEvent{Message: msg}); err != nil {
+			t.Fatalf("Handle() unexpected error: %v", err)
+		}
+	}
+
+	if calls != 2 {
+		t.Fatalf("hook calls=%d, want 2", calls)
+	}
+	if emitted != 1 {
+		t.Fatalf("events=%d, want 1", emitted)
+	}
+}
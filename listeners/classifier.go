@@ -13,10 +13,22 @@ import (
 // Classifier returns a listener that analyzes incoming updates and emits more specific events
 // based on the update content.
 func Classifier(emitter eventemitter.EventEmitter) eventemitter.Listener {
+	return ClassifierWithTypes(emitter, nil)
+}
+
+// ClassifierWithTypes returns a Classifier that resolves MessageEvent.Type through the
+// given registry, so custom detectors take part in message classification.
+// A nil registry uses the built-in messagetype.Detect.
+func ClassifierWithTypes(emitter eventemitter.EventEmitter, types *messagetype.Registry) eventemitter.Listener {
+	detect := messagetype.Detect
+	if types != nil {
+		detect = types.Detect
+	}
+
 	return eventemitter.ListenerFunc(func(ctx context.Context, payload any) error {
 		if event, ok := payload.(*events.UpdateEvent); ok {
 			if event != nil && event.Update != nil {
-				classifyUpdate(ctx, emitter, event)
+				classifyUpdate(ctx, emitter, detect, event)
 			}
 		}
 
@@ -24,43 +36,73 @@ func Classifier(emitter eventemitter.EventEmitter) eventemitter.Listener {
 	})
 }
 
+// messageDetector resolves the type reported in MessageEvent.Type.
+type messageDetector func(message *client.Message) messagetype.MessageType
+
 // classifyUpdate inspects the update and emits corresponding events.
-func classifyUpdate(ctx context.Context, emitter eventemitter.EventEmitter, event *events.UpdateEvent) {
+func classifyUpdate(
+	ctx context.Context,
+	emitter eventemitter.EventEmitter,
+	detect messageDetector,
+	event *events.UpdateEvent,
+) {
 	update := event.Update
+	counter := &emitCounter{EventEmitter: emitter}
+
+	classifyMessages(ctx, counter, detect, update)
+	classifyQueries(ctx, counter, update)
+	classifyChatUpdates(ctx, counter, update)
+	classifyBusinessUpdates(ctx, counter, update)
+
+	if counter.emitted == 0 {
+		emitter.Emit(ctx, events.OnUnhandledUpdate, event)
+	}
+}
+
+// emitCounter counts the events emitted while classifying one update.
+type emitCounter struct {
+	eventemitter.EventEmitter
+
+	emitted int
+}
 
-	classifyMessages(ctx, emitter, update)
-	classifyQueries(ctx, emitter, update)
-	classifyChatUpdates(ctx, emitter, update)
-	classifyBusinessUpdates(ctx, emitter, update)
+func (c *emitCounter) Emit(ctx context.Context, event string, payload any) {
+	c.emitted++
+	c.EventEmitter.Emit(ctx, event, payload)
 }
 
-func classifyMessages(ctx context.Context, emitter eventemitter.EventEmitter, update *client.Update) {
+func classifyMessages(
+	ctx context.Context,
+	emitter eventemitter.EventEmitter,
+	detect messageDetector,
+	update *client.Update,
+) {
 	if update.Message != nil {
-		emitMessage(ctx, emitter, events.OnMessage, update.Message)
+		emitMessage(ctx, emitter, detect, events.OnMessage, update.Message)
 	}
 
 	if update.EditedMessage != nil {
-		emitMessage(ctx, emitter, events.OnEditedMessage, update.EditedMessage)
+		emitMessage(ctx, emitter, detect, events.OnEditedMessage, update.EditedMessage)
 	}
 
 	if update.ChannelPost != nil {
-		emitMessage(ctx, emitter, events.OnChannelPost, update.ChannelPost)
+		emitMessage(ctx, emitter, detect, events.OnChannelPost, update.ChannelPost)
 	}
 
 	if update.EditedChannelPost != nil {
-		emitMessage(ctx, emitter, events.OnEditedChannelPost, update.EditedChannelPost)
+		emitMessage(ctx, emitter, detect, events.OnEditedChannelPost, update.EditedChannelPost)
 	}
 
 	if update.BusinessMessage != nil {
-		emitMessage(ctx, emitter, events.OnBusinessMessage, update.BusinessMessage)
+		emitMessage(ctx, emitter, detect, events.OnBusinessMessage, update.BusinessMessage)
 	}
 
 	if update.EditedBusinessMessage != nil {
-		emitMessage(ctx, emitter, events.OnEditedBusinessMessage, update.EditedBusinessMessage)
+		emitMessage(ctx, emitter, detect, events.OnEditedBusinessMessage, update.EditedBusinessMessage)
 	}
 
 	if update.GuestMessage != nil {
-		emitMessage(ctx, emitter, events.OnGuestMessage, update.GuestMessage)
+		emitMessage(ctx, emitter, detect, events.OnGuestMessage, update.GuestMessage)
 	}
 }
 
@@ -188,10 +230,16 @@ func classifyBusinessUpdates(ctx context.Context, emitter eventemitter.EventEmit
 	}
 }
 
-func emitMessage(ctx context.Context, emitter eventemitter.EventEmitter, event string, message *client.Message) {
+func emitMessage(
+	ctx context.Context,
+	emitter eventemitter.EventEmitter,
+	detect messageDetector,
+	event string,
+	message *client.Message,
+) {
 	emitter.Emit(ctx, event, &events.MessageEvent{
 		Message: message,
-		Type:    messagetype.Detect(message),
+		Type:    detect(message),
 	})
 }
 
@@ -86,6 +86,42 @@ func TestClassifier(t *testing.T) {
 		}
 	})
 
+	t.Run("classifies with custom detectors", func(t *testing.T) {
+		ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+		if err != nil {
+			t.Fatalf("NewSync() unexpected error: %v", err)
+		}
+
+		const invoiceLink messagetype.MessageType = "invoice_link"
+
+		types := messagetype.NewRegistry()
+		types.Register(1, messagetype.DetectorFunc(func(m *client.Message) (messagetype.MessageType, bool) {
+			return invoiceLink, m.Text != nil && *m.Text == "https://t.me/$invoice"
+		}))
+		classifier := listeners.ClassifierWithTypes(ee, types)
+
+		var receivedEvent *events.MessageEvent
+		ee.AddListener(events.OnMessage, eventemitter.ListenerFunc(func(_ context.Context, payload any) error {
+			if e, ok := payload.(*events.MessageEvent); ok {
+				receivedEvent = e
+			}
+			return nil
+		}))
+
+		text := "https://t.me/$invoice"
+		update := &client.Update{UpdateId: 4, Message: &client.Message{Text: &text}}
+
+		if err := classifier.Handle(context.Background(), &events.UpdateEvent{Update: update}); err != nil {
+			t.Fatalf("Handle() unexpected error: %v", err)
+		}
+		if receivedEvent == nil {
+			t.Fatal("receivedEvent is nil")
+		}
+		if receivedEvent.Type != invoiceLink {
+			t.Fatalf("received type=%q, want %q", receivedEvent.Type, invoiceLink)
+		}
+	})
+
 	t.Run("ignores updates without message", func(t *testing.T) {
 		ee, err := eventemitter.NewSync(eventemitter.NewOptions())
 		if err != nil {
@@ -109,6 +145,44 @@ func TestClassifier(t *testing.T) {
 		}
 	})
 
+	t.Run("emits unhandled update when nothing matched", func(t *testing.T) {
+		ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+		if err != nil {
+			t.Fatalf("NewSync() unexpected error: %v", err)
+		}
+		classifier := listeners.Classifier(ee)
+
+		var received []*events.UpdateEvent
+		ee.AddListener(events.OnUnhandledUpdate, eventemitter.ListenerFunc(func(_ context.Context, payload any) error {
+			if e, ok := payload.(*events.UpdateEvent); ok {
+				received = append(received, e)
+			}
+			return nil
+		}))
+
+		raw := []byte(`{"update_id":4,"future_field":{}}`)
+		unhandled := &events.UpdateEvent{Update: &client.Update{UpdateId: 4}, Raw: raw}
+		if err := classifier.Handle(context.Background(), unhandled); err != nil {
+			t.Fatalf("Handle() unexpected error: %v", err)
+		}
+
+		text := "hello"
+		handled := &events.UpdateEvent{Update: &client.Update{UpdateId: 5, Message: &client.Message{Text: &text}}}
+		if err := classifier.Handle(context.Background(), handled); err != nil {
+			t.Fatalf("Handle() unexpected error: %v", err)
+		}
+
+		if len(received) != 1 {
+			t.Fatalf("unhandled events=%d, want 1", len(received))
+		}
+		if received[0] != unhandled {
+			t.Fatalf("unhandled event=%p, want %p", received[0], unhandled)
+		}
+		if string(received[0].Raw) != string(raw) {
+			t.Fatalf("raw=%s, want %s", received[0].Raw, raw)
+		}
+	})
+
 	t.Run("ignores invalid payload", func(t *testing.T) {
 		err := classifier.Handle(context.Background(), "invalid-payload")
 		if err != nil {
@@ -3,11 +3,11 @@ package listeners
 import (
 	"context"
 	"strings"
-	"unicode/utf16"
 
 	"github.com/tgbotkit/client"
 	"github.com/tgbotkit/runtime/eventemitter"
 	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/internal/utf16text"
 )
 
 // CommandParser returns a listener that detects bot commands in messages and emits OnCommand events.
@@ -90,9 +90,9 @@ func commandSource(message *client.Message) (string, []client.MessageEntity, boo
 func sliceText(text string, offset int, length int) string {
 	runes := []rune(text)
 
-	start := runeIndexFromUTF16Offset(runes, offset)
+	start := utf16text.RuneIndex(runes, offset)
 
-	end := runeIndexFromUTF16Offset(runes, offset+length)
+	end := utf16text.RuneIndex(runes, offset+length)
 	if end < start {
 		end = start
 	}
@@ -103,24 +103,7 @@ func sliceText(text string, offset int, length int) string {
 func sliceTextFrom(text string, offset int) string {
 	runes := []rune(text)
 
-	start := runeIndexFromUTF16Offset(runes, offset)
+	start := utf16text.RuneIndex(runes, offset)
 
 	return string(runes[start:])
 }
-
-func runeIndexFromUTF16Offset(runes []rune, offset int) int {
-	if offset <= 0 {
-		return 0
-	}
-
-	units := 0
-	for i, r := range runes {
-		if units >= offset {
-			return i
-		}
-
-		units += utf16.RuneLen(r)
-	}
-
-	return len(runes)
-}
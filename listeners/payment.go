@@ -0,0 +1,35 @@
+package listeners
+
+import (
+	"context"
+
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+)
+
+// Payments returns a listener that emits OnSuccessfulPayment and OnRefundedPayment
+// for the service messages reporting a payment or a refund.
+func Payments(emitter eventemitter.EventEmitter) eventemitter.Listener {
+	return eventemitter.ListenerFunc(func(ctx context.Context, payload any) error {
+		event, ok := payload.(*events.MessageEvent)
+		if !ok || event == nil || event.Message == nil {
+			return nil
+		}
+
+		if payment := event.Message.SuccessfulPayment; payment != nil {
+			emitter.Emit(ctx, events.OnSuccessfulPayment, &events.SuccessfulPaymentEvent{
+				Message: event.Message,
+				Payment: payment,
+			})
+		}
+
+		if payment := event.Message.RefundedPayment; payment != nil {
+			emitter.Emit(ctx, events.OnRefundedPayment, &events.RefundedPaymentEvent{
+				Message: event.Message,
+				Payment: payment,
+			})
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,54 @@
+package listeners_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/listeners"
+)
+
+func TestPayments(t *testing.T) {
+	ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+	if err != nil {
+		t.Fatalf("NewSync() unexpected error: %v", err)
+	}
+
+	var (
+		paid     []*events.SuccessfulPaymentEvent
+		refunded []*events.RefundedPaymentEvent
+	)
+
+	eventemitter.On(ee, events.OnSuccessfulPayment, func(_ context.Context, event *events.SuccessfulPaymentEvent) error {
+		paid = append(paid, event)
+		return nil
+	})
+	eventemitter.On(ee, events.OnRefundedPayment, func(_ context.Context, event *events.RefundedPaymentEvent) error {
+		refunded = append(refunded, event)
+		return nil
+	})
+
+	listener := listeners.Payments(ee)
+	payment := &client.SuccessfulPayment{Currency: "XTR", InvoicePayload: "pro", TotalAmount: 50}
+	refund := &client.RefundedPayment{Currency: "XTR", InvoicePayload: "pro", TotalAmount: 50}
+	text := "hello"
+
+	for _, msg := range []*client.Message{
+		{Text: &text},
+		{SuccessfulPayment: payment},
+		{RefundedPayment: refund},
+	} {
+		if err := listener.Handle(context.Background(), &events.MessageEvent{Message: msg}); err != nil {
+			t.Fatalf("Handle() unexpected error: %v", err)
+		}
+	}
+
+	if len(paid) != 1 || paid[0].Payment != payment {
+		t.Fatalf("successful payment events=%v, want the one payment", paid)
+	}
+	if len(refunded) != 1 || refunded[0].Payment != refund {
+		t.Fatalf("refunded payment events=%v, want the one refund", refunded)
+	}
+}
@@ -0,0 +1,386 @@
+package messagetype
+
+import (
+	"encoding/json"
+
+	"github.com/tgbotkit/client"
+)
+
+// FileRef references a file stored on Telegram servers.
+type FileRef struct {
+	// FileID is used to download or resend the file.
+	FileID string
+	// FileUniqueID is stable across bots and time but cannot be used to download the file.
+	FileUniqueID string
+	// FileSize is the file size in bytes, or zero when Telegram did not report it.
+	FileSize int64
+	// MimeType is the MIME type reported by the sender, if any.
+	MimeType string
+	// FileName is the original file name, if any.
+	FileName string
+	// Width is the width in pixels for visual media.
+	Width int
+	// Height is the height in pixels for visual media.
+	Height int
+	// Duration is the duration in seconds for audio and video media.
+	Duration int
+}
+
+// Media describes the downloadable attachment of a message.
+type Media struct {
+	// Type is the media kind the file was taken from.
+	Type MessageType
+	// File is the primary file. For photos it is the largest available size.
+	File FileRef
+	// Thumbnail is the media thumbnail, if any.
+	Thumbnail *FileRef
+	// Sizes lists every available size for photo-like media.
+	Sizes []FileRef
+	// Items lists the media of a paid media message that the bot can
+	// download, one per item. File, Thumbnail and Sizes are those of the
+	// first one.
+	Items []Media
+}
+
+// Content is a normalized description of a message's content.
+type Content struct {
+	// Types lists every type present on the message, as returned by DetectAll.
+	Types []MessageType
+	// Text is the message text or media caption.
+	Text string
+	// Entities are the entities of Text.
+	Entities []client.MessageEntity
+	// Media is the message attachment, or nil when the message has no file.
+	Media *Media
+	// ReplyToStory is the story the message replies to, if any.
+	ReplyToStory *client.Story
+	// ExternalReply describes the message replied to when it comes from
+	// another chat or forum topic, if any.
+	ExternalReply *client.ExternalReplyInfo
+	// Quote is the quoted part of the message replied to, if any.
+	Quote *client.TextQuote
+}
+
+// Has reports whether t is one of the content types.
+func (c Content) Has(t MessageType) bool {
+	for _, candidate := range c.Types {
+		if candidate == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Describe returns a normalized content descriptor for a Telegram message, so handlers
+// can treat text and captions, and every media kind, uniformly.
+func Describe(message *client.Message) Content {
+	if message == nil {
+		return Content{}
+	}
+
+	content := Content{
+		Types:         DetectAll(message),
+		Media:         DescribeMedia(message),
+		ReplyToStory:  message.ReplyToStory,
+		ExternalReply: message.ExternalReply,
+		Quote:         message.Quote,
+	}
+
+	switch {
+	case message.Text != nil:
+		content.Text = *message.Text
+		content.Entities = derefSlice(message.Entities)
+	case message.Caption != nil:
+		content.Text = *message.Caption
+		content.Entities = derefSlice(message.CaptionEntities)
+	}
+
+	return content
+}
+
+// DescribeMedia returns the downloadable attachment of a message, or nil when the
+// message carries no file. Animations take precedence over the document Telegram
+// also attaches to them for backward compatibility. Paid media is described by
+// its items, skipping the previews of media the bot cannot download.
+//
+//nolint:cyclop
+func DescribeMedia(message *client.Message) *Media {
+	if message == nil {
+		return nil
+	}
+
+	switch {
+	case message.Animation != nil:
+		return animationMedia(message.Animation)
+	case message.Audio != nil:
+		return audioMedia(message.Audio)
+	case message.Document != nil:
+		return documentMedia(message.Document)
+	case message.Photo != nil && len(*message.Photo) > 0:
+		return photoMedia(Photo, *message.Photo)
+	case message.Sticker != nil:
+		return stickerMedia(message.Sticker)
+	case message.LivePhoto != nil:
+		return livePhotoMedia(message.LivePhoto)
+	case message.Video != nil:
+		return videoMedia(message.Video)
+	case message.VideoNote != nil:
+		return videoNoteMedia(message.VideoNote)
+	case message.Voice != nil:
+		return voiceMedia(message.Voice)
+	case message.PaidMedia != nil:
+		return paidMedia(message.PaidMedia)
+	default:
+		return nil
+	}
+}
+
+func animationMedia(a *client.Animation) *Media {
+	return &Media{
+		Type: Animation,
+		File: FileRef{
+			FileID:       a.FileId,
+			FileUniqueID: a.FileUniqueId,
+			FileSize:     derefInt64(a.FileSize),
+			MimeType:     deref(a.MimeType),
+			FileName:     deref(a.FileName),
+			Width:        a.Width,
+			Height:       a.Height,
+			Duration:     a.Duration,
+		},
+		Thumbnail: thumbnailRef(a.Thumbnail),
+	}
+}
+
+func audioMedia(a *client.Audio) *Media {
+	return &Media{
+		Type: Audio,
+		File: FileRef{
+			FileID:       a.FileId,
+			FileUniqueID: a.FileUniqueId,
+			FileSize:     derefInt64(a.FileSize),
+			MimeType:     deref(a.MimeType),
+			FileName:     deref(a.FileName),
+			Duration:     a.Duration,
+		},
+		Thumbnail: thumbnailRef(a.Thumbnail),
+	}
+}
+
+func documentMedia(d *client.Document) *Media {
+	return &Media{
+		Type: Document,
+		File: FileRef{
+			FileID:       d.FileId,
+			FileUniqueID: d.FileUniqueId,
+			FileSize:     derefInt64(d.FileSize),
+			MimeType:     deref(d.MimeType),
+			FileName:     deref(d.FileName),
+		},
+		Thumbnail: thumbnailRef(d.Thumbnail),
+	}
+}
+
+func photoMedia(t MessageType, sizes []client.PhotoSize) *Media {
+	refs := make([]FileRef, 0, len(sizes))
+	largest := 0
+
+	for i, size := range sizes {
+		refs = append(refs, photoSizeRef(size))
+
+		if size.Width*size.Height > sizes[largest].Width*sizes[largest].Height {
+			largest = i
+		}
+	}
+
+	return &Media{
+		Type:  t,
+		File:  refs[largest],
+		Sizes: refs,
+	}
+}
+
+func stickerMedia(s *client.Sticker) *Media {
+	return &Media{
+		Type: Sticker,
+		File: FileRef{
+			FileID:       s.FileId,
+			FileUniqueID: s.FileUniqueId,
+			FileSize:     derefInt(s.FileSize),
+			Width:        s.Width,
+			Height:       s.Height,
+		},
+		Thumbnail: thumbnailRef(s.Thumbnail),
+	}
+}
+
+func livePhotoMedia(p *client.LivePhoto) *Media {
+	media := &Media{
+		Type: LivePhoto,
+		File: FileRef{
+			FileID:       p.FileId,
+			FileUniqueID: p.FileUniqueId,
+			FileSize:     derefInt64(p.FileSize),
+			MimeType:     deref(p.MimeType),
+			Width:        p.Width,
+			Height:       p.Height,
+			Duration:     p.Duration,
+		},
+	}
+
+	if p.Photo != nil {
+		for _, size := range *p.Photo {
+			media.Sizes = append(media.Sizes, photoSizeRef(size))
+		}
+	}
+
+	return media
+}
+
+func videoMedia(v *client.Video) *Media {
+	return &Media{
+		Type: Video,
+		File: FileRef{
+			FileID:       v.FileId,
+			FileUniqueID: v.FileUniqueId,
+			FileSize:     derefInt64(v.FileSize),
+			MimeType:     deref(v.MimeType),
+			FileName:     deref(v.FileName),
+			Width:        v.Width,
+			Height:       v.Height,
+			Duration:     v.Duration,
+		},
+		Thumbnail: thumbnailRef(v.Thumbnail),
+	}
+}
+
+func videoNoteMedia(v *client.VideoNote) *Media {
+	return &Media{
+		Type: VideoNote,
+		File: FileRef{
+			FileID:       v.FileId,
+			FileUniqueID: v.FileUniqueId,
+			FileSize:     derefInt(v.FileSize),
+			Width:        v.Length,
+			Height:       v.Length,
+			Duration:     v.Duration,
+		},
+		Thumbnail: thumbnailRef(v.Thumbnail),
+	}
+}
+
+func voiceMedia(v *client.Voice) *Media {
+	return &Media{
+		Type: Voice,
+		File: FileRef{
+			FileID:       v.FileId,
+			FileUniqueID: v.FileUniqueId,
+			FileSize:     derefInt64(v.FileSize),
+			MimeType:     deref(v.MimeType),
+			Duration:     v.Duration,
+		},
+	}
+}
+
+// paidMediaItem is a PaidMedia, which the client leaves undecoded.
+type paidMediaItem struct {
+	Photo     []client.PhotoSize `json:"photo"`
+	Video     *client.Video      `json:"video"`
+	LivePhoto *client.LivePhoto  `json:"live_photo"`
+}
+
+func paidMedia(info *client.PaidMediaInfo) *Media {
+	var items []Media
+
+	for _, raw := range info.PaidMedia {
+		if item := paidMediaItemMedia(raw); item != nil {
+			items = append(items, *item)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	media := items[0]
+	media.Type = PaidMedia
+	media.Items = items
+
+	return &media
+}
+
+func paidMediaItemMedia(raw client.PaidMedia) *Media {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+
+	var item paidMediaItem
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil
+	}
+
+	switch {
+	case len(item.Photo) > 0:
+		return photoMedia(Photo, item.Photo)
+	case item.Video != nil:
+		return videoMedia(item.Video)
+	case item.LivePhoto != nil:
+		return livePhotoMedia(item.LivePhoto)
+	default:
+		return nil
+	}
+}
+
+func photoSizeRef(size client.PhotoSize) FileRef {
+	return FileRef{
+		FileID:       size.FileId,
+		FileUniqueID: size.FileUniqueId,
+		FileSize:     derefInt(size.FileSize),
+		Width:        size.Width,
+		Height:       size.Height,
+	}
+}
+
+func thumbnailRef(size *client.PhotoSize) *FileRef {
+	if size == nil {
+		return nil
+	}
+
+	ref := photoSizeRef(*size)
+
+	return &ref
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func derefInt(value *int) int64 {
+	if value == nil {
+		return 0
+	}
+
+	return int64(*value)
+}
+
+func derefInt64(value *int64) int64 {
+	if value == nil {
+		return 0
+	}
+
+	return *value
+}
+
+func derefSlice[T any](value *[]T) []T {
+	if value == nil {
+		return nil
+	}
+
+	return *value
+}
@@ -0,0 +1,181 @@
+package messagetype_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/messagetype"
+)
+
+func TestDetectAll(t *testing.T) {
+	caption := "look"
+	msg := &client.Message{
+		Caption:       &caption,
+		Animation:     &client.Animation{FileId: "anim"},
+		Document:      &client.Document{FileId: "doc"},
+		PinnedMessage: &client.MaybeInaccessibleMessage{},
+	}
+
+	got := messagetype.DetectAll(msg)
+	want := []messagetype.MessageType{messagetype.PinnedMessage, messagetype.Animation, messagetype.Document}
+	if !slices.Equal(got, want) {
+		t.Fatalf("DetectAll()=%v, want %v", got, want)
+	}
+	if messagetype.Detect(msg) != got[0] {
+		t.Fatalf("Detect()=%q, want first DetectAll type %q", messagetype.Detect(msg), got[0])
+	}
+	if messagetype.DetectAll(nil) != nil {
+		t.Fatal("DetectAll(nil) is not nil")
+	}
+}
+
+func TestDetectAllReplyToStory(t *testing.T) {
+	caption := "nice story"
+	msg := &client.Message{
+		Caption:      &caption,
+		Photo:        &[]client.PhotoSize{{FileId: "photo", Width: 90, Height: 90}},
+		ReplyToStory: &client.Story{Chat: client.Chat{Id: 5}, Id: 7},
+		Quote:        &client.TextQuote{Text: "story"},
+	}
+
+	got := messagetype.DetectAll(msg)
+	want := []messagetype.MessageType{messagetype.Photo, messagetype.ReplyToStory, messagetype.Quote}
+	if !slices.Equal(got, want) {
+		t.Fatalf("DetectAll()=%v, want %v", got, want)
+	}
+	if messagetype.Detect(msg) != messagetype.Photo {
+		t.Fatalf("Detect()=%q, want %q", messagetype.Detect(msg), messagetype.Photo)
+	}
+
+	unknown := &client.Message{Quote: &client.TextQuote{Text: "story"}}
+	if messagetype.Detect(unknown) != messagetype.Unknown {
+		t.Fatalf("Detect()=%q for a quote without content, want %q", messagetype.Detect(unknown), messagetype.Unknown)
+	}
+
+	content := messagetype.Describe(msg)
+	if content.ReplyToStory == nil || content.ReplyToStory.Id != 7 {
+		t.Fatalf("ReplyToStory=%+v, want story 7", content.ReplyToStory)
+	}
+	if content.Quote == nil || content.Quote.Text != "story" {
+		t.Fatalf("Quote=%+v, want the quoted text", content.Quote)
+	}
+	if content.Text != caption || content.Media == nil || content.Media.File.FileID != "photo" {
+		t.Fatalf("Describe()=%+v, want the captioned photo", content)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	t.Run("photo with caption", func(t *testing.T) {
+		caption := "a cat"
+		small := 100
+		large := 4000
+		msg := &client.Message{
+			Caption:         &caption,
+			CaptionEntities: &[]client.MessageEntity{{Type: "bold", Offset: 0, Length: 1}},
+			Photo: &[]client.PhotoSize{
+				{FileId: "small", FileUniqueId: "u-small", Width: 90, Height: 90, FileSize: &small},
+				{FileId: "large", FileUniqueId: "u-large", Width: 1280, Height: 960, FileSize: &large},
+			},
+		}
+
+		content := messagetype.Describe(msg)
+		if content.Text != caption {
+			t.Fatalf("Text=%q, want %q", content.Text, caption)
+		}
+		if len(content.Entities) != 1 {
+			t.Fatalf("Entities=%v, want 1 entity", content.Entities)
+		}
+		if !content.Has(messagetype.Photo) {
+			t.Fatalf("Types=%v, want photo", content.Types)
+		}
+		if content.Media == nil {
+			t.Fatal("Media is nil")
+		}
+		if content.Media.Type != messagetype.Photo {
+			t.Fatalf("Media.Type=%q, want photo", content.Media.Type)
+		}
+		if content.Media.File.FileID != "large" || content.Media.File.FileSize != 4000 {
+			t.Fatalf("Media.File=%+v, want largest size", content.Media.File)
+		}
+		if len(content.Media.Sizes) != 2 {
+			t.Fatalf("Media.Sizes=%d, want 2", len(content.Media.Sizes))
+		}
+	})
+
+	t.Run("document", func(t *testing.T) {
+		name := "report.pdf"
+		mime := "application/pdf"
+		size := int64(1 << 20)
+		msg := &client.Message{
+			Document: &client.Document{
+				FileId:       "doc",
+				FileUniqueId: "u-doc",
+				FileName:     &name,
+				MimeType:     &mime,
+				FileSize:     &size,
+				Thumbnail:    &client.PhotoSize{FileId: "thumb"},
+			},
+		}
+
+		media := messagetype.DescribeMedia(msg)
+		if media == nil {
+			t.Fatal("DescribeMedia() is nil")
+		}
+
+		want := messagetype.FileRef{
+			FileID:       "doc",
+			FileUniqueID: "u-doc",
+			FileSize:     size,
+			MimeType:     mime,
+			FileName:     name,
+		}
+		if media.File != want {
+			t.Fatalf("File=%+v, want %+v", media.File, want)
+		}
+		if media.Thumbnail == nil || media.Thumbnail.FileID != "thumb" {
+			t.Fatalf("Thumbnail=%+v, want thumb", media.Thumbnail)
+		}
+	})
+
+	t.Run("animation wins over document", func(t *testing.T) {
+		msg := &client.Message{
+			Animation: &client.Animation{FileId: "anim", Duration: 3},
+			Document:  &client.Document{FileId: "doc"},
+		}
+
+		media := messagetype.DescribeMedia(msg)
+		if media == nil || media.Type != messagetype.Animation || media.File.Duration != 3 {
+			t.Fatalf("DescribeMedia()=%+v, want animation", media)
+		}
+	})
+
+	t.Run("paid media", func(t *testing.T) {
+		msg := &client.Message{PaidMedia: &client.PaidMediaInfo{StarCount: 10, PaidMedia: []client.PaidMedia{
+			{"type": "preview", "width": 640, "height": 480},
+			{"type": "photo", "photo": []any{map[string]any{"file_id": "photo", "file_unique_id": "u-photo", "width": 90, "height": 90}}},
+			{"type": "video", "video": map[string]any{"file_id": "video", "file_unique_id": "u-video", "duration": 5}},
+		}}}
+
+		media := messagetype.DescribeMedia(msg)
+		if media == nil || media.Type != messagetype.PaidMedia || media.File.FileID != "photo" {
+			t.Fatalf("DescribeMedia()=%+v, want paid media led by the photo", media)
+		}
+		if len(media.Items) != 2 || media.Items[1].Type != messagetype.Video || media.Items[1].File.Duration != 5 {
+			t.Fatalf("Items=%+v, want the photo and the video", media.Items)
+		}
+
+		preview := &client.Message{PaidMedia: &client.PaidMediaInfo{PaidMedia: []client.PaidMedia{{"type": "preview"}}}}
+		if media := messagetype.DescribeMedia(preview); media != nil {
+			t.Fatalf("DescribeMedia(preview)=%+v, want nil", media)
+		}
+	})
+
+	t.Run("text without media", func(t *testing.T) {
+		text := "hello"
+		content := messagetype.Describe(&client.Message{Text: &text})
+		if content.Text != text || content.Media != nil {
+			t.Fatalf("Describe()=%+v, want text without media", content)
+		}
+	})
+}
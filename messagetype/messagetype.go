@@ -11,168 +11,137 @@ type MessageType string
 
 // Detect inspects a Telegram message and returns its most specific type.
 // It prioritizes service messages (e.g., chat member changes) over standard content (e.g., text).
-//
-//nolint:gocyclo,cyclop,funlen,maintidx
 func Detect(message *client.Message) MessageType {
 	if message == nil {
 		return Unknown
 	}
 
-	switch {
+	for _, d := range builtinDetectors {
+		if d.present(message) {
+			return d.messageType
+		}
+	}
+
+	return Unknown
+}
+
+// DetectAll returns every type present on a Telegram message, in the same priority
+// order used by Detect, followed by the reply context, such as Quote. An animation,
+// for example, is reported as both Animation and Document because Telegram fills
+// both fields for backward compatibility. It returns nil when no known type is
+// present.
+func DetectAll(message *client.Message) []MessageType {
+	if message == nil {
+		return nil
+	}
+
+	var types []MessageType
+
+	for _, detectors := range [][]builtinDetector{builtinDetectors, replyContextDetectors} {
+		for _, d := range detectors {
+			if d.present(message) {
+				types = append(types, d.messageType)
+			}
+		}
+	}
+
+	return types
+}
+
+type builtinDetector struct {
+	messageType MessageType
+	present     func(message *client.Message) bool
+}
+
+// builtinDetectors lists the known message fields in detection priority order:
+// service messages first, then standard content, then the context of replies.
+var builtinDetectors = []builtinDetector{
 	// Service messages
-	case message.NewChatMembers != nil:
-		return NewChatMembers
-	case message.LeftChatMember != nil:
-		return LeftChatMember
-	case message.NewChatTitle != nil:
-		return NewChatTitle
-	case message.NewChatPhoto != nil:
-		return NewChatPhoto
-	case message.DeleteChatPhoto != nil:
-		return DeleteChatPhoto
-	case message.GroupChatCreated != nil:
-		return GroupChatCreated
-	case message.SupergroupChatCreated != nil:
-		return SupergroupChatCreated
-	case message.ChannelChatCreated != nil:
-		return ChannelChatCreated
-	case message.MessageAutoDeleteTimerChanged != nil:
-		return MessageAutoDeleteTimerChanged
-	case message.MigrateToChatId != nil:
-		return MigrateToChatID
-	case message.MigrateFromChatId != nil:
-		return MigrateFromChatID
-	case message.PinnedMessage != nil:
-		return PinnedMessage
-	case message.SuccessfulPayment != nil:
-		return SuccessfulPayment
-	case message.RefundedPayment != nil:
-		return RefundedPayment
-	case message.UsersShared != nil:
-		return UsersShared
-	case message.ChatShared != nil:
-		return ChatShared
-	case message.ChatOwnerChanged != nil:
-		return ChatOwnerChanged
-	case message.ChatOwnerLeft != nil:
-		return ChatOwnerLeft
-	case message.WriteAccessAllowed != nil:
-		return WriteAccessAllowed
-	case message.ProximityAlertTriggered != nil:
-		return ProximityAlertTriggered
-	case message.ForumTopicCreated != nil:
-		return ForumTopicCreated
-	case message.ForumTopicEdited != nil:
-		return ForumTopicEdited
-	case message.ForumTopicClosed != nil:
-		return ForumTopicClosed
-	case message.ForumTopicReopened != nil:
-		return ForumTopicReopened
-	case message.GeneralForumTopicHidden != nil:
-		return GeneralForumTopicHidden
-	case message.GeneralForumTopicUnhidden != nil:
-		return GeneralForumTopicUnhidden
-	case message.VideoChatScheduled != nil:
-		return VideoChatScheduled
-	case message.VideoChatStarted != nil:
-		return VideoChatStarted
-	case message.VideoChatEnded != nil:
-		return VideoChatEnded
-	case message.VideoChatParticipantsInvited != nil:
-		return VideoChatParticipantsInvited
-	case message.WebAppData != nil:
-		return WebAppData
-	case message.BoostAdded != nil:
-		return BoostAdded
-	case message.ChatBackgroundSet != nil:
-		return ChatBackgroundSet
-	case message.ChecklistTasksAdded != nil:
-		return ChecklistTasksAdded
-	case message.ChecklistTasksDone != nil:
-		return ChecklistTasksDone
-	case message.ManagedBotCreated != nil:
-		return ManagedBotCreated
-	case message.PollOptionAdded != nil:
-		return PollOptionAdded
-	case message.PollOptionDeleted != nil:
-		return PollOptionDeleted
-	case message.DirectMessagePriceChanged != nil:
-		return DirectMessagePriceChanged
-	case message.Gift != nil:
-		return Gift
-	case message.GiftUpgradeSent != nil:
-		return GiftUpgradeSent
-	case message.GiveawayCompleted != nil:
-		return GiveawayCompleted
-	case message.GiveawayCreated != nil:
-		return GiveawayCreated
-	case message.GiveawayWinners != nil:
-		return GiveawayWinners
-	case message.PaidMessagePriceChanged != nil:
-		return PaidMessagePriceChanged
-	case message.SuggestedPostApprovalFailed != nil:
-		return SuggestedPostApprovalFailed
-	case message.SuggestedPostApproved != nil:
-		return SuggestedPostApproved
-	case message.SuggestedPostDeclined != nil:
-		return SuggestedPostDeclined
-	case message.SuggestedPostPaid != nil:
-		return SuggestedPostPaid
-	case message.SuggestedPostRefunded != nil:
-		return SuggestedPostRefunded
-	case message.UniqueGift != nil:
-		return UniqueGift
-	case message.PassportData != nil:
-		return PassportData
-	case message.ConnectedWebsite != nil:
-		return ConnectedWebsite
+	{NewChatMembers, func(m *client.Message) bool { return m.NewChatMembers != nil }},
+	{LeftChatMember, func(m *client.Message) bool { return m.LeftChatMember != nil }},
+	{NewChatTitle, func(m *client.Message) bool { return m.NewChatTitle != nil }},
+	{NewChatPhoto, func(m *client.Message) bool { return m.NewChatPhoto != nil }},
+	{DeleteChatPhoto, func(m *client.Message) bool { return m.DeleteChatPhoto != nil }},
+	{GroupChatCreated, func(m *client.Message) bool { return m.GroupChatCreated != nil }},
+	{SupergroupChatCreated, func(m *client.Message) bool { return m.SupergroupChatCreated != nil }},
+	{ChannelChatCreated, func(m *client.Message) bool { return m.ChannelChatCreated != nil }},
+	{MessageAutoDeleteTimerChanged, func(m *client.Message) bool { return m.MessageAutoDeleteTimerChanged != nil }},
+	{MigrateToChatID, func(m *client.Message) bool { return m.MigrateToChatId != nil }},
+	{MigrateFromChatID, func(m *client.Message) bool { return m.MigrateFromChatId != nil }},
+	{PinnedMessage, func(m *client.Message) bool { return m.PinnedMessage != nil }},
+	{SuccessfulPayment, func(m *client.Message) bool { return m.SuccessfulPayment != nil }},
+	{RefundedPayment, func(m *client.Message) bool { return m.RefundedPayment != nil }},
+	{UsersShared, func(m *client.Message) bool { return m.UsersShared != nil }},
+	{ChatShared, func(m *client.Message) bool { return m.ChatShared != nil }},
+	{ChatOwnerChanged, func(m *client.Message) bool { return m.ChatOwnerChanged != nil }},
+	{ChatOwnerLeft, func(m *client.Message) bool { return m.ChatOwnerLeft != nil }},
+	{WriteAccessAllowed, func(m *client.Message) bool { return m.WriteAccessAllowed != nil }},
+	{ProximityAlertTriggered, func(m *client.Message) bool { return m.ProximityAlertTriggered != nil }},
+	{ForumTopicCreated, func(m *client.Message) bool { return m.ForumTopicCreated != nil }},
+	{ForumTopicEdited, func(m *client.Message) bool { return m.ForumTopicEdited != nil }},
+	{ForumTopicClosed, func(m *client.Message) bool { return m.ForumTopicClosed != nil }},
+	{ForumTopicReopened, func(m *client.Message) bool { return m.ForumTopicReopened != nil }},
+	{GeneralForumTopicHidden, func(m *client.Message) bool { return m.GeneralForumTopicHidden != nil }},
+	{GeneralForumTopicUnhidden, func(m *client.Message) bool { return m.GeneralForumTopicUnhidden != nil }},
+	{VideoChatScheduled, func(m *client.Message) bool { return m.VideoChatScheduled != nil }},
+	{VideoChatStarted, func(m *client.Message) bool { return m.VideoChatStarted != nil }},
+	{VideoChatEnded, func(m *client.Message) bool { return m.VideoChatEnded != nil }},
+	{VideoChatParticipantsInvited, func(m *client.Message) bool { return m.VideoChatParticipantsInvited != nil }},
+	{WebAppData, func(m *client.Message) bool { return m.WebAppData != nil }},
+	{BoostAdded, func(m *client.Message) bool { return m.BoostAdded != nil }},
+	{ChatBackgroundSet, func(m *client.Message) bool { return m.ChatBackgroundSet != nil }},
+	{ChecklistTasksAdded, func(m *client.Message) bool { return m.ChecklistTasksAdded != nil }},
+	{ChecklistTasksDone, func(m *client.Message) bool { return m.ChecklistTasksDone != nil }},
+	{ManagedBotCreated, func(m *client.Message) bool { return m.ManagedBotCreated != nil }},
+	{PollOptionAdded, func(m *client.Message) bool { return m.PollOptionAdded != nil }},
+	{PollOptionDeleted, func(m *client.Message) bool { return m.PollOptionDeleted != nil }},
+	{DirectMessagePriceChanged, func(m *client.Message) bool { return m.DirectMessagePriceChanged != nil }},
+	{Gift, func(m *client.Message) bool { return m.Gift != nil }},
+	{GiftUpgradeSent, func(m *client.Message) bool { return m.GiftUpgradeSent != nil }},
+	{GiveawayCompleted, func(m *client.Message) bool { return m.GiveawayCompleted != nil }},
+	{GiveawayCreated, func(m *client.Message) bool { return m.GiveawayCreated != nil }},
+	{GiveawayWinners, func(m *client.Message) bool { return m.GiveawayWinners != nil }},
+	{PaidMessagePriceChanged, func(m *client.Message) bool { return m.PaidMessagePriceChanged != nil }},
+	{SuggestedPostApprovalFailed, func(m *client.Message) bool { return m.SuggestedPostApprovalFailed != nil }},
+	{SuggestedPostApproved, func(m *client.Message) bool { return m.SuggestedPostApproved != nil }},
+	{SuggestedPostDeclined, func(m *client.Message) bool { return m.SuggestedPostDeclined != nil }},
+	{SuggestedPostPaid, func(m *client.Message) bool { return m.SuggestedPostPaid != nil }},
+	{SuggestedPostRefunded, func(m *client.Message) bool { return m.SuggestedPostRefunded != nil }},
+	{UniqueGift, func(m *client.Message) bool { return m.UniqueGift != nil }},
+	{PassportData, func(m *client.Message) bool { return m.PassportData != nil }},
+	{ConnectedWebsite, func(m *client.Message) bool { return m.ConnectedWebsite != nil }},
 
 	// Standard messages
-	case message.Text != nil:
-		return Text
-	case message.Animation != nil:
-		return Animation
-	case message.Audio != nil:
-		return Audio
-	case message.Document != nil:
-		return Document
-	case message.Photo != nil:
-		return Photo
-	case message.Sticker != nil:
-		return Sticker
-	case message.Story != nil:
-		return Story
-	case message.LivePhoto != nil:
-		return LivePhoto
-	case message.Video != nil:
-		return Video
-	case message.VideoNote != nil:
-		return VideoNote
-	case message.Voice != nil:
-		return Voice
-	case message.Contact != nil:
-		return Contact
-	case message.Dice != nil:
-		return Dice
-	case message.Game != nil:
-		return Game
-	case message.Poll != nil:
-		return Poll
-	case message.Venue != nil:
-		return Venue
-	case message.Location != nil:
-		return Location
-	case message.Invoice != nil:
-		return Invoice
-	case message.Checklist != nil:
-		return Checklist
-	case message.PaidMedia != nil:
-		return PaidMedia
-	case message.Giveaway != nil:
-		return Giveaway
-	default:
-		return Unknown
-	}
+	{Text, func(m *client.Message) bool { return m.Text != nil }},
+	{Animation, func(m *client.Message) bool { return m.Animation != nil }},
+	{Audio, func(m *client.Message) bool { return m.Audio != nil }},
+	{Document, func(m *client.Message) bool { return m.Document != nil }},
+	{Photo, func(m *client.Message) bool { return m.Photo != nil }},
+	{Sticker, func(m *client.Message) bool { return m.Sticker != nil }},
+	{Story, func(m *client.Message) bool { return m.Story != nil }},
+	{LivePhoto, func(m *client.Message) bool { return m.LivePhoto != nil }},
+	{Video, func(m *client.Message) bool { return m.Video != nil }},
+	{VideoNote, func(m *client.Message) bool { return m.VideoNote != nil }},
+	{Voice, func(m *client.Message) bool { return m.Voice != nil }},
+	{Contact, func(m *client.Message) bool { return m.Contact != nil }},
+	{Dice, func(m *client.Message) bool { return m.Dice != nil }},
+	{Game, func(m *client.Message) bool { return m.Game != nil }},
+	{Poll, func(m *client.Message) bool { return m.Poll != nil }},
+	{Venue, func(m *client.Message) bool { return m.Venue != nil }},
+	{Location, func(m *client.Message) bool { return m.Location != nil }},
+	{Invoice, func(m *client.Message) bool { return m.Invoice != nil }},
+	{Checklist, func(m *client.Message) bool { return m.Checklist != nil }},
+	{PaidMedia, func(m *client.Message) bool { return m.PaidMedia != nil }},
+	{Giveaway, func(m *client.Message) bool { return m.Giveaway != nil }},
+}
+
+// replyContextDetectors lists the reply context fields. Only DetectAll reports
+// them, after the content types, so that Detect keeps returning the content
+// of a reply, or Unknown.
+var replyContextDetectors = []builtinDetector{
+	{ReplyToStory, func(m *client.Message) bool { return m.ReplyToStory != nil }},
+	{ExternalReply, func(m *client.Message) bool { return m.ExternalReply != nil }},
+	{Quote, func(m *client.Message) bool { return m.Quote != nil }},
 }
 
 // Standard Content Types.
@@ -200,6 +169,14 @@ const (
 	Giveaway  MessageType = "giveaway"
 )
 
+// Reply Context. They accompany the content of a reply; DetectAll and
+// Describe report them, Detect never does.
+const (
+	ReplyToStory  MessageType = "reply_to_story"
+	ExternalReply MessageType = "external_reply"
+	Quote         MessageType = "quote"
+)
+
 // Chat Lifecycle & Management.
 const (
 	NewChatMembers                MessageType = "new_chat_members"
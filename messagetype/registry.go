@@ -0,0 +1,168 @@
+package messagetype
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/tgbotkit/client"
+)
+
+// PriorityBuiltin is the priority of the built-in field detection performed by Detect.
+// Custom detectors with a higher priority run before it and can override the built-in
+// type; detectors with a lower priority only apply to messages Detect reports as Unknown.
+const PriorityBuiltin = 0
+
+// Detector classifies a message as a custom type.
+type Detector interface {
+	// Detect returns the type of message and true if the detector recognizes it.
+	Detect(message *client.Message) (MessageType, bool)
+}
+
+// DetectorFunc is an adapter to allow the use of ordinary functions as Detector.
+type DetectorFunc func(message *client.Message) (MessageType, bool)
+
+var _ Detector = DetectorFunc(nil)
+
+// Detect calls f(message).
+func (f DetectorFunc) Detect(message *client.Message) (MessageType, bool) {
+	return f(message)
+}
+
+// Registry combines custom detectors with the built-in detection, ordered by priority.
+// It is safe for concurrent use.
+type Registry struct {
+	mu           sync.RWMutex
+	entries      []*registryEntry
+	nextSequence uint64
+}
+
+type registryEntry struct {
+	detector Detector
+	priority int
+	sequence uint64
+	builtin  bool
+}
+
+// NewRegistry creates a Registry that initially performs only built-in detection.
+func NewRegistry() *Registry {
+	r := &Registry{}
+	r.add(&registryEntry{priority: PriorityBuiltin, builtin: true})
+
+	return r
+}
+
+// Register adds a detector with the given priority. Higher priorities run first;
+// detectors with equal priority run in registration order. The returned function
+// removes the detector.
+func (r *Registry) Register(priority int, detector Detector) func() {
+	entry := &registryEntry{detector: detector, priority: priority}
+	r.add(entry)
+
+	return func() {
+		r.remove(entry)
+	}
+}
+
+// Detect returns the type reported by the highest-priority detector that recognizes
+// the message, or Unknown.
+func (r *Registry) Detect(message *client.Message) MessageType {
+	if message == nil {
+		return Unknown
+	}
+
+	for _, entry := range r.snapshot() {
+		if t, ok := entry.detect(message); ok {
+			return t
+		}
+	}
+
+	return Unknown
+}
+
+// DetectAll returns every type recognized by any detector, in priority order and
+// without duplicates.
+func (r *Registry) DetectAll(message *client.Message) []MessageType {
+	if message == nil {
+		return nil
+	}
+
+	var types []MessageType
+
+	seen := make(map[MessageType]struct{})
+
+	for _, entry := range r.snapshot() {
+		for _, t := range entry.detectAll(message) {
+			if _, ok := seen[t]; ok {
+				continue
+			}
+
+			seen[t] = struct{}{}
+			types = append(types, t)
+		}
+	}
+
+	return types
+}
+
+func (r *Registry) add(entry *registryEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextSequence++
+	entry.sequence = r.nextSequence
+	r.entries = append(r.entries, entry)
+
+	sort.SliceStable(r.entries, func(i, j int) bool {
+		if r.entries[i].priority != r.entries[j].priority {
+			return r.entries[i].priority > r.entries[j].priority
+		}
+
+		return r.entries[i].sequence < r.entries[j].sequence
+	})
+}
+
+func (r *Registry) remove(entry *registryEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, candidate := range r.entries {
+		if candidate == entry {
+			r.entries = append(r.entries[:i], r.entries[i+1:]...)
+
+			return
+		}
+	}
+}
+
+func (r *Registry) snapshot() []*registryEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]*registryEntry(nil), r.entries...)
+}
+
+func (e *registryEntry) detect(message *client.Message) (MessageType, bool) {
+	if e.builtin {
+		t := Detect(message)
+
+		return t, t != Unknown
+	}
+
+	if e.detector == nil {
+		return "", false
+	}
+
+	return e.detector.Detect(message)
+}
+
+func (e *registryEntry) detectAll(message *client.Message) []MessageType {
+	if e.builtin {
+		return DetectAll(message)
+	}
+
+	if t, ok := e.detect(message); ok {
+		return []MessageType{t}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package messagetype_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/messagetype"
+)
+
+const (
+	longVoice   messagetype.MessageType = "long_voice"
+	containsURL messagetype.MessageType = "contains_url"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := messagetype.NewRegistry()
+
+	registry.Register(10, messagetype.DetectorFunc(func(m *client.Message) (messagetype.MessageType, bool) {
+		return longVoice, m.Voice != nil && m.Voice.Duration > 60
+	}))
+	unregister := registry.Register(5, messagetype.DetectorFunc(func(m *client.Message) (messagetype.MessageType, bool) {
+		return containsURL, m.Text != nil && strings.Contains(*m.Text, "https://")
+	}))
+
+	short := &client.Message{Voice: &client.Voice{Duration: 5}}
+	if got := registry.Detect(short); got != messagetype.Voice {
+		t.Fatalf("Detect(short voice)=%q, want %q", got, messagetype.Voice)
+	}
+
+	long := &client.Message{Voice: &client.Voice{Duration: 61}}
+	if got := registry.Detect(long); got != longVoice {
+		t.Fatalf("Detect(long voice)=%q, want %q", got, longVoice)
+	}
+	if got, want := registry.DetectAll(long), []messagetype.MessageType{longVoice, messagetype.Voice}; !slices.Equal(got, want) {
+		t.Fatalf("DetectAll(long voice)=%v, want %v", got, want)
+	}
+
+	text := "see https://example.com"
+	link := &client.Message{Text: &text}
+	if got := registry.Detect(link); got != containsURL {
+		t.Fatalf("Detect(link)=%q, want %q", got, containsURL)
+	}
+
+	unregister()
+	if got := registry.Detect(link); got != messagetype.Text {
+		t.Fatalf("Detect(link) after unregister=%q, want %q", got, messagetype.Text)
+	}
+}
+
+func TestRegistryLowPriorityFallback(t *testing.T) {
+	const fallback messagetype.MessageType = "fallback"
+
+	registry := messagetype.NewRegistry()
+	registry.Register(messagetype.PriorityBuiltin-1, messagetype.DetectorFunc(func(_ *client.Message) (messagetype.MessageType, bool) {
+		return fallback, true
+	}))
+
+	text := "hello"
+	if got := registry.Detect(&client.Message{Text: &text}); got != messagetype.Text {
+		t.Fatalf("Detect(text)=%q, want %q", got, messagetype.Text)
+	}
+	if got := registry.Detect(&client.Message{}); got != fallback {
+		t.Fatalf("Detect(empty)=%q, want %q", got, fallback)
+	}
+	if got := registry.Detect(nil); got != messagetype.Unknown {
+		t.Fatalf("Detect(nil)=%q, want %q", got, messagetype.Unknown)
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+// ChatAction returns a middleware that shows action, such as
+// respond.ActionTyping, in the chat of a message, command or callback query
+// event while the next listener runs. The action is refreshed until the
+// listener returns. Other events pass through, and a failure to send the
+// action does not stop the listener.
+//
+// Added to the event emitter, it runs for every listener of the event,
+// including those whose matcher rejects it. To show an action only for the
+// handler that runs, add it to a registry with handlers.WithMiddleware.
+func ChatAction(responder *respond.Responder, action string) eventemitter.Middleware {
+	return eventemitter.MiddlewareFunc(func(next eventemitter.Listener) eventemitter.Listener {
+		return eventemitter.ListenerFunc(func(ctx context.Context, payload any) error {
+			target, ok := chatActionTarget(payload)
+			if !ok {
+				return next.Handle(ctx, payload)
+			}
+
+			stop, _ := responder.KeepAction(ctx, target, action)
+			defer stop()
+
+			return next.Handle(ctx, payload)
+		})
+	})
+}
+
+func chatActionTarget(payload any) (respond.ChatTarget, bool) {
+	var (
+		target respond.ChatTarget
+		err    error
+	)
+
+	switch event := payload.(type) {
+	case *events.MessageEvent:
+		target, err = respond.TargetFromMessage(event.Message)
+	case *events.CommandEvent:
+		target, err = respond.TargetFromMessage(event.Message)
+	case *events.CallbackQueryEvent:
+		message, messageErr := respond.CallbackMessage(event.CallbackQuery)
+		if messageErr != nil {
+			return respond.ChatTarget{}, false
+		}
+
+		target, err = respond.TargetFromMessage(message)
+	default:
+		return respond.ChatTarget{}, false
+	}
+
+	return target, err == nil
+}
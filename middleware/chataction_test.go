@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+type actionClient struct {
+	client.ClientWithResponsesInterface
+	actions []client.SendChatActionJSONRequestBody
+}
+
+func (c *actionClient) SendChatActionWithResponse(
+	_ context.Context,
+	body client.SendChatActionJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.SendChatActionResponse, error) {
+	c.actions = append(c.actions, body)
+
+	return &client.SendChatActionResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"},
+		JSON200: &struct {
+			Ok     client.SendChatAction200Ok `json:"ok"`
+			Result bool                       `json:"result"`
+		}{Ok: true, Result: true},
+	}, nil
+}
+
+func TestChatAction(t *testing.T) {
+	t.Run("sends the action before the listener runs", func(t *testing.T) {
+		api := &actionClient{}
+		mw := ChatAction(respond.New(api), respond.ActionUploadDocument)
+
+		var before int
+		next := eventemitter.ListenerFunc(func(_ context.Context, _ any) error {
+			before = len(api.actions)
+
+			return nil
+		})
+
+		event := &events.CommandEvent{Message: &client.Message{Chat: client.Chat{Id: 7}}}
+		if err := mw.Handle(next).Handle(context.Background(), event); err != nil {
+			t.Fatalf("Handle() unexpected error: %v", err)
+		}
+
+		if before != 1 || api.actions[0].ChatId != 7 || api.actions[0].Action != respond.ActionUploadDocument {
+			t.Fatalf("actions=%+v before the listener, want upload_document in chat 7", api.actions)
+		}
+	})
+
+	t.Run("passes other events through", func(t *testing.T) {
+		api := &actionClient{}
+		mw := ChatAction(respond.New(api), respond.ActionTyping)
+
+		var called bool
+		next := eventemitter.ListenerFunc(func(_ context.Context, _ any) error {
+			called = true
+
+			return nil
+		})
+
+		if err := mw.Handle(next).Handle(context.Background(), &events.InlineQueryEvent{}); err != nil {
+			t.Fatalf("Handle() unexpected error: %v", err)
+		}
+
+		if !called || len(api.actions) != 0 {
+			t.Fatalf("called=%v actions=%d, want the listener called without actions", called, len(api.actions))
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/i18n"
+)
+
+// Localizer returns a middleware that resolves the locale of every event and
+// puts a Localizer for it into the context, where handlers get it with
+// i18n.FromContext. A nil resolve uses i18n.LanguageCode, the language
+// Telegram reports for the user.
+func Localizer(bundle *i18n.Bundle, resolve i18n.Resolver) eventemitter.Middleware {
+	if resolve == nil {
+		resolve = i18n.LanguageCode
+	}
+
+	return eventemitter.MiddlewareFunc(func(next eventemitter.Listener) eventemitter.Listener {
+		return eventemitter.ListenerFunc(func(ctx context.Context, payload any) error {
+			ctx = i18n.WithLocalizer(ctx, bundle.Localizer(resolve(ctx, payload)))
+
+			return next.Handle(ctx, payload)
+		})
+	})
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/i18n"
+)
+
+func TestLocalizer(t *testing.T) {
+	bundle, err := i18n.New(i18n.NewOptions())
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	if err := bundle.AddMessages("de", map[string]string{"hi": "Hallo"}); err != nil {
+		t.Fatalf("AddMessages() unexpected error: %v", err)
+	}
+
+	var got string
+	next := eventemitter.ListenerFunc(func(ctx context.Context, _ any) error {
+		got = i18n.FromContext(ctx).T("hi", nil)
+
+		return nil
+	})
+
+	language := "de"
+	event := &events.MessageEvent{Message: &client.Message{From: &client.User{LanguageCode: &language}}}
+
+	if err := Localizer(bundle, nil).Handle(next).Handle(context.Background(), event); err != nil {
+		t.Fatalf("Handle() unexpected error: %v", err)
+	}
+
+	if got != "Hallo" {
+		t.Fatalf("T()=%q, want %q", got, "Hallo")
+	}
+}
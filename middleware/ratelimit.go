@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/botcontext"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/ratelimit"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+// rateLimitDecisions is how many recent updates RateLimit remembers the
+// decision of, so that every event and listener of an update shares it.
+const rateLimitDecisions = 1024
+
+// RateLimitOption configures the RateLimit middleware.
+type RateLimitOption func(*rateLimiter)
+
+// WithRateLimitKey sets how updates are counted: ratelimit.PerUser, the
+// default, ratelimit.PerChat, ratelimit.PerCommand or a custom KeyFunc.
+func WithRateLimitKey(key ratelimit.KeyFunc) RateLimitOption {
+	return func(r *rateLimiter) {
+		if key != nil {
+			r.key = key
+		}
+	}
+}
+
+// WithExemptUsers lets the updates of userIDs, such as the bot's
+// administrators, through without counting them.
+func WithExemptUsers(userIDs ...int64) RateLimitOption {
+	return func(r *rateLimiter) {
+		for _, id := range userIDs {
+			r.exempt[id] = true
+		}
+	}
+}
+
+// WithCooldownReply answers the first update of a key over the limit with
+// text, sent with responder to the chat of a message or as the answer of a
+// callback query. The later updates of the key are dropped silently until it
+// is allowed again.
+func WithCooldownReply(responder *respond.Responder, text string) RateLimitOption {
+	return func(r *rateLimiter) {
+		r.responder = responder
+		r.cooldown = text
+	}
+}
+
+// WithRateLimitBreak makes the middleware return eventemitter.ErrBreak for
+// updates over the limit, which also stops the later listeners of the event.
+func WithRateLimitBreak() RateLimitOption {
+	return func(r *rateLimiter) {
+		r.breaks = true
+	}
+}
+
+// RateLimit returns a middleware that drops the events of the updates over
+// the limit of limiter. Updates are counted once, whatever event carries
+// them, under the key the update gives: per user by default. Updates without
+// a key, and events raised outside an update, pass through. A failure of the
+// limiter's store lets the update through.
+//
+// Added to the event emitter for events.OnUpdate, it stops updates over the
+// limit before any other event is derived from them. Added to a registry with
+// handlers.WithMiddleware, it limits the handlers of that registry only.
+func RateLimit(limiter *ratelimit.Limiter, opts ...RateLimitOption) eventemitter.Middleware {
+	r := &rateLimiter{
+		limiter:   limiter,
+		key:       ratelimit.PerUser,
+		exempt:    make(map[int64]bool),
+		decisions: make(map[int]*rateLimitDecision),
+		notified:  make(map[string]time.Time),
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+
+	return eventemitter.MiddlewareFunc(func(next eventemitter.Listener) eventemitter.Listener {
+		return eventemitter.ListenerFunc(func(ctx context.Context, payload any) error {
+			update := eventUpdate(ctx, payload)
+			if update == nil {
+				return next.Handle(ctx, payload)
+			}
+
+			decision := r.decision(update.UpdateId)
+			decision.once.Do(func() {
+				decision.allowed = r.allow(ctx, update)
+			})
+
+			switch {
+			case decision.allowed:
+				return next.Handle(ctx, payload)
+			case r.breaks:
+				return eventemitter.ErrBreak
+			default:
+				return nil
+			}
+		})
+	})
+}
+
+type rateLimiter struct {
+	limiter   *ratelimit.Limiter
+	key       ratelimit.KeyFunc
+	exempt    map[int64]bool
+	responder *respond.Responder
+	cooldown  string
+	breaks    bool
+
+	// mu guards decisions, the decisions of recent updates by update ID in
+	// the order of order, and notified, when the keys told to cool down may
+	// be told again.
+	mu        sync.Mutex
+	decisions map[int]*rateLimitDecision
+	order     []int
+	notified  map[string]time.Time
+}
+
+type rateLimitDecision struct {
+	once    sync.Once
+	allowed bool
+}
+
+// decision returns the decision of updateID, remembering it among the
+// recent ones.
+func (r *rateLimiter) decision(updateID int) *rateLimitDecision {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if decision, ok := r.decisions[updateID]; ok {
+		return decision
+	}
+
+	if len(r.order) == rateLimitDecisions {
+		delete(r.decisions, r.order[0])
+		r.order = r.order[1:]
+	}
+
+	decision := &rateLimitDecision{}
+	r.decisions[updateID] = decision
+	r.order = append(r.order, updateID)
+
+	return decision
+}
+
+// allow counts update and reports whether it is within the limit, telling
+// its sender to cool down when it is not.
+func (r *rateLimiter) allow(ctx context.Context, update *client.Update) bool {
+	if userID, _ := ratelimit.Sender(update); r.exempt[userID] && userID != 0 {
+		return true
+	}
+
+	key, ok := r.key(update)
+	if !ok {
+		return true
+	}
+
+	allowed, retryAfter, err := r.limiter.Allow(ctx, key)
+	if err != nil {
+		logErrorf(ctx, "rate limit update %d: %v", update.UpdateId, err)
+
+		return true
+	}
+
+	if !allowed && r.responder != nil && r.notify(key, retryAfter) {
+		if err := r.reply(ctx, update); err != nil {
+			logErrorf(ctx, "reply to rate limited update %d: %v", update.UpdateId, err)
+		}
+	}
+
+	return allowed
+}
+
+// notify reports whether key should be told to cool down, which it is once
+// until it is allowed again.
+func (r *rateLimiter) notify(key string, retryAfter time.Duration) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if until, ok := r.notified[key]; ok && now.Before(until) {
+		return false
+	}
+
+	for k, until := range r.notified {
+		if !now.Before(until) {
+			delete(r.notified, k)
+		}
+	}
+
+	r.notified[key] = now.Add(retryAfter)
+
+	return true
+}
+
+func (r *rateLimiter) reply(ctx context.Context, update *client.Update) error {
+	if update.CallbackQuery != nil {
+		return r.responder.AnswerCallbackText(ctx, update.CallbackQuery, r.cooldown)
+	}
+
+	if update.Message == nil {
+		return nil
+	}
+
+	_, err := r.responder.SendTextInChat(ctx, update.Message, r.cooldown)
+
+	return err
+}
+
+// eventUpdate returns the update that caused the event carried by payload.
+func eventUpdate(ctx context.Context, payload any) *client.Update {
+	if update := events.UpdateFromContext(ctx); update != nil {
+		return update
+	}
+
+	if event, ok := payload.(*events.UpdateEvent); ok {
+		return event.Update
+	}
+
+	return nil
+}
+
+// logErrorf logs with the logger of the bot in ctx, if any.
+func logErrorf(ctx context.Context, format string, args ...any) {
+	if bot := botcontext.FromContext(ctx); bot != nil && bot.Logger() != nil {
+		bot.Logger().Errorf(format, args...)
+	}
+}
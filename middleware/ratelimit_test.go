@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/ratelimit"
+	"github.com/tgbotkit/runtime/ratelimit/counterstore"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+type replyClient struct {
+	client.ClientWithResponsesInterface
+	sent []client.SendMessageJSONRequestBody
+}
+
+func (c *replyClient) SendMessageWithResponse(
+	_ context.Context,
+	body client.SendMessageJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.SendMessageResponse, error) {
+	c.sent = append(c.sent, body)
+
+	return &client.SendMessageResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"},
+		JSON200: &struct {
+			Ok     client.SendMessage200Ok `json:"ok"`
+			Result client.Message          `json:"result"`
+		}{Ok: true, Result: client.Message{Chat: client.Chat{Id: body.ChatId}}},
+	}, nil
+}
+
+func newTestLimiter(t *testing.T, burst int) *ratelimit.Limiter {
+	t.Helper()
+
+	limiter, err := ratelimit.New(ratelimit.NewOptions(counterstore.NewInMemoryStore(), ratelimit.TokenBucket(burst, time.Hour)))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	return limiter
+}
+
+func textUpdate(updateID int, userID int64, text string) *client.Update {
+	return &client.Update{
+		UpdateId: updateID,
+		Message: &client.Message{
+			MessageId: updateID,
+			From:      &client.User{Id: userID},
+			Chat:      client.Chat{Id: userID},
+			Text:      &text,
+		},
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Run("counts each update once across events and listeners", func(t *testing.T) {
+		api := &replyClient{}
+		mw := RateLimit(newTestLimiter(t, 1), WithCooldownReply(respond.New(api), "Slow down"))
+
+		var handled []int
+		next := mw.Handle(eventemitter.ListenerFunc(func(ctx context.Context, _ any) error {
+			handled = append(handled, events.UpdateFromContext(ctx).UpdateId)
+
+			return nil
+		}))
+
+		for id := 1; id <= 3; id++ {
+			update := textUpdate(id, 7, "hi")
+			ctx := events.WithUpdate(context.Background(), update)
+
+			// The update and the message derived from it are one hit.
+			for _, payload := range []any{&events.UpdateEvent{Update: update}, &events.MessageEvent{Message: update.Message}} {
+				if err := next.Handle(ctx, payload); err != nil {
+					t.Fatalf("Handle() unexpected error: %v", err)
+				}
+			}
+		}
+
+		if len(handled) != 2 || handled[0] != 1 || handled[1] != 1 {
+			t.Fatalf("handled=%v, want both events of update 1 only", handled)
+		}
+		if len(api.sent) != 1 || api.sent[0].Text != "Slow down" || api.sent[0].ChatId != 7 {
+			t.Fatalf("sent=%+v, want one cooldown reply in chat 7", api.sent)
+		}
+	})
+
+	t.Run("breaks and exempts privileged users", func(t *testing.T) {
+		mw := RateLimit(newTestLimiter(t, 1), WithRateLimitBreak(), WithExemptUsers(1))
+		next := mw.Handle(eventemitter.ListenerFunc(func(context.Context, any) error { return nil }))
+
+		handle := func(update *client.Update) error {
+			return next.Handle(context.Background(), &events.UpdateEvent{Update: update})
+		}
+
+		for id := 1; id <= 3; id++ {
+			if err := handle(textUpdate(id, 1, "hi")); err != nil {
+				t.Fatalf("Handle(exempt) error=%v, want nil", err)
+			}
+		}
+
+		if err := handle(textUpdate(4, 2, "hi")); err != nil {
+			t.Fatalf("Handle() error=%v, want nil", err)
+		}
+		if err := handle(textUpdate(5, 2, "hi")); !errors.Is(err, eventemitter.ErrBreak) {
+			t.Fatalf("Handle() error=%v, want ErrBreak", err)
+		}
+	})
+
+	t.Run("limits each command separately", func(t *testing.T) {
+		mw := RateLimit(newTestLimiter(t, 1), WithRateLimitKey(ratelimit.PerCommand))
+
+		var handled int
+		next := mw.Handle(eventemitter.ListenerFunc(func(context.Context, any) error {
+			handled++
+
+			return nil
+		}))
+
+		for id, text := range []string{"/start", "/help@bot", "/start x", "hello", "hello"} {
+			if err := next.Handle(context.Background(), &events.UpdateEvent{Update: textUpdate(id, 7, text)}); err != nil {
+				t.Fatalf("Handle() unexpected error: %v", err)
+			}
+		}
+
+		if handled != 4 {
+			t.Fatalf("handled=%d, want all but the second /start", handled)
+		}
+	})
+}
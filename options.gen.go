@@ -9,8 +9,11 @@ import (
 	errors461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/errors"
 	validator461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/validator"
 	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/dedup"
 	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/listeners"
 	"github.com/tgbotkit/runtime/logger"
+	"github.com/tgbotkit/runtime/throttle"
 )
 
 type OptOptionsSetter func(o *Options)
@@ -45,6 +48,12 @@ func WithClient(opt client.ClientWithResponsesInterface) OptOptionsSetter {
 	return func(o *Options) { o.client = opt }
 }
 
+// limiter schedules the requests of the default client within flood limits.
+// A custom client is throttled by passing the limiter to client.WithHTTPClient.
+func WithLimiter(opt *throttle.Limiter) OptOptionsSetter {
+	return func(o *Options) { o.limiter = opt }
+}
+
 // eventEmitter is the event emitter to use.
 func WithEventEmitter(opt eventemitter.EventEmitter) OptOptionsSetter {
 	return func(o *Options) { o.eventEmitter = opt }
@@ -60,6 +69,16 @@ func WithLogger(opt logger.Logger) OptOptionsSetter {
 	return func(o *Options) { o.logger = opt }
 }
 
+// chatMigrationHooks rekey chat-scoped data when a group becomes a supergroup.
+func WithChatMigrationHooks(opt []listeners.ChatMigrationHook) OptOptionsSetter {
+	return func(o *Options) { o.chatMigrationHooks = opt }
+}
+
+// deduplicator drops redelivered updates before they are emitted.
+func WithDeduplicator(opt *dedup.Deduplicator) OptOptionsSetter {
+	return func(o *Options) { o.deduplicator = opt }
+}
+
 // startupTimeout bounds blocking startup API calls.
 func WithStartupTimeout(opt time.Duration) OptOptionsSetter {
 	return func(o *Options) { o.startupTimeout = opt }
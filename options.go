@@ -4,8 +4,11 @@ import (
 	"time"
 
 	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/dedup"
 	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/listeners"
 	"github.com/tgbotkit/runtime/logger"
+	"github.com/tgbotkit/runtime/throttle"
 )
 
 //go:generate go tool options-gen -out-filename=options.gen.go -from-struct=Options
@@ -19,12 +22,19 @@ type Options struct {
 
 	// client is the Telegram API client.
 	client client.ClientWithResponsesInterface
+	// limiter schedules the requests of the default client within flood limits.
+	// A custom client is throttled by passing the limiter to client.WithHTTPClient.
+	limiter *throttle.Limiter
 	// eventEmitter is the event emitter to use.
 	eventEmitter eventemitter.EventEmitter
 	// updateSource is the update source to use.
 	updateSource UpdateSource
 	// logger is the logger to use.
 	logger logger.Logger
+	// chatMigrationHooks rekey chat-scoped data when a group becomes a supergroup.
+	chatMigrationHooks []listeners.ChatMigrationHook
+	// deduplicator drops redelivered updates before they are emitted.
+	deduplicator *dedup.Deduplicator
 	// startupTimeout bounds blocking startup API calls.
 	startupTimeout time.Duration `default:"10s" validate:"gt=0"`
 	// defaultMiddlewareEnabled controls registration of runtime middleware.
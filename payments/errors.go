@@ -0,0 +1,15 @@
+package payments
+
+import "errors"
+
+// ErrInvalidInvoice is returned when an invoice breaks Telegram's limits.
+var ErrInvalidInvoice = errors.New("invalid invoice")
+
+// ErrNilInvoice is returned when an invoice is sent without one.
+var ErrNilInvoice = errors.New("nil invoice")
+
+// ErrNilQuery is returned when a shipping or pre-checkout query is answered without one.
+var ErrNilQuery = errors.New("nil query")
+
+// ErrNoShippingOptions is returned when a shipping query is accepted without shipping options.
+var ErrNoShippingOptions = errors.New("no shipping options")
@@ -0,0 +1,101 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tgbotkit/client"
+)
+
+// PreCheckoutValidator checks an order before the user is charged, such as
+// that the goods are still in stock. Returning an error rejects the checkout:
+// the message of an error made with Reject is shown to the user, and any other
+// error shows the configured error message.
+type PreCheckoutValidator interface {
+	ValidatePreCheckout(ctx context.Context, query *client.PreCheckoutQuery) error
+}
+
+// PreCheckoutValidatorFunc is an adapter to allow the use of ordinary functions as PreCheckoutValidator.
+type PreCheckoutValidatorFunc func(ctx context.Context, query *client.PreCheckoutQuery) error
+
+var _ PreCheckoutValidator = PreCheckoutValidatorFunc(nil)
+
+// ValidatePreCheckout calls f(ctx, query).
+func (f PreCheckoutValidatorFunc) ValidatePreCheckout(ctx context.Context, query *client.PreCheckoutQuery) error {
+	return f(ctx, query)
+}
+
+// ShippingQuoter returns the shipping options available for the address of a
+// shipping query. Errors reject the query like those of PreCheckoutValidator.
+type ShippingQuoter interface {
+	ShippingOptions(ctx context.Context, query *client.ShippingQuery) ([]client.ShippingOption, error)
+}
+
+// ShippingQuoterFunc is an adapter to allow the use of ordinary functions as ShippingQuoter.
+type ShippingQuoterFunc func(ctx context.Context, query *client.ShippingQuery) ([]client.ShippingOption, error)
+
+var _ ShippingQuoter = ShippingQuoterFunc(nil)
+
+// ShippingOptions calls f(ctx, query).
+func (f ShippingQuoterFunc) ShippingOptions(
+	ctx context.Context,
+	query *client.ShippingQuery,
+) ([]client.ShippingOption, error) {
+	return f(ctx, query)
+}
+
+// RejectionError rejects a query with a message for the user.
+type RejectionError struct {
+	// Message is shown to the user.
+	Message string
+}
+
+// Reject returns an error that rejects a query with message, such as "Sorry,
+// we do not deliver to your country".
+func Reject(message string) error {
+	return &RejectionError{Message: message}
+}
+
+func (e *RejectionError) Error() string {
+	return "payment rejected: " + e.Message
+}
+
+// rejection returns the message to reject a query with after a hook failed,
+// and the error to report, nil for rejections made on purpose.
+func (p *Payments) rejection(err error) (string, error) {
+	var rejection *RejectionError
+	if errors.As(err, &rejection) && rejection.Message != "" {
+		return rejection.Message, nil
+	}
+
+	return p.opts.errorMessage, err
+}
+
+// runHook runs hook with the answer timeout. A hook that overruns it is left
+// to finish in the background, so that the query is answered in time.
+func runHook[T any](ctx context.Context, timeout time.Duration, hook func(ctx context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		value T
+		err   error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		value, err := hook(ctx)
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero T
+
+		return zero, ctx.Err()
+	}
+}
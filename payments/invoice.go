@@ -0,0 +1,268 @@
+package payments
+
+import (
+	"fmt"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tgbotkit/client"
+)
+
+// CurrencyStars is the currency code of payments in Telegram Stars.
+const CurrencyStars = "XTR"
+
+// SubscriptionPeriod is the only subscription period Telegram accepts.
+const SubscriptionPeriod = 30 * 24 * time.Hour
+
+const (
+	maxTitleLength       = 32
+	maxDescriptionLength = 255
+	maxPayloadSize       = 128
+	maxSuggestedTips     = 4
+	maxSubscriptionStars = 10000
+	currencyCodeLength   = 3
+)
+
+// Invoice describes a product to pay for. Build one with FiatInvoice or
+// StarsInvoice, which validate it against Telegram's limits.
+type Invoice struct {
+	Title       string
+	Description string
+	// Payload is passed back in the queries and the payment; it is not shown
+	// to the user.
+	Payload  string
+	Currency string
+	// ProviderToken is the token of the payment provider, empty for Stars.
+	ProviderToken string
+	Prices        []client.LabeledPrice
+
+	MaxTipAmount        int
+	SuggestedTipAmounts []int
+	// ProviderData is JSON shared with the payment provider.
+	ProviderData string
+	PhotoURL     string
+	PhotoWidth   int
+	PhotoHeight  int
+
+	NeedName                  bool
+	NeedPhoneNumber           bool
+	NeedEmail                 bool
+	NeedShippingAddress       bool
+	SendPhoneNumberToProvider bool
+	SendEmailToProvider       bool
+	// IsFlexible asks for a shipping query to price delivery.
+	IsFlexible bool
+	// SubscriptionPeriod makes the invoice a subscription renewed every
+	// period. Only Stars invoices sent as links can be subscriptions.
+	SubscriptionPeriod time.Duration
+	// StartParameter makes forwarded copies of a sent invoice link to the bot
+	// with this start parameter instead of showing a Pay button.
+	StartParameter string
+}
+
+// Stars reports whether the invoice is paid in Telegram Stars.
+func (i *Invoice) Stars() bool {
+	return i.Currency == CurrencyStars
+}
+
+// Total returns the sum of the invoice prices, in the smallest units of the
+// currency.
+func (i *Invoice) Total() int {
+	total := 0
+	for _, price := range i.Prices {
+		total += price.Amount
+	}
+
+	return total
+}
+
+// InvoiceBuilder builds an Invoice.
+type InvoiceBuilder struct {
+	invoice Invoice
+}
+
+// FiatInvoice starts an invoice paid in currency, a three-letter ISO 4217
+// code, through the payment provider of providerToken. Add its prices with
+// Price.
+func FiatInvoice(providerToken, currency, title, description, payload string) *InvoiceBuilder {
+	return &InvoiceBuilder{invoice: Invoice{
+		Title:         title,
+		Description:   description,
+		Payload:       payload,
+		Currency:      currency,
+		ProviderToken: providerToken,
+	}}
+}
+
+// StarsInvoice starts an invoice of stars Telegram Stars. Stars invoices have
+// exactly one price and do not take tips, shipping or contact details.
+func StarsInvoice(title, description, payload string, stars int) *InvoiceBuilder {
+	return &InvoiceBuilder{invoice: Invoice{
+		Title:       title,
+		Description: description,
+		Payload:     payload,
+		Currency:    CurrencyStars,
+		Prices:      []client.LabeledPrice{{Label: title, Amount: stars}},
+	}}
+}
+
+// Price appends a portion of the price, such as the product, a tax or a
+// discount, in the smallest units of the currency.
+func (b *InvoiceBuilder) Price(label string, amount int) *InvoiceBuilder {
+	b.invoice.Prices = append(b.invoice.Prices, client.LabeledPrice{Label: label, Amount: amount})
+
+	return b
+}
+
+// Tips lets the user add a tip of up to maxAmount, suggesting up to four
+// amounts in increasing order.
+func (b *InvoiceBuilder) Tips(maxAmount int, suggested ...int) *InvoiceBuilder {
+	b.invoice.MaxTipAmount = maxAmount
+	b.invoice.SuggestedTipAmounts = suggested
+
+	return b
+}
+
+// Photo shows the product photo at url.
+func (b *InvoiceBuilder) Photo(url string, width, height int) *InvoiceBuilder {
+	b.invoice.PhotoURL = url
+	b.invoice.PhotoWidth = width
+	b.invoice.PhotoHeight = height
+
+	return b
+}
+
+// NeedName asks for the user's full name.
+func (b *InvoiceBuilder) NeedName() *InvoiceBuilder {
+	b.invoice.NeedName = true
+
+	return b
+}
+
+// NeedPhoneNumber asks for the user's phone number, sharing it with the
+// provider when share is true.
+func (b *InvoiceBuilder) NeedPhoneNumber(share bool) *InvoiceBuilder {
+	b.invoice.NeedPhoneNumber = true
+	b.invoice.SendPhoneNumberToProvider = share
+
+	return b
+}
+
+// NeedEmail asks for the user's email address, sharing it with the provider
+// when share is true.
+func (b *InvoiceBuilder) NeedEmail(share bool) *InvoiceBuilder {
+	b.invoice.NeedEmail = true
+	b.invoice.SendEmailToProvider = share
+
+	return b
+}
+
+// NeedShippingAddress asks for the user's shipping address.
+func (b *InvoiceBuilder) NeedShippingAddress() *InvoiceBuilder {
+	b.invoice.NeedShippingAddress = true
+
+	return b
+}
+
+// Flexible makes the final price depend on the shipping method, which the
+// bot offers when it answers the shipping query.
+func (b *InvoiceBuilder) Flexible() *InvoiceBuilder {
+	b.invoice.IsFlexible = true
+
+	return b
+}
+
+// ProviderData sets JSON data shared with the payment provider.
+func (b *InvoiceBuilder) ProviderData(data string) *InvoiceBuilder {
+	b.invoice.ProviderData = data
+
+	return b
+}
+
+// StartParameter makes forwarded copies of the sent invoice open the bot with
+// parameter instead of showing a Pay button.
+func (b *InvoiceBuilder) StartParameter(parameter string) *InvoiceBuilder {
+	b.invoice.StartParameter = parameter
+
+	return b
+}
+
+// Subscription renews the payment every SubscriptionPeriod. It is for Stars
+// invoices created with Payments.CreateInvoiceLink.
+func (b *InvoiceBuilder) Subscription() *InvoiceBuilder {
+	b.invoice.SubscriptionPeriod = SubscriptionPeriod
+
+	return b
+}
+
+// Build validates the invoice and returns it.
+func (b *InvoiceBuilder) Build() (*Invoice, error) {
+	invoice := b.invoice
+	invoice.Prices = slices.Clone(invoice.Prices)
+	invoice.SuggestedTipAmounts = slices.Clone(invoice.SuggestedTipAmounts)
+
+	if err := invoice.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInvoice, err)
+	}
+
+	return &invoice, nil
+}
+
+func (i *Invoice) validate() error {
+	switch {
+	case i.Title == "" || utf8.RuneCountInString(i.Title) > maxTitleLength:
+		return fmt.Errorf("title must have 1-%d characters", maxTitleLength)
+	case i.Description == "" || utf8.RuneCountInString(i.Description) > maxDescriptionLength:
+		return fmt.Errorf("description must have 1-%d characters", maxDescriptionLength)
+	case i.Payload == "" || len(i.Payload) > maxPayloadSize:
+		return fmt.Errorf("payload must have 1-%d bytes", maxPayloadSize)
+	case len(i.Prices) == 0:
+		return fmt.Errorf("no prices")
+	case i.Total() <= 0:
+		return fmt.Errorf("total must be positive")
+	}
+
+	if i.Stars() {
+		return i.validateStars()
+	}
+
+	return i.validateFiat()
+}
+
+func (i *Invoice) validateStars() error {
+	switch {
+	case len(i.Prices) != 1:
+		return fmt.Errorf("stars invoices take exactly one price")
+	case i.MaxTipAmount > 0 || len(i.SuggestedTipAmounts) > 0:
+		return fmt.Errorf("stars invoices take no tips")
+	case i.SubscriptionPeriod != 0 && i.SubscriptionPeriod != SubscriptionPeriod:
+		return fmt.Errorf("subscription period must be %s", SubscriptionPeriod)
+	case i.SubscriptionPeriod != 0 && i.Prices[0].Amount > maxSubscriptionStars:
+		return fmt.Errorf("subscriptions cost at most %d stars", maxSubscriptionStars)
+	}
+
+	return nil
+}
+
+func (i *Invoice) validateFiat() error {
+	switch {
+	case len(i.Currency) != currencyCodeLength:
+		return fmt.Errorf("currency %q is not a three-letter code", i.Currency)
+	case i.SubscriptionPeriod != 0:
+		return fmt.Errorf("only stars invoices can be subscriptions")
+	case len(i.SuggestedTipAmounts) > maxSuggestedTips:
+		return fmt.Errorf("at most %d suggested tips", maxSuggestedTips)
+	}
+
+	previous := 0
+	for _, tip := range i.SuggestedTipAmounts {
+		if tip <= previous || tip > i.MaxTipAmount {
+			return fmt.Errorf("suggested tips must increase and not exceed the maximum tip")
+		}
+
+		previous = tip
+	}
+
+	return nil
+}
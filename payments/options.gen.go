@@ -0,0 +1,91 @@
+// Code generated by options-gen v0.55.3. DO NOT EDIT.
+
+package payments
+
+import (
+	fmt461e464ebed9 "fmt"
+	"time"
+
+	errors461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/errors"
+	validator461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/validator"
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+type OptOptionsSetter func(o *Options)
+
+func NewOptions(
+	client client.ClientWithResponsesInterface,
+	options ...OptOptionsSetter,
+) Options {
+	var o Options
+
+	// Setting defaults from field tag (if present)
+
+	o.answerTimeout, _ = time.ParseDuration("8s")
+	o.errorMessage = "Sorry, the payment could not be processed. Please try again later."
+
+	o.client = client
+
+	for _, opt := range options {
+		opt(&o)
+	}
+	return o
+}
+
+// preCheckout validates pre-checkout queries before they are approved.
+// Without it, every pre-checkout query is approved.
+func WithPreCheckout(opt PreCheckoutValidator) OptOptionsSetter {
+	return func(o *Options) { o.preCheckout = opt }
+}
+
+// shipping prices delivery for shipping queries. Without it, shipping
+// queries are left to the bot's own handlers.
+func WithShipping(opt ShippingQuoter) OptOptionsSetter {
+	return func(o *Options) { o.shipping = opt }
+}
+
+// answerTimeout bounds how long the hooks may run before the query is
+// rejected. Telegram cancels a checkout not answered within 10 seconds.
+func WithAnswerTimeout(opt time.Duration) OptOptionsSetter {
+	return func(o *Options) { o.answerTimeout = opt }
+}
+
+// errorMessage is shown to the user when a hook fails or times out.
+func WithErrorMessage(opt string) OptOptionsSetter {
+	return func(o *Options) { o.errorMessage = opt }
+}
+
+// logger is the logger to use.
+func WithLogger(opt logger.Logger) OptOptionsSetter {
+	return func(o *Options) { o.logger = opt }
+}
+
+func (o *Options) Validate() error {
+	errs := new(errors461e464ebed9.ValidationErrors)
+	errs.Add(errors461e464ebed9.NewValidationError("client", _validate_Options_client(o)))
+	errs.Add(errors461e464ebed9.NewValidationError("answerTimeout", _validate_Options_answerTimeout(o)))
+	errs.Add(errors461e464ebed9.NewValidationError("errorMessage", _validate_Options_errorMessage(o)))
+	return errs.AsError()
+}
+
+func _validate_Options_client(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.client, "required"); err != nil {
+		return fmt461e464ebed9.Errorf("field `client` did not pass the test: %w", err)
+	}
+	return nil
+}
+
+func _validate_Options_answerTimeout(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.answerTimeout, "gt=0"); err != nil {
+		return fmt461e464ebed9.Errorf("field `answerTimeout` did not pass the test: %w", err)
+	}
+	return nil
+}
+
+func _validate_Options_errorMessage(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.errorMessage, "required"); err != nil {
+		return fmt461e464ebed9.Errorf("field `errorMessage` did not pass the test: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package payments
+
+import (
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+//go:generate go tool options-gen -out-filename=options.gen.go -from-struct=Options
+
+// Options is the options for Payments.
+type Options struct {
+	// client is the Telegram API client.
+	client client.ClientWithResponsesInterface `option:"mandatory" validate:"required"`
+	// preCheckout validates pre-checkout queries before they are approved.
+	// Without it, every pre-checkout query is approved.
+	preCheckout PreCheckoutValidator
+	// shipping prices delivery for shipping queries. Without it, shipping
+	// queries are left to the bot's own handlers.
+	shipping ShippingQuoter
+	// answerTimeout bounds how long the hooks may run before the query is
+	// rejected. Telegram cancels a checkout not answered within 10 seconds.
+	answerTimeout time.Duration `default:"8s" validate:"gt=0"`
+	// errorMessage is shown to the user when a hook fails or times out.
+	errorMessage string `default:"Sorry, the payment could not be processed. Please try again later." validate:"required"`
+	// logger is the logger to use.
+	logger logger.Logger
+}
@@ -0,0 +1,425 @@
+// Package payments sends invoices and answers the shipping and pre-checkout
+// queries of the checkout, in fiat currencies or Telegram Stars.
+//
+// Telegram cancels a checkout whose queries are not answered within 10
+// seconds. Payments registered on a handlers.Registry answers them itself,
+// running a ShippingQuoter and a PreCheckoutValidator under a timeout, and
+// rejects the query with an error message for the user when they fail.
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/botapi"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/handlers"
+	"github.com/tgbotkit/runtime/logger"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+// Payments sends invoices and answers payment queries.
+type Payments struct {
+	opts Options
+	log  logger.Logger
+}
+
+// New creates a new Payments with the given options.
+func New(opts Options) (*Payments, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid payments options: %w", err)
+	}
+
+	if opts.logger == nil {
+		opts.logger = logger.NewNop()
+	}
+
+	return &Payments{
+		opts: opts,
+		log:  opts.logger,
+	}, nil
+}
+
+// SendInvoiceOption customizes a sent invoice.
+type SendInvoiceOption func(*client.SendInvoiceJSONRequestBody)
+
+// WithInvoiceReplyMarkup sets the inline keyboard of the invoice. Its first
+// button must be a Pay button.
+func WithInvoiceReplyMarkup(markup *client.InlineKeyboardMarkup) SendInvoiceOption {
+	return func(body *client.SendInvoiceJSONRequestBody) {
+		body.ReplyMarkup = markup
+	}
+}
+
+// WithInvoiceSilent sends the invoice without a notification sound.
+func WithInvoiceSilent() SendInvoiceOption {
+	return func(body *client.SendInvoiceJSONRequestBody) {
+		value := true
+		body.DisableNotification = &value
+	}
+}
+
+// WithInvoiceProtectedContent stops the invoice from being forwarded or saved.
+func WithInvoiceProtectedContent() SendInvoiceOption {
+	return func(body *client.SendInvoiceJSONRequestBody) {
+		value := true
+		body.ProtectContent = &value
+	}
+}
+
+// SendInvoice sends invoice to the target chat. Invoices cannot be sent on
+// behalf of a business account, so the target's business connection is
+// ignored, and subscriptions must be created with CreateInvoiceLink.
+func (p *Payments) SendInvoice(
+	ctx context.Context,
+	target respond.ChatTarget,
+	invoice *Invoice,
+	opts ...SendInvoiceOption,
+) (*client.Message, error) {
+	if invoice == nil {
+		return nil, fmt.Errorf("send invoice: %w", ErrNilInvoice)
+	}
+
+	if invoice.SubscriptionPeriod != 0 {
+		return nil, fmt.Errorf("send invoice: %w: subscriptions need an invoice link", ErrInvalidInvoice)
+	}
+
+	body, err := sendInvoiceBody(target, invoice, opts)
+	if err != nil {
+		return nil, fmt.Errorf("send invoice: %w", err)
+	}
+
+	resp, err := p.opts.client.SendInvoiceWithResponse(ctx, body)
+	if err != nil {
+		return nil, fmt.Errorf("send invoice: %w", err)
+	}
+
+	if resp.JSON200 == nil {
+		if apiErr := botapi.FromResponse(resp.StatusCode(), resp.Body); apiErr != nil {
+			return nil, fmt.Errorf("send invoice: %w", apiErr)
+		}
+	}
+
+	if resp.JSON200 == nil || !bool(resp.JSON200.Ok) {
+		return nil, fmt.Errorf("send invoice: unexpected response: %s", resp.Status())
+	}
+
+	return &resp.JSON200.Result, nil
+}
+
+// CreateInvoiceLink returns a link to pay invoice, which can be shared
+// anywhere or opened from a Mini App.
+func (p *Payments) CreateInvoiceLink(ctx context.Context, invoice *Invoice) (string, error) {
+	if invoice == nil {
+		return "", fmt.Errorf("create invoice link: %w", ErrNilInvoice)
+	}
+
+	body, err := invoiceBody[client.CreateInvoiceLinkJSONRequestBody](invoice)
+	if err != nil {
+		return "", fmt.Errorf("create invoice link: %w", err)
+	}
+
+	body.SubscriptionPeriod = positive(int(invoice.SubscriptionPeriod.Seconds()))
+
+	resp, err := p.opts.client.CreateInvoiceLinkWithResponse(ctx, body)
+	if err != nil {
+		return "", fmt.Errorf("create invoice link: %w", err)
+	}
+
+	if resp.JSON200 == nil {
+		if apiErr := botapi.FromResponse(resp.StatusCode(), resp.Body); apiErr != nil {
+			return "", fmt.Errorf("create invoice link: %w", apiErr)
+		}
+	}
+
+	if resp.JSON200 == nil || !bool(resp.JSON200.Ok) {
+		return "", fmt.Errorf("create invoice link: unexpected response: %s", resp.Status())
+	}
+
+	return resp.JSON200.Result, nil
+}
+
+// AnswerShippingQuery accepts the shipping address of query, offering options
+// to choose from.
+func (p *Payments) AnswerShippingQuery(
+	ctx context.Context,
+	query *client.ShippingQuery,
+	options ...client.ShippingOption,
+) error {
+	if len(options) == 0 {
+		return fmt.Errorf("answer shipping query: %w", ErrNoShippingOptions)
+	}
+
+	return p.answerShippingQuery(ctx, query, client.AnswerShippingQueryJSONRequestBody{
+		Ok:              true,
+		ShippingOptions: &options,
+	})
+}
+
+// RejectShippingQuery rejects the shipping address of query, showing message
+// to the user.
+func (p *Payments) RejectShippingQuery(ctx context.Context, query *client.ShippingQuery, message string) error {
+	return p.answerShippingQuery(ctx, query, client.AnswerShippingQueryJSONRequestBody{
+		ErrorMessage: &message,
+	})
+}
+
+// AnswerPreCheckoutQuery approves the checkout of query. The user is charged
+// once it is answered.
+func (p *Payments) AnswerPreCheckoutQuery(ctx context.Context, query *client.PreCheckoutQuery) error {
+	return p.answerPreCheckoutQuery(ctx, query, client.AnswerPreCheckoutQueryJSONRequestBody{Ok: true})
+}
+
+// RejectPreCheckoutQuery cancels the checkout of query, showing message to
+// the user.
+func (p *Payments) RejectPreCheckoutQuery(ctx context.Context, query *client.PreCheckoutQuery, message string) error {
+	return p.answerPreCheckoutQuery(ctx, query, client.AnswerPreCheckoutQueryJSONRequestBody{
+		ErrorMessage: &message,
+	})
+}
+
+// Register makes Payments answer the payment queries received by registry:
+// pre-checkout queries always, and shipping queries when a ShippingQuoter is
+// set. It returns a function that unregisters the handlers.
+func (p *Payments) Register(registry *handlers.Registry) eventemitter.UnsubscribeFunc {
+	unsubscribe := []eventemitter.UnsubscribeFunc{
+		registry.OnPreCheckoutQuery(p.handlePreCheckoutQuery),
+	}
+
+	if p.opts.shipping != nil {
+		unsubscribe = append(unsubscribe, registry.OnShippingQuery(p.handleShippingQuery))
+	}
+
+	return func() {
+		for _, fn := range unsubscribe {
+			fn()
+		}
+	}
+}
+
+func (p *Payments) handlePreCheckoutQuery(ctx context.Context, event *events.PreCheckoutQueryEvent) error {
+	query := event.PreCheckoutQuery
+	if query == nil || p.opts.preCheckout == nil {
+		return p.AnswerPreCheckoutQuery(ctx, query)
+	}
+
+	_, err := runHook(ctx, p.opts.answerTimeout, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, p.opts.preCheckout.ValidatePreCheckout(ctx, query)
+	})
+	if err == nil {
+		return p.AnswerPreCheckoutQuery(ctx, query)
+	}
+
+	message, err := p.rejection(err)
+	if err != nil {
+		p.log.Errorf("validate pre-checkout query %s: %v", query.Id, err)
+	}
+
+	return p.RejectPreCheckoutQuery(ctx, query, message)
+}
+
+func (p *Payments) handleShippingQuery(ctx context.Context, event *events.ShippingQueryEvent) error {
+	query := event.ShippingQuery
+	if query == nil {
+		return fmt.Errorf("answer shipping query: %w", ErrNilQuery)
+	}
+
+	options, err := runHook(ctx, p.opts.answerTimeout, func(ctx context.Context) ([]client.ShippingOption, error) {
+		return p.opts.shipping.ShippingOptions(ctx, query)
+	})
+	if err == nil && len(options) == 0 {
+		err = ErrNoShippingOptions
+	}
+
+	if err == nil {
+		return p.AnswerShippingQuery(ctx, query, options...)
+	}
+
+	message, err := p.rejection(err)
+	if err != nil {
+		p.log.Errorf("quote shipping query %s: %v", query.Id, err)
+	}
+
+	return p.RejectShippingQuery(ctx, query, message)
+}
+
+func (p *Payments) answerShippingQuery(
+	ctx context.Context,
+	query *client.ShippingQuery,
+	body client.AnswerShippingQueryJSONRequestBody,
+) error {
+	if query == nil {
+		return fmt.Errorf("answer shipping query: %w", ErrNilQuery)
+	}
+
+	body.ShippingQueryId = query.Id
+
+	resp, err := p.opts.client.AnswerShippingQueryWithResponse(ctx, body)
+	if err != nil {
+		return fmt.Errorf("answer shipping query: %w", err)
+	}
+
+	if resp.JSON200 == nil {
+		if apiErr := botapi.FromResponse(resp.StatusCode(), resp.Body); apiErr != nil {
+			return fmt.Errorf("answer shipping query: %w", apiErr)
+		}
+	}
+
+	if resp.JSON200 == nil || !bool(resp.JSON200.Ok) || !resp.JSON200.Result {
+		return fmt.Errorf("answer shipping query: unexpected response: %s", resp.Status())
+	}
+
+	return nil
+}
+
+func (p *Payments) answerPreCheckoutQuery(
+	ctx context.Context,
+	query *client.PreCheckoutQuery,
+	body client.AnswerPreCheckoutQueryJSONRequestBody,
+) error {
+	if query == nil {
+		return fmt.Errorf("answer pre-checkout query: %w", ErrNilQuery)
+	}
+
+	body.PreCheckoutQueryId = query.Id
+
+	resp, err := p.opts.client.AnswerPreCheckoutQueryWithResponse(ctx, body)
+	if err != nil {
+		return fmt.Errorf("answer pre-checkout query: %w", err)
+	}
+
+	if resp.JSON200 == nil {
+		if apiErr := botapi.FromResponse(resp.StatusCode(), resp.Body); apiErr != nil {
+			return fmt.Errorf("answer pre-checkout query: %w", apiErr)
+		}
+	}
+
+	if resp.JSON200 == nil || !bool(resp.JSON200.Ok) || !resp.JSON200.Result {
+		return fmt.Errorf("answer pre-checkout query: unexpected response: %s", resp.Status())
+	}
+
+	return nil
+}
+
+func sendInvoiceBody(
+	target respond.ChatTarget,
+	invoice *Invoice,
+	opts []SendInvoiceOption,
+) (client.SendInvoiceJSONRequestBody, error) {
+	body, err := invoiceBody[client.SendInvoiceJSONRequestBody](invoice)
+	if err != nil {
+		return body, err
+	}
+
+	body.ChatId = target.ChatID
+	body.MessageThreadId = target.MessageThreadID
+	body.DirectMessagesTopicId = target.DirectMessagesTopicID
+	body.StartParameter = nonZero(invoice.StartParameter)
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&body)
+		}
+	}
+
+	return body, nil
+}
+
+// invoiceParams holds the parameters of an invoice that sendInvoice and
+// createInvoiceLink share, named as by the Bot API.
+type invoiceParams struct {
+	Title                     string                `json:"title"`
+	Description               string                `json:"description"`
+	Payload                   string                `json:"payload"`
+	Currency                  string                `json:"currency"`
+	Prices                    []client.LabeledPrice `json:"prices"`
+	ProviderToken             string                `json:"provider_token"`
+	MaxTipAmount              *int                  `json:"max_tip_amount,omitempty"`
+	SuggestedTipAmounts       *[]int                `json:"suggested_tip_amounts,omitempty"`
+	ProviderData              *string               `json:"provider_data,omitempty"`
+	PhotoURL                  *string               `json:"photo_url,omitempty"`
+	PhotoWidth                *int                  `json:"photo_width,omitempty"`
+	PhotoHeight               *int                  `json:"photo_height,omitempty"`
+	NeedName                  *bool                 `json:"need_name,omitempty"`
+	NeedPhoneNumber           *bool                 `json:"need_phone_number,omitempty"`
+	NeedEmail                 *bool                 `json:"need_email,omitempty"`
+	NeedShippingAddress       *bool                 `json:"need_shipping_address,omitempty"`
+	SendPhoneNumberToProvider *bool                 `json:"send_phone_number_to_provider,omitempty"`
+	SendEmailToProvider       *bool                 `json:"send_email_to_provider,omitempty"`
+	IsFlexible                *bool                 `json:"is_flexible,omitempty"`
+}
+
+// invoiceBody returns the request body of either method with the shared
+// parameters of invoice set. The two generated bodies are distinct types, so
+// the parameters are copied through their JSON names; callers set the rest.
+func invoiceBody[T client.SendInvoiceJSONRequestBody | client.CreateInvoiceLinkJSONRequestBody](
+	invoice *Invoice,
+) (T, error) {
+	var body T
+
+	data, err := json.Marshal(invoiceParams{
+		Title:                     invoice.Title,
+		Description:               invoice.Description,
+		Payload:                   invoice.Payload,
+		Currency:                  invoice.Currency,
+		Prices:                    invoice.Prices,
+		ProviderToken:             invoice.ProviderToken,
+		MaxTipAmount:              positive(invoice.MaxTipAmount),
+		SuggestedTipAmounts:       nonEmpty(invoice.SuggestedTipAmounts),
+		ProviderData:              nonZero(invoice.ProviderData),
+		PhotoURL:                  nonZero(invoice.PhotoURL),
+		PhotoWidth:                positive(invoice.PhotoWidth),
+		PhotoHeight:               positive(invoice.PhotoHeight),
+		NeedName:                  flag(invoice.NeedName),
+		NeedPhoneNumber:           flag(invoice.NeedPhoneNumber),
+		NeedEmail:                 flag(invoice.NeedEmail),
+		NeedShippingAddress:       flag(invoice.NeedShippingAddress),
+		SendPhoneNumberToProvider: flag(invoice.SendPhoneNumberToProvider),
+		SendEmailToProvider:       flag(invoice.SendEmailToProvider),
+		IsFlexible:                flag(invoice.IsFlexible),
+	})
+	if err != nil {
+		return body, fmt.Errorf("encode invoice: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &body); err != nil {
+		return body, fmt.Errorf("encode invoice: %w", err)
+	}
+
+	return body, nil
+}
+
+func flag(value bool) *bool {
+	if !value {
+		return nil
+	}
+
+	return &value
+}
+
+func positive(value int) *int {
+	if value <= 0 {
+		return nil
+	}
+
+	return &value
+}
+
+func nonZero(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+func nonEmpty(values []int) *[]int {
+	if len(values) == 0 {
+		return nil
+	}
+
+	return &values
+}
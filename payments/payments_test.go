@@ -0,0 +1,344 @@
+package payments_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/handlers"
+	"github.com/tgbotkit/runtime/logger"
+	"github.com/tgbotkit/runtime/payments"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+type mockClient struct {
+	client.ClientWithResponsesInterface
+
+	mu          sync.Mutex
+	invoices    []client.SendInvoiceJSONRequestBody
+	links       []client.CreateInvoiceLinkJSONRequestBody
+	preCheckout []client.AnswerPreCheckoutQueryJSONRequestBody
+	shipping    []client.AnswerShippingQueryJSONRequestBody
+}
+
+func (m *mockClient) SendInvoiceWithResponse(
+	_ context.Context,
+	body client.SendInvoiceJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.SendInvoiceResponse, error) {
+	m.mu.Lock()
+	m.invoices = append(m.invoices, body)
+	m.mu.Unlock()
+
+	resp := &client.SendInvoiceResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}}
+	resp.JSON200 = &struct {
+		Ok     client.SendInvoice200Ok `json:"ok"`
+		Result client.Message          `json:"result"`
+	}{Ok: true, Result: client.Message{MessageId: 1, Chat: client.Chat{Id: body.ChatId}}}
+
+	return resp, nil
+}
+
+func (m *mockClient) CreateInvoiceLinkWithResponse(
+	_ context.Context,
+	body client.CreateInvoiceLinkJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.CreateInvoiceLinkResponse, error) {
+	m.mu.Lock()
+	m.links = append(m.links, body)
+	m.mu.Unlock()
+
+	resp := &client.CreateInvoiceLinkResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}}
+	resp.JSON200 = &struct {
+		Ok     client.CreateInvoiceLink200Ok `json:"ok"`
+		Result string                        `json:"result"`
+	}{Ok: true, Result: "https://t.me/$invoice"}
+
+	return resp, nil
+}
+
+func (m *mockClient) AnswerPreCheckoutQueryWithResponse(
+	_ context.Context,
+	body client.AnswerPreCheckoutQueryJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.AnswerPreCheckoutQueryResponse, error) {
+	m.mu.Lock()
+	m.preCheckout = append(m.preCheckout, body)
+	m.mu.Unlock()
+
+	resp := &client.AnswerPreCheckoutQueryResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}}
+	resp.JSON200 = &struct {
+		Ok     client.AnswerPreCheckoutQuery200Ok `json:"ok"`
+		Result bool                               `json:"result"`
+	}{Ok: true, Result: true}
+
+	return resp, nil
+}
+
+func (m *mockClient) AnswerShippingQueryWithResponse(
+	_ context.Context,
+	body client.AnswerShippingQueryJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.AnswerShippingQueryResponse, error) {
+	m.mu.Lock()
+	m.shipping = append(m.shipping, body)
+	m.mu.Unlock()
+
+	resp := &client.AnswerShippingQueryResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}}
+	resp.JSON200 = &struct {
+		Ok     client.AnswerShippingQuery200Ok `json:"ok"`
+		Result bool                            `json:"result"`
+	}{Ok: true, Result: true}
+
+	return resp, nil
+}
+
+func newRegistry(t *testing.T) (eventemitter.EventEmitter, *handlers.Registry) {
+	t.Helper()
+
+	ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+	if err != nil {
+		t.Fatalf("NewSync() unexpected error: %v", err)
+	}
+
+	return ee, handlers.NewRegistry(ee, logger.NewNop())
+}
+
+func TestInvoiceBuilderValidates(t *testing.T) {
+	t.Parallel()
+
+	invoice, err := payments.FiatInvoice("token", "USD", "T-shirt", "A black T-shirt", "order-1").
+		Price("T-shirt", 1500).
+		Price("Discount", -200).
+		Tips(500, 100, 300).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+	if invoice.Total() != 1300 {
+		t.Fatalf("Total()=%d, want 1300", invoice.Total())
+	}
+
+	for name, builder := range map[string]*payments.InvoiceBuilder{
+		"stars with tips":       payments.StarsInvoice("Pro", "Pro plan", "pro", 50).Tips(10, 5),
+		"stars with two prices": payments.StarsInvoice("Pro", "Pro plan", "pro", 50).Price("Extra", 10),
+		"fiat without prices":   payments.FiatInvoice("token", "USD", "T-shirt", "A T-shirt", "order-1"),
+		"fiat subscription": payments.FiatInvoice("token", "USD", "Pro", "Pro plan", "pro").
+			Price("Pro", 500).Subscription(),
+		"tips out of order": payments.FiatInvoice("token", "USD", "T-shirt", "A T-shirt", "order-1").
+			Price("T-shirt", 1500).Tips(500, 300, 100),
+		"long title": payments.StarsInvoice("A title longer than thirty-two characters", "Pro plan", "pro", 50),
+	} {
+		if _, err := builder.Build(); !errors.Is(err, payments.ErrInvalidInvoice) {
+			t.Errorf("%s: Build() error=%v, want ErrInvalidInvoice", name, err)
+		}
+	}
+}
+
+func TestSendInvoice(t *testing.T) {
+	t.Parallel()
+
+	api := &mockClient{}
+
+	p, err := payments.New(payments.NewOptions(api))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	invoice, err := payments.StarsInvoice("Pro", "Pro plan for a month", "pro", 50).Build()
+	if err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+
+	if _, err := p.SendInvoice(context.Background(), respond.ChatTarget{ChatID: 7}, invoice,
+		payments.WithInvoiceSilent()); err != nil {
+		t.Fatalf("SendInvoice() unexpected error: %v", err)
+	}
+
+	body := api.invoices[0]
+	if body.ChatId != 7 || body.Currency != payments.CurrencyStars || body.ProviderToken == nil ||
+		*body.ProviderToken != "" || len(body.Prices) != 1 || body.Prices[0].Amount != 50 {
+		t.Fatalf("body=%+v, want a 50 stars invoice with an empty provider token", body)
+	}
+	if body.DisableNotification == nil || !*body.DisableNotification || body.NeedName != nil {
+		t.Fatalf("body=%+v, want a silent invoice without contact details", body)
+	}
+
+	subscription, err := payments.StarsInvoice("Pro", "Pro plan", "pro", 50).Subscription().Build()
+	if err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+
+	if _, err := p.SendInvoice(context.Background(), respond.ChatTarget{ChatID: 7}, subscription); !errors.Is(
+		err, payments.ErrInvalidInvoice) {
+		t.Fatalf("SendInvoice(subscription) error=%v, want ErrInvalidInvoice", err)
+	}
+}
+
+func TestInvoiceLinkSharesInvoiceFields(t *testing.T) {
+	t.Parallel()
+
+	api := &mockClient{}
+
+	p, err := payments.New(payments.NewOptions(api))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	invoice := &payments.Invoice{
+		Title:          "Box",
+		Description:    "A box",
+		Payload:        "box",
+		Currency:       "EUR",
+		ProviderToken:  "token",
+		Prices:         []client.LabeledPrice{{Label: "Box", Amount: 500}},
+		MaxTipAmount:   100,
+		PhotoURL:       "https://example.com/box.png",
+		NeedEmail:      true,
+		IsFlexible:     true,
+		StartParameter: "box",
+	}
+
+	if _, err := p.SendInvoice(context.Background(), respond.ChatTarget{ChatID: 7}, invoice); err != nil {
+		t.Fatalf("SendInvoice() unexpected error: %v", err)
+	}
+
+	if _, err := p.CreateInvoiceLink(context.Background(), invoice); err != nil {
+		t.Fatalf("CreateInvoiceLink() unexpected error: %v", err)
+	}
+
+	sent, link := api.invoices[0], api.links[0]
+	if sent.ChatId != 7 || sent.StartParameter == nil || *sent.StartParameter != "box" {
+		t.Fatalf("sent=%+v, want the invoice sent to chat 7 with its start parameter", sent)
+	}
+
+	for _, body := range []struct {
+		title, token, photo string
+		tip                 *int
+		email, flexible     *bool
+	}{
+		{sent.Title, *sent.ProviderToken, *sent.PhotoUrl, sent.MaxTipAmount, sent.NeedEmail, sent.IsFlexible},
+		{link.Title, *link.ProviderToken, *link.PhotoUrl, link.MaxTipAmount, link.NeedEmail, link.IsFlexible},
+	} {
+		if body.title != "Box" || body.token != "token" || body.photo != invoice.PhotoURL ||
+			body.tip == nil || *body.tip != 100 || body.email == nil || body.flexible == nil {
+			t.Fatalf("body=%+v, want the shared invoice fields", body)
+		}
+	}
+}
+
+func TestRegisterAnswersPreCheckoutQueries(t *testing.T) {
+	t.Parallel()
+
+	api := &mockClient{}
+	ee, registry := newRegistry(t)
+	release := make(chan struct{})
+
+	defer close(release)
+
+	p, err := payments.New(payments.NewOptions(api,
+		payments.WithAnswerTimeout(20*time.Millisecond),
+		payments.WithErrorMessage("try later"),
+		payments.WithPreCheckout(payments.PreCheckoutValidatorFunc(
+			func(_ context.Context, query *client.PreCheckoutQuery) error {
+				switch query.InvoicePayload {
+				case "sold-out":
+					return payments.Reject("sold out")
+				case "broken":
+					return errors.New("database is down")
+				case "slow":
+					<-release
+
+					return nil
+				default:
+					return nil
+				}
+			},
+		)),
+	))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	unregister := p.Register(registry)
+
+	for _, payload := range []string{"ok", "sold-out", "broken", "slow"} {
+		ee.Emit(context.Background(), events.OnPreCheckoutQuery, &events.PreCheckoutQueryEvent{
+			PreCheckoutQuery: &client.PreCheckoutQuery{Id: payload, InvoicePayload: payload},
+		})
+	}
+
+	unregister()
+	ee.Emit(context.Background(), events.OnPreCheckoutQuery, &events.PreCheckoutQueryEvent{
+		PreCheckoutQuery: &client.PreCheckoutQuery{Id: "late", InvoicePayload: "ok"},
+	})
+
+	want := map[string]string{"ok": "", "sold-out": "sold out", "broken": "try later", "slow": "try later"}
+	if len(api.preCheckout) != len(want) {
+		t.Fatalf("answers=%d, want %d", len(api.preCheckout), len(want))
+	}
+
+	for _, answer := range api.preCheckout {
+		message := ""
+		if answer.ErrorMessage != nil {
+			message = *answer.ErrorMessage
+		}
+
+		if answer.Ok != (want[answer.PreCheckoutQueryId] == "") || message != want[answer.PreCheckoutQueryId] {
+			t.Errorf("answer %s: ok=%v message=%q, want %q", answer.PreCheckoutQueryId, answer.Ok, message,
+				want[answer.PreCheckoutQueryId])
+		}
+	}
+}
+
+func TestRegisterAnswersShippingQueries(t *testing.T) {
+	t.Parallel()
+
+	api := &mockClient{}
+	ee, registry := newRegistry(t)
+
+	p, err := payments.New(payments.NewOptions(api,
+		payments.WithShipping(payments.ShippingQuoterFunc(
+			func(_ context.Context, query *client.ShippingQuery) ([]client.ShippingOption, error) {
+				if query.ShippingAddress.CountryCode != "DE" {
+					return nil, payments.Reject("We only deliver to Germany")
+				}
+
+				return []client.ShippingOption{{
+					Id:     "dhl",
+					Title:  "DHL",
+					Prices: []client.LabeledPrice{{Label: "Delivery", Amount: 500}},
+				}}, nil
+			},
+		)),
+	))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	p.Register(registry)
+
+	for _, country := range []string{"DE", "FR"} {
+		ee.Emit(context.Background(), events.OnShippingQuery, &events.ShippingQueryEvent{
+			ShippingQuery: &client.ShippingQuery{Id: country, ShippingAddress: client.ShippingAddress{CountryCode: country}},
+		})
+	}
+
+	if len(api.shipping) != 2 {
+		t.Fatalf("answers=%d, want 2", len(api.shipping))
+	}
+
+	accepted, rejected := api.shipping[0], api.shipping[1]
+	if !accepted.Ok || accepted.ShippingOptions == nil || (*accepted.ShippingOptions)[0].Id != "dhl" {
+		t.Fatalf("answer=%+v, want DHL offered", accepted)
+	}
+	if rejected.Ok || rejected.ErrorMessage == nil || *rejected.ErrorMessage != "We only deliver to Germany" {
+		t.Fatalf("answer=%+v, want rejection", rejected)
+	}
+}
@@ -0,0 +1,52 @@
+// Code generated by options-gen v0.55.3. DO NOT EDIT.
+
+package polls
+
+import (
+	fmt461e464ebed9 "fmt"
+
+	errors461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/errors"
+	validator461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/validator"
+	"github.com/tgbotkit/runtime/eventemitter"
+)
+
+type OptOptionsSetter func(o *Options)
+
+func NewOptions(
+	store Store,
+	emitter eventemitter.EventEmitter,
+	options ...OptOptionsSetter,
+) Options {
+	var o Options
+
+	// Setting defaults from field tag (if present)
+
+	o.store = store
+	o.emitter = emitter
+
+	for _, opt := range options {
+		opt(&o)
+	}
+	return o
+}
+
+func (o *Options) Validate() error {
+	errs := new(errors461e464ebed9.ValidationErrors)
+	errs.Add(errors461e464ebed9.NewValidationError("store", _validate_Options_store(o)))
+	errs.Add(errors461e464ebed9.NewValidationError("emitter", _validate_Options_emitter(o)))
+	return errs.AsError()
+}
+
+func _validate_Options_store(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.store, "required"); err != nil {
+		return fmt461e464ebed9.Errorf("field `store` did not pass the test: %w", err)
+	}
+	return nil
+}
+
+func _validate_Options_emitter(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.emitter, "required"); err != nil {
+		return fmt461e464ebed9.Errorf("field `emitter` did not pass the test: %w", err)
+	}
+	return nil
+}
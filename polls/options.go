@@ -0,0 +1,13 @@
+package polls
+
+import "github.com/tgbotkit/runtime/eventemitter"
+
+//go:generate go tool options-gen -out-filename=options.gen.go -from-struct=Options
+
+// Options is the options for the Tracker.
+type Options struct {
+	// store persists the results of the tracked polls.
+	store Store `option:"mandatory" validate:"required"`
+	// emitter receives the OnPollClosed events.
+	emitter eventemitter.EventEmitter `option:"mandatory" validate:"required"`
+}
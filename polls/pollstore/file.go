@@ -0,0 +1,116 @@
+package pollstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tgbotkit/runtime/polls"
+)
+
+// FileStore is a polls.Store that persists the results of all polls to a JSON
+// file, so that they are still tracked after a restart.
+type FileStore struct {
+	mu      sync.Mutex
+	path    string
+	results map[string]polls.Results
+}
+
+var _ polls.Store = (*FileStore)(nil)
+
+// NewFileStore creates a new FileStore backed by path, loading any previously
+// persisted results.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		path:    path,
+		results: make(map[string]polls.Results),
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Load returns the results of the poll with pollID and whether they exist.
+func (s *FileStore) Load(_ context.Context, pollID string) (polls.Results, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results, ok := s.results[pollID]
+
+	return results.Clone(), ok, nil
+}
+
+// Save records results, replacing those of the same poll. They are written to
+// disk before Save returns.
+func (s *FileStore) Save(_ context.Context, results polls.Results) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.results[results.Poll.Id] = results.Clone()
+
+	return s.persist()
+}
+
+func (s *FileStore) load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("read poll store: %w", err)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &s.results); err != nil {
+		return fmt.Errorf("decode poll store: %w", err)
+	}
+
+	return nil
+}
+
+// persist atomically replaces the store file with the current results.
+func (s *FileStore) persist() error {
+	data, err := json.Marshal(s.results)
+	if err != nil {
+		return fmt.Errorf("encode poll store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create poll store temp file: %w", err)
+	}
+
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+
+		return fmt.Errorf("write poll store: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+
+		return fmt.Errorf("close poll store: %w", err)
+	}
+
+	if err := os.Rename(tmpName, s.path); err != nil {
+		_ = os.Remove(tmpName)
+
+		return fmt.Errorf("replace poll store: %w", err)
+	}
+
+	return nil
+}
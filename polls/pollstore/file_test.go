@@ -0,0 +1,55 @@
+package pollstore
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/polls"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "polls.json")
+	ctx := context.Background()
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	results := polls.Results{
+		ChatID:    7,
+		MessageID: 5,
+		Poll: client.Poll{
+			Id:      "p1",
+			Options: []client.PollOption{{Text: "Pizza", VoterCount: 2}, {Text: "Sushi"}},
+		},
+		Votes: map[int64][]int{1: {0}, 2: {0}},
+	}
+	if err := store.Save(ctx, results); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Changing the saved results does not change the stored ones.
+	results.Votes[3] = []int{1}
+
+	// A new store over the same file keeps tracking the poll.
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore() reopen error = %v", err)
+	}
+
+	got, found, err := reopened.Load(ctx, "p1")
+	if err != nil || !found {
+		t.Fatalf("Load() found = %v, error = %v", found, err)
+	}
+	if got.ChatID != 7 || got.MessageID != 5 || len(got.Votes) != 2 || !slices.Equal(got.Tallies(), []int{2, 0}) {
+		t.Errorf("Load() got = %+v, want the saved results", got)
+	}
+
+	if _, found, _ := reopened.Load(ctx, "other"); found {
+		t.Error("Load() found an unknown poll")
+	}
+}
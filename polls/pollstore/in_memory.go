@@ -0,0 +1,43 @@
+// Package pollstore provides polls.Store implementations.
+package pollstore
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tgbotkit/runtime/polls"
+)
+
+// InMemoryStore keeps the results of polls in memory. They are lost on
+// restart, after which answers to earlier polls are ignored.
+type InMemoryStore struct {
+	mu      sync.RWMutex
+	results map[string]polls.Results
+}
+
+var _ polls.Store = (*InMemoryStore)(nil)
+
+// NewInMemoryStore creates a new InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{results: make(map[string]polls.Results)}
+}
+
+// Load returns the results of the poll with pollID and whether they exist.
+func (s *InMemoryStore) Load(_ context.Context, pollID string) (polls.Results, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results, ok := s.results[pollID]
+
+	return results.Clone(), ok, nil
+}
+
+// Save records results, replacing those of the same poll.
+func (s *InMemoryStore) Save(_ context.Context, results polls.Results) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.results[results.Poll.Id] = results.Clone()
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package polls
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/tgbotkit/client"
+)
+
+// Results are the results of a poll the bot sent.
+type Results struct {
+	// ChatID and MessageID identify the message of the poll.
+	ChatID    int64 `json:"chat_id"`
+	MessageID int   `json:"message_id"`
+	// Poll is the latest state of the poll. The voter count of each option is
+	// its tally.
+	Poll client.Poll `json:"poll"`
+	// Votes maps each voter, a user or a chat voting on behalf of a channel,
+	// to the options it chose. It is empty for anonymous polls.
+	Votes map[int64][]int `json:"votes,omitempty"`
+}
+
+// Tallies returns the number of votes of each option.
+func (r Results) Tallies() []int {
+	tallies := make([]int, len(r.Poll.Options))
+	for i, option := range r.Poll.Options {
+		tallies[i] = option.VoterCount
+	}
+
+	return tallies
+}
+
+// Leaders returns the options with the most votes, or none when nobody voted.
+func (r Results) Leaders() []int {
+	var (
+		leaders []int
+		most    int
+	)
+
+	for i, option := range r.Poll.Options {
+		switch {
+		case option.VoterCount > most:
+			leaders = []int{i}
+			most = option.VoterCount
+		case option.VoterCount == most && most > 0:
+			leaders = append(leaders, i)
+		}
+	}
+
+	return leaders
+}
+
+// Clone returns a deep copy of the results that can be modified without
+// affecting r. Stores use it to keep what they hold apart from their callers.
+func (r Results) Clone() Results {
+	r.Poll.Options = slices.Clone(r.Poll.Options)
+
+	if r.Poll.CorrectOptionIds != nil {
+		ids := slices.Clone(*r.Poll.CorrectOptionIds)
+		r.Poll.CorrectOptionIds = &ids
+	}
+
+	if r.Votes != nil {
+		votes := maps.Clone(r.Votes)
+		for voter, options := range votes {
+			votes[voter] = slices.Clone(options)
+		}
+
+		r.Votes = votes
+	}
+
+	return r
+}
+
+// Store persists the results of the polls a Tracker follows, keyed by poll
+// ID. The Tracker serializes its writes.
+type Store interface {
+	// Load returns the results of the poll with pollID and whether they exist.
+	Load(ctx context.Context, pollID string) (Results, bool, error)
+	// Save records results, replacing those of the same poll.
+	Save(ctx context.Context, results Results) error
+}
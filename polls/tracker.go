@@ -0,0 +1,216 @@
+// Package polls follows the answers to the polls and quizzes the bot sends.
+//
+// A Tracker is told about each poll the bot sent with Track. Registered on a
+// handlers.Registry, it correlates the poll and poll answer updates with the
+// tracked polls, keeps their tallies and, for polls that are not anonymous,
+// who voted for what. When a tracked poll is closed, it emits
+// events.OnPollClosed with the final results. Results are kept in a Store, so
+// polls remain tracked across restarts.
+package polls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/handlers"
+)
+
+// ErrNotPoll is returned by Track for a message without a poll.
+var ErrNotPoll = errors.New("message has no poll")
+
+// Tracker follows the answers to the polls the bot sent.
+type Tracker struct {
+	opts Options
+
+	// mu serializes the read-modify-write cycles on the store.
+	mu sync.Mutex
+}
+
+// New creates a new Tracker with the given options.
+func New(opts Options) (*Tracker, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid polls options: %w", err)
+	}
+
+	return &Tracker{opts: opts}, nil
+}
+
+// Register makes the tracker follow the poll and poll answer updates received
+// by registry. It returns a function that unregisters the handlers.
+func (t *Tracker) Register(registry *handlers.Registry) eventemitter.UnsubscribeFunc {
+	unsubscribe := []eventemitter.UnsubscribeFunc{
+		registry.OnPoll(t.handlePoll),
+		registry.OnPollAnswer(t.handlePollAnswer),
+	}
+
+	return func() {
+		for _, fn := range unsubscribe {
+			fn()
+		}
+	}
+}
+
+// Track starts following the poll of message, as returned by
+// respond.Responder.SendPoll or SendQuiz. Tracking a poll again keeps the
+// results collected so far.
+func (t *Tracker) Track(ctx context.Context, message *client.Message) error {
+	if message == nil || message.Poll == nil {
+		return fmt.Errorf("track poll: %w", ErrNotPoll)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, found, err := t.load(ctx, message.Poll.Id)
+	if err != nil || found {
+		return err
+	}
+
+	return t.save(ctx, Results{
+		ChatID:    message.Chat.Id,
+		MessageID: message.MessageId,
+		Poll:      *message.Poll,
+	})
+}
+
+// Results returns the results of the tracked poll with pollID and whether it
+// is tracked.
+func (t *Tracker) Results(ctx context.Context, pollID string) (Results, bool, error) {
+	return t.load(ctx, pollID)
+}
+
+func (t *Tracker) handlePoll(ctx context.Context, event *events.PollEvent) error {
+	if event.Poll == nil {
+		return nil
+	}
+
+	results, closed, err := t.update(ctx, event.Poll)
+	if err != nil || !closed {
+		return err
+	}
+
+	t.opts.emitter.Emit(ctx, events.OnPollClosed, &events.PollClosedEvent{
+		Poll:      &results.Poll,
+		ChatID:    results.ChatID,
+		MessageID: results.MessageID,
+		Votes:     results.Votes,
+	})
+
+	return nil
+}
+
+// update records the latest state of a tracked poll and reports whether it
+// has just been closed.
+func (t *Tracker) update(ctx context.Context, poll *client.Poll) (Results, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	results, found, err := t.load(ctx, poll.Id)
+	if err != nil || !found || results.Poll.IsClosed {
+		return Results{}, false, err
+	}
+
+	results.Poll = *poll
+
+	if err := t.save(ctx, results); err != nil {
+		return Results{}, false, err
+	}
+
+	return results, poll.IsClosed, nil
+}
+
+func (t *Tracker) handlePollAnswer(ctx context.Context, event *events.PollAnswerEvent) error {
+	answer := event.PollAnswer
+	if answer == nil {
+		return nil
+	}
+
+	voter, ok := voterID(answer)
+	if !ok {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	results, found, err := t.load(ctx, answer.PollId)
+	if err != nil || !found || results.Poll.IsClosed {
+		return err
+	}
+
+	results.vote(voter, answer.OptionIds)
+
+	return t.save(ctx, results)
+}
+
+// vote replaces the options voter chose with options, keeping the tallies in
+// step until the next poll update reports them. Empty options retract the
+// vote.
+func (r *Results) vote(voter int64, options []int) {
+	previous, voted := r.Votes[voter]
+
+	r.count(previous, -1)
+	r.count(options, 1)
+
+	if len(options) == 0 {
+		if voted {
+			delete(r.Votes, voter)
+			r.Poll.TotalVoterCount--
+		}
+
+		return
+	}
+
+	if r.Votes == nil {
+		r.Votes = make(map[int64][]int)
+	}
+
+	r.Votes[voter] = slices.Clone(options)
+
+	if !voted {
+		r.Poll.TotalVoterCount++
+	}
+}
+
+func (r *Results) count(options []int, delta int) {
+	for _, option := range options {
+		if option >= 0 && option < len(r.Poll.Options) {
+			r.Poll.Options[option].VoterCount += delta
+		}
+	}
+}
+
+func (t *Tracker) load(ctx context.Context, pollID string) (Results, bool, error) {
+	results, found, err := t.opts.store.Load(ctx, pollID)
+	if err != nil {
+		return Results{}, false, fmt.Errorf("load poll %s: %w", pollID, err)
+	}
+
+	return results, found, nil
+}
+
+func (t *Tracker) save(ctx context.Context, results Results) error {
+	if err := t.opts.store.Save(ctx, results); err != nil {
+		return fmt.Errorf("save poll %s: %w", results.Poll.Id, err)
+	}
+
+	return nil
+}
+
+// voterID returns the user who answered, or the chat on whose behalf they did.
+func voterID(answer *client.PollAnswer) (int64, bool) {
+	switch {
+	case answer.VoterChat != nil:
+		return answer.VoterChat.Id, true
+	case answer.User != nil:
+		return answer.User.Id, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,144 @@
+package polls_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/eventemitter"
+	"github.com/tgbotkit/runtime/events"
+	"github.com/tgbotkit/runtime/handlers"
+	"github.com/tgbotkit/runtime/logger"
+	"github.com/tgbotkit/runtime/polls"
+	"github.com/tgbotkit/runtime/polls/pollstore"
+)
+
+func newTracker(t *testing.T) (*polls.Tracker, eventemitter.EventEmitter) {
+	t.Helper()
+
+	ee, err := eventemitter.NewSync(eventemitter.NewOptions())
+	if err != nil {
+		t.Fatalf("NewSync() unexpected error: %v", err)
+	}
+
+	tracker, err := polls.New(polls.NewOptions(pollstore.NewInMemoryStore(), ee))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	tracker.Register(handlers.NewRegistry(ee, logger.NewNop()))
+
+	return tracker, ee
+}
+
+func pollMessage() *client.Message {
+	return &client.Message{
+		MessageId: 5,
+		Chat:      client.Chat{Id: 7},
+		Poll: &client.Poll{
+			Id:       "p1",
+			Question: "Lunch?",
+			Options:  []client.PollOption{{Text: "Pizza"}, {Text: "Sushi"}, {Text: "Salad"}},
+		},
+	}
+}
+
+func answer(userID int64, options ...int) *events.PollAnswerEvent {
+	return &events.PollAnswerEvent{PollAnswer: &client.PollAnswer{
+		PollId:    "p1",
+		User:      &client.User{Id: userID},
+		OptionIds: options,
+	}}
+}
+
+func TestTrackerAggregatesAnswers(t *testing.T) {
+	t.Parallel()
+
+	tracker, ee := newTracker(t)
+	ctx := context.Background()
+
+	if err := tracker.Track(ctx, pollMessage()); err != nil {
+		t.Fatalf("Track() unexpected error: %v", err)
+	}
+
+	ee.Emit(ctx, events.OnPollAnswer, answer(1, 0))
+	ee.Emit(ctx, events.OnPollAnswer, answer(2, 1))
+	ee.Emit(ctx, events.OnPollAnswer, answer(3, 0))
+	// Voters may change their mind, or retract their vote.
+	ee.Emit(ctx, events.OnPollAnswer, answer(2, 0))
+	ee.Emit(ctx, events.OnPollAnswer, answer(3))
+	// Answers to polls that are not tracked are ignored.
+	ee.Emit(ctx, events.OnPollAnswer, &events.PollAnswerEvent{PollAnswer: &client.PollAnswer{
+		PollId: "other", User: &client.User{Id: 1}, OptionIds: []int{1},
+	}})
+
+	results, found, err := tracker.Results(ctx, "p1")
+	if err != nil || !found {
+		t.Fatalf("Results() found=%v, err=%v", found, err)
+	}
+
+	if got := results.Tallies(); !slices.Equal(got, []int{2, 0, 0}) {
+		t.Fatalf("Tallies()=%v, want [2 0 0]", got)
+	}
+	if results.Poll.TotalVoterCount != 2 || len(results.Votes) != 2 || !slices.Equal(results.Votes[2], []int{0}) {
+		t.Fatalf("results=%+v, want users 1 and 2 voting for option 0", results)
+	}
+	if got := results.Leaders(); !slices.Equal(got, []int{0}) {
+		t.Fatalf("Leaders()=%v, want [0]", got)
+	}
+
+	if err := tracker.Track(ctx, &client.Message{}); !errors.Is(err, polls.ErrNotPoll) {
+		t.Fatalf("Track(no poll) error=%v, want ErrNotPoll", err)
+	}
+}
+
+func TestTrackerEmitsPollClosed(t *testing.T) {
+	t.Parallel()
+
+	tracker, ee := newTracker(t)
+	ctx := context.Background()
+
+	var closed []*events.PollClosedEvent
+
+	eventemitter.On(ee, events.OnPollClosed, func(_ context.Context, event *events.PollClosedEvent) error {
+		closed = append(closed, event)
+
+		return nil
+	})
+
+	if err := tracker.Track(ctx, pollMessage()); err != nil {
+		t.Fatalf("Track() unexpected error: %v", err)
+	}
+
+	ee.Emit(ctx, events.OnPollAnswer, answer(1, 2))
+
+	final := *pollMessage().Poll
+	final.IsClosed = true
+	final.TotalVoterCount = 4
+	final.Options[1].VoterCount = 1
+	final.Options[2].VoterCount = 3
+
+	ee.Emit(ctx, events.OnPoll, &events.PollEvent{Poll: &final})
+	// A closed poll is reported once, and ignores later answers.
+	ee.Emit(ctx, events.OnPoll, &events.PollEvent{Poll: &final})
+	ee.Emit(ctx, events.OnPollAnswer, answer(2, 0))
+
+	if len(closed) != 1 {
+		t.Fatalf("OnPollClosed emitted %d times, want 1", len(closed))
+	}
+
+	event := closed[0]
+	if event.ChatID != 7 || event.MessageID != 5 || event.Poll.TotalVoterCount != 4 {
+		t.Fatalf("event=%+v, want the final state of poll 7/5", event)
+	}
+	if !slices.Equal(event.Votes[1], []int{2}) {
+		t.Fatalf("Votes=%v, want user 1 voting for option 2", event.Votes)
+	}
+
+	results, _, _ := tracker.Results(ctx, "p1")
+	if got := results.Tallies(); !slices.Equal(got, []int{0, 1, 3}) {
+		t.Fatalf("Tallies()=%v, want [0 1 3]", got)
+	}
+}
@@ -0,0 +1,76 @@
+// Package counterstore provides ratelimit.Store implementations.
+package counterstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tgbotkit/runtime/ratelimit"
+)
+
+// sweepInterval is how often expired states are dropped.
+const sweepInterval = time.Minute
+
+// InMemoryStore keeps the counter states of a single process in memory.
+// States are dropped once their ttl has passed, so memory stays bounded by
+// the keys active recently.
+type InMemoryStore struct {
+	mu        sync.Mutex
+	states    map[string]entry
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+type entry struct {
+	state     ratelimit.State
+	expiresAt time.Time
+}
+
+var _ ratelimit.Store = (*InMemoryStore)(nil)
+
+// NewInMemoryStore creates a new InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		states: make(map[string]entry),
+		now:    time.Now,
+	}
+}
+
+// Update replaces the state of key with the one fn returns for the current
+// state.
+func (s *InMemoryStore) Update(
+	_ context.Context,
+	key string,
+	ttl time.Duration,
+	fn func(ratelimit.State) ratelimit.State,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	current, ok := s.states[key]
+	if !ok || now.After(current.expiresAt) {
+		current = entry{}
+	}
+
+	s.states[key] = entry{state: fn(current.state), expiresAt: now.Add(ttl)}
+
+	return nil
+}
+
+func (s *InMemoryStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+
+	for key, e := range s.states {
+		if now.After(e.expiresAt) {
+			delete(s.states, key)
+		}
+	}
+
+	s.nextSweep = now.Add(sweepInterval)
+}
@@ -0,0 +1,59 @@
+package counterstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tgbotkit/runtime/ratelimit"
+)
+
+func TestInMemoryStoreExpiresStates(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := NewInMemoryStore()
+	store.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	increment := func(state ratelimit.State) ratelimit.State {
+		state.Count++
+
+		return state
+	}
+
+	var seen ratelimit.State
+
+	for range 2 {
+		if err := store.Update(ctx, "user:1", time.Minute, increment); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if err := store.Update(ctx, "user:1", time.Minute, func(state ratelimit.State) ratelimit.State {
+		seen = state
+
+		return state
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if seen != (ratelimit.State{}) {
+		t.Errorf("Update() state after ttl = %+v, want zero state", seen)
+	}
+
+	// Expired keys are swept on a later update.
+	if err := store.Update(ctx, "user:2", time.Minute, increment); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if err := store.Update(ctx, "user:3", time.Minute, increment); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if len(store.states) != 1 {
+		t.Errorf("states = %d after sweeping, want 1", len(store.states))
+	}
+}
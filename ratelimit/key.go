@@ -0,0 +1,197 @@
+package ratelimit
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tgbotkit/client"
+)
+
+// KeyFunc returns the key the hits of an update are counted under, and false
+// for updates that are not limited.
+type KeyFunc func(update *client.Update) (string, bool)
+
+var (
+	_ KeyFunc = PerUser
+	_ KeyFunc = PerChat
+	_ KeyFunc = PerCommand
+)
+
+// PerUser counts the updates of each user across all chats. Payment updates
+// are not limited.
+func PerUser(update *client.Update) (string, bool) {
+	userID, _ := Sender(update)
+	if userID == 0 || IsPayment(update) {
+		return "", false
+	}
+
+	return "user:" + strconv.FormatInt(userID, 10), true
+}
+
+// PerChat counts the updates of each chat, whoever sends them. Payment
+// updates are not limited.
+func PerChat(update *client.Update) (string, bool) {
+	_, chatID := Sender(update)
+	if chatID == 0 || IsPayment(update) {
+		return "", false
+	}
+
+	return "chat:" + strconv.FormatInt(chatID, 10), true
+}
+
+// PerCommand counts the uses of each command by each user. Updates other
+// than command messages are not limited.
+func PerCommand(update *client.Update) (string, bool) {
+	message := updateMessage(update)
+	if message == nil || message.From == nil || message.Text == nil {
+		return "", false
+	}
+
+	text := *message.Text
+	if !strings.HasPrefix(text, "/") {
+		return "", false
+	}
+
+	command := text[1:]
+	if end := strings.IndexFunc(command, unicode.IsSpace); end >= 0 {
+		command = command[:end]
+	}
+
+	command, _, _ = strings.Cut(command, "@")
+
+	if command == "" {
+		return "", false
+	}
+
+	return "command:" + strconv.FormatInt(message.From.Id, 10) + ":" + strings.ToLower(command), true
+}
+
+// IsPayment reports whether update is part of a payment: a shipping or
+// pre-checkout query, which Telegram cancels when it is not answered within
+// 10 seconds, or the service message of a successful or refunded payment,
+// which must be recorded. Custom KeyFuncs should not limit them either.
+func IsPayment(update *client.Update) bool {
+	if update == nil {
+		return false
+	}
+
+	if update.ShippingQuery != nil || update.PreCheckoutQuery != nil {
+		return true
+	}
+
+	message := updateMessage(update)
+
+	return message != nil && (message.SuccessfulPayment != nil || message.RefundedPayment != nil)
+}
+
+// Sender returns the IDs of the user who sent update and of the chat it
+// belongs to. Either is zero when the update has none, such as the user of a
+// channel post or the chat of an inline query.
+func Sender(update *client.Update) (userID, chatID int64) {
+	if update == nil {
+		return 0, 0
+	}
+
+	if message := updateMessage(update); message != nil {
+		if message.From != nil {
+			userID = message.From.Id
+		}
+
+		return userID, message.Chat.Id
+	}
+
+	if user, chat := chatUpdateSender(update); user != nil || chat != nil {
+		return idOf(user), chatIDOf(chat)
+	}
+
+	return idOf(queryUser(update)), 0
+}
+
+func updateMessage(update *client.Update) *client.Message {
+	for _, message := range []*client.Message{
+		update.Message,
+		update.EditedMessage,
+		update.ChannelPost,
+		update.EditedChannelPost,
+		update.BusinessMessage,
+		update.EditedBusinessMessage,
+		update.GuestMessage,
+	} {
+		if message != nil {
+			return message
+		}
+	}
+
+	return nil
+}
+
+// chatUpdateSender returns the user and chat of the updates tied to a chat
+// that are not messages.
+func chatUpdateSender(update *client.Update) (*client.User, *client.Chat) {
+	switch {
+	case update.CallbackQuery != nil:
+		return &update.CallbackQuery.From, callbackChat(update.CallbackQuery)
+	case update.ChatMember != nil:
+		return &update.ChatMember.From, &update.ChatMember.Chat
+	case update.MyChatMember != nil:
+		return &update.MyChatMember.From, &update.MyChatMember.Chat
+	case update.ChatJoinRequest != nil:
+		return &update.ChatJoinRequest.From, &update.ChatJoinRequest.Chat
+	case update.MessageReaction != nil:
+		return update.MessageReaction.User, &update.MessageReaction.Chat
+	default:
+		return nil, nil
+	}
+}
+
+// queryUser returns the user of the updates tied to no chat.
+func queryUser(update *client.Update) *client.User {
+	switch {
+	case update.InlineQuery != nil:
+		return &update.InlineQuery.From
+	case update.ChosenInlineResult != nil:
+		return &update.ChosenInlineResult.From
+	case update.ShippingQuery != nil:
+		return &update.ShippingQuery.From
+	case update.PreCheckoutQuery != nil:
+		return &update.PreCheckoutQuery.From
+	case update.PollAnswer != nil:
+		return update.PollAnswer.User
+	default:
+		return nil
+	}
+}
+
+// callbackChat returns the chat of the message a callback button was
+// attached to, or nil for buttons of inline messages.
+func callbackChat(query *client.CallbackQuery) *client.Chat {
+	if query.Message == nil {
+		return nil
+	}
+
+	chat, _ := (*query.Message)["chat"].(map[string]any)
+	id, _ := chat["id"].(float64)
+
+	if id == 0 {
+		return nil
+	}
+
+	return &client.Chat{Id: int64(id)}
+}
+
+func idOf(user *client.User) int64 {
+	if user == nil {
+		return 0
+	}
+
+	return user.Id
+}
+
+func chatIDOf(chat *client.Chat) int64 {
+	if chat == nil {
+		return 0
+	}
+
+	return chat.Id
+}
@@ -0,0 +1,72 @@
+package ratelimit_test
+
+import (
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/ratelimit"
+)
+
+func TestPerCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text   string
+		key    string
+		limits bool
+	}{
+		{text: "/start", key: "command:7:start", limits: true},
+		{text: "/Help@bot", key: "command:7:help", limits: true},
+		{text: "/ban spammer", key: "command:7:ban", limits: true},
+		{text: "/ban\nreason", key: "command:7:ban", limits: true},
+		{text: "/ban\tspammer", key: "command:7:ban", limits: true},
+		{text: "/", limits: false},
+		{text: "hello", limits: false},
+	}
+
+	for _, tt := range tests {
+		update := &client.Update{Message: &client.Message{
+			From: &client.User{Id: 7},
+			Chat: client.Chat{Id: 7},
+			Text: &tt.text,
+		}}
+
+		key, ok := ratelimit.PerCommand(update)
+		if ok != tt.limits || key != tt.key {
+			t.Errorf("PerCommand(%q)=(%q, %v), want (%q, %v)", tt.text, key, ok, tt.key, tt.limits)
+		}
+	}
+}
+
+func TestKeysSkipPayments(t *testing.T) {
+	t.Parallel()
+
+	user := client.User{Id: 7}
+	message := func(payment *client.SuccessfulPayment, refund *client.RefundedPayment) *client.Update {
+		return &client.Update{Message: &client.Message{
+			From:              &user,
+			Chat:              client.Chat{Id: 7},
+			SuccessfulPayment: payment,
+			RefundedPayment:   refund,
+		}}
+	}
+
+	updates := map[string]*client.Update{
+		"pre-checkout query": {PreCheckoutQuery: &client.PreCheckoutQuery{From: user}},
+		"shipping query":     {ShippingQuery: &client.ShippingQuery{From: user}},
+		"successful payment": message(&client.SuccessfulPayment{}, nil),
+		"refunded payment":   message(nil, &client.RefundedPayment{}),
+	}
+
+	for name, update := range updates {
+		for _, key := range []ratelimit.KeyFunc{ratelimit.PerUser, ratelimit.PerChat, ratelimit.PerCommand} {
+			if _, ok := key(update); ok {
+				t.Errorf("%s is limited, want it left out", name)
+			}
+		}
+	}
+
+	if _, ok := ratelimit.PerUser(message(nil, nil)); !ok {
+		t.Error("PerUser() left out a plain message")
+	}
+}
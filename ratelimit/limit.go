@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// State is the counter state of one key. Its fields are read by the Limit
+// that counts the key: a token bucket keeps the tokens left in Count and the
+// time they were counted in Start; a sliding window keeps the hits of the
+// current and the previous window in Count and Previous, and the start of
+// the current window in Start. The zero State is the state of a key that was
+// never counted.
+type State struct {
+	Count    float64   `json:"count"`
+	Previous float64   `json:"previous,omitempty"`
+	Start    time.Time `json:"start"`
+}
+
+// Limit is a rate limiting algorithm.
+type Limit interface {
+	// Take counts one hit on state at now. It returns the new state and, when
+	// the hit is over the limit, how long until a hit is allowed again. A
+	// denied hit is not counted.
+	Take(state State, now time.Time) (next State, allowed bool, retryAfter time.Duration)
+	// TTL is how long a state stays meaningful after its last hit. Past it, the
+	// state may be dropped, which resets the key.
+	TTL() time.Duration
+}
+
+type tokenBucket struct {
+	burst float64
+	every time.Duration
+}
+
+// TokenBucket returns a Limit that allows bursts of up to burst hits, and
+// one more hit every interval after that.
+func TokenBucket(burst int, every time.Duration) Limit {
+	return tokenBucket{burst: float64(max(burst, 1)), every: max(every, time.Nanosecond)}
+}
+
+func (b tokenBucket) Take(state State, now time.Time) (State, bool, time.Duration) {
+	tokens := b.burst
+	if !state.Start.IsZero() {
+		refilled := float64(now.Sub(state.Start)) / float64(b.every)
+		tokens = min(b.burst, state.Count+max(refilled, 0))
+	}
+
+	if tokens < 1 {
+		retryAfter := time.Duration(math.Ceil((1 - tokens) * float64(b.every)))
+
+		return state, false, retryAfter
+	}
+
+	return State{Count: tokens - 1, Start: now}, true, 0
+}
+
+func (b tokenBucket) TTL() time.Duration {
+	return time.Duration(b.burst * float64(b.every))
+}
+
+// slidingWindows is how many windows the state of a sliding window counts:
+// the current and the previous one.
+const slidingWindows = 2
+
+type slidingWindow struct {
+	limit  float64
+	window time.Duration
+}
+
+// SlidingWindow returns a Limit that allows limit hits in any period of
+// length window. It weighs the hits of the previous window by how much of it
+// the period still covers, which smooths the bursts a fixed window allows at
+// its boundaries.
+func SlidingWindow(limit int, window time.Duration) Limit {
+	return slidingWindow{limit: float64(max(limit, 1)), window: max(window, time.Nanosecond)}
+}
+
+func (w slidingWindow) Take(state State, now time.Time) (State, bool, time.Duration) {
+	state = w.advance(state, now)
+	elapsed := float64(now.Sub(state.Start)) / float64(w.window)
+
+	if state.Previous*(1-elapsed)+state.Count+1 <= w.limit {
+		state.Count++
+
+		return state, true, 0
+	}
+
+	return state, false, w.retryAfter(state, now)
+}
+
+func (w slidingWindow) TTL() time.Duration {
+	return slidingWindows * w.window
+}
+
+// advance moves state to the window holding now.
+func (w slidingWindow) advance(state State, now time.Time) State {
+	start := now.Truncate(w.window)
+
+	switch {
+	case state.Start.Equal(start):
+		return state
+	case state.Start.Add(w.window).Equal(start):
+		return State{Previous: state.Count, Start: start}
+	default:
+		return State{Start: start}
+	}
+}
+
+// retryAfter returns how long until the weighted count of state leaves room
+// for one more hit.
+func (w slidingWindow) retryAfter(state State, now time.Time) time.Duration {
+	start, previous, count := state.Start, state.Previous, state.Count
+
+	// The hits of the current window may fill it alone, in which case they
+	// have to slide out of the next one.
+	if count+1 > w.limit {
+		start, previous, count = start.Add(w.window), count, 0
+	}
+
+	elapsed := 1.0
+	if previous > 0 {
+		elapsed = max(1-(w.limit-count-1)/previous, 0)
+	}
+
+	at := start.Add(time.Duration(math.Ceil(elapsed * float64(w.window))))
+
+	return max(at.Sub(now), time.Nanosecond)
+}
@@ -0,0 +1,67 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tgbotkit/runtime/ratelimit"
+)
+
+func TestTokenBucket(t *testing.T) {
+	t.Parallel()
+
+	limit := ratelimit.TokenBucket(2, time.Second)
+	now := time.Unix(1700000000, 0)
+
+	var (
+		state   ratelimit.State
+		allowed bool
+		retry   time.Duration
+	)
+
+	for i := range 2 {
+		if state, allowed, _ = limit.Take(state, now); !allowed {
+			t.Fatalf("Take() #%d denied within the burst", i)
+		}
+	}
+
+	if state, allowed, retry = limit.Take(state, now.Add(500*time.Millisecond)); allowed || retry != 500*time.Millisecond {
+		t.Fatalf("Take() allowed=%v retry=%v, want denied for 500ms", allowed, retry)
+	}
+
+	if _, allowed, _ = limit.Take(state, now.Add(time.Second)); !allowed {
+		t.Fatal("Take() denied after a token was refilled")
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	t.Parallel()
+
+	limit := ratelimit.SlidingWindow(4, time.Minute)
+	start := time.Unix(1700000000, 0).Truncate(time.Minute)
+
+	var (
+		state   ratelimit.State
+		allowed bool
+		retry   time.Duration
+	)
+
+	for i := range 4 {
+		if state, allowed, _ = limit.Take(state, start.Add(50*time.Second)); !allowed {
+			t.Fatalf("Take() #%d denied within the limit", i)
+		}
+	}
+
+	if _, allowed, retry = limit.Take(state, start.Add(55*time.Second)); allowed || retry != 20*time.Second {
+		t.Fatalf("Take() allowed=%v retry=%v, want denied until a quarter into the next window", allowed, retry)
+	}
+
+	// A quarter into the next window, the previous one weighs 3 hits.
+	if state, allowed, _ = limit.Take(state, start.Add(75*time.Second)); !allowed {
+		t.Fatal("Take() denied once the previous window slid out")
+	}
+
+	if _, allowed, _ = limit.Take(state, start.Add(76*time.Second)); allowed {
+		t.Fatal("Take() allowed over the weighted limit")
+	}
+}
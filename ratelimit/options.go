@@ -0,0 +1,11 @@
+package ratelimit
+
+//go:generate go tool options-gen -out-filename=options.gen.go -from-struct=Options
+
+// Options is the options for the Limiter.
+type Options struct {
+	// store keeps the counter state of each key.
+	store Store `option:"mandatory" validate:"required"`
+	// limit is the algorithm counting the hits of each key.
+	limit Limit `option:"mandatory" validate:"required"`
+}
@@ -0,0 +1,58 @@
+// Package ratelimit limits how often a key, such as a user or a chat, may do
+// something.
+//
+// A Limiter counts the hits of each key in a Store with a Limit: a
+// TokenBucket or a SlidingWindow. middleware.RateLimit uses it to protect the
+// bot from users and chats that flood it, keyed by the KeyFunc of the update.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Store keeps the counter states of the keys of a Limiter. Stores shared by
+// several processes, such as one backed by Redis, let them enforce one limit.
+type Store interface {
+	// Update atomically replaces the state of key with the one fn returns
+	// for the current state, the zero State for an unknown key. The state
+	// may be dropped once ttl has passed without an update.
+	Update(ctx context.Context, key string, ttl time.Duration, fn func(State) State) error
+}
+
+// Limiter limits the rate of hits of keys.
+type Limiter struct {
+	opts Options
+}
+
+// New creates a new Limiter with the given options.
+func New(opts Options) (*Limiter, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rate limiter options: %w", err)
+	}
+
+	return &Limiter{opts: opts}, nil
+}
+
+// Allow counts one hit of key and reports whether it is within the limit.
+// Otherwise, it returns how long until key is allowed again.
+func (l *Limiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	var (
+		allowed    bool
+		retryAfter time.Duration
+	)
+
+	now := time.Now()
+
+	err := l.opts.store.Update(ctx, key, l.opts.limit.TTL(), func(state State) State {
+		state, allowed, retryAfter = l.opts.limit.Take(state, now)
+
+		return state
+	})
+	if err != nil {
+		return false, 0, fmt.Errorf("update rate limit of %s: %w", key, err)
+	}
+
+	return allowed, retryAfter, nil
+}
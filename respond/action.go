@@ -0,0 +1,134 @@
+package respond
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tgbotkit/client"
+)
+
+// Chat actions shown to users while the bot prepares a response.
+const (
+	ActionTyping          = "typing"
+	ActionUploadPhoto     = "upload_photo"
+	ActionRecordVideo     = "record_video"
+	ActionUploadVideo     = "upload_video"
+	ActionRecordVoice     = "record_voice"
+	ActionUploadVoice     = "upload_voice"
+	ActionUploadDocument  = "upload_document"
+	ActionChooseSticker   = "choose_sticker"
+	ActionFindLocation    = "find_location"
+	ActionRecordVideoNote = "record_video_note"
+	ActionUploadVideoNote = "upload_video_note"
+)
+
+// DefaultActionInterval is how often KeepAction repeats an action. Telegram
+// shows an action for five seconds or until the bot sends a message.
+const DefaultActionInterval = 4500 * time.Millisecond
+
+// KeepActionOption customizes Responder.KeepAction.
+type KeepActionOption func(*keepActionConfig)
+
+type keepActionConfig struct {
+	interval time.Duration
+}
+
+// WithActionInterval sets how often KeepAction repeats the action.
+func WithActionInterval(interval time.Duration) KeepActionOption {
+	return func(config *keepActionConfig) {
+		if interval > 0 {
+			config.interval = interval
+		}
+	}
+}
+
+// SendChatAction shows action in the target chat, such as "typing…", for a
+// few seconds or until the bot sends a message there.
+func (r *Responder) SendChatAction(ctx context.Context, target ChatTarget, action string) error {
+	if r == nil || r.api == nil {
+		return ErrNilClient
+	}
+
+	body := client.SendChatActionJSONRequestBody{
+		ChatId:               target.ChatID,
+		Action:               action,
+		MessageThreadId:      target.MessageThreadID,
+		BusinessConnectionId: target.BusinessConnectionID,
+	}
+
+	resp, err := r.api.SendChatActionWithResponse(ctx, body)
+	if err != nil {
+		return fmt.Errorf("send chat action: %w", err)
+	}
+
+	if resp == nil {
+		return fmt.Errorf("send chat action: empty response")
+	}
+
+	if apiErr := responseError(resp.JSON200 != nil, resp.StatusCode(), resp.Body); apiErr != nil {
+		return fmt.Errorf("send chat action: %w", apiErr)
+	}
+
+	if resp.JSON200 == nil || !bool(resp.JSON200.Ok) || !resp.JSON200.Result {
+		return fmt.Errorf("send chat action: unexpected response: %s", resp.Status())
+	}
+
+	return nil
+}
+
+// KeepAction shows action in the target chat until the returned stop function
+// is called or ctx ends. The action is sent at once and repeated every
+// DefaultActionInterval. When the first send fails, KeepAction returns its
+// error and a stop function that does nothing; later failures end the
+// repetition. Call stop before sending the response: it waits for a send in
+// progress, so no action follows the response.
+func (r *Responder) KeepAction(
+	ctx context.Context,
+	target ChatTarget,
+	action string,
+	opts ...KeepActionOption,
+) (func(), error) {
+	config := keepActionConfig{interval: DefaultActionInterval}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&config)
+		}
+	}
+
+	if err := r.SendChatAction(ctx, target, action); err != nil {
+		return func() {}, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(config.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if r.SendChatAction(ctx, target, action) != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}, nil
+}
@@ -0,0 +1,121 @@
+package respond_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+func TestResponderKeepAction(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	responder := respond.New(&mockClient{
+		actionFunc: func(_ context.Context, body client.SendChatActionJSONRequestBody) (*client.SendChatActionResponse, error) {
+			if body.ChatId != 42 || body.Action != respond.ActionUploadPhoto {
+				t.Errorf("body=%+v, want upload_photo in chat 42", body)
+			}
+
+			calls.Add(1)
+
+			return chatActionResponse(), nil
+		},
+	})
+
+	stop, err := responder.KeepAction(
+		context.Background(),
+		respond.ChatTarget{ChatID: 42},
+		respond.ActionUploadPhoto,
+		respond.WithActionInterval(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("KeepAction() unexpected error: %v", err)
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("calls=%d right after KeepAction, want 1", got)
+	}
+
+	time.Sleep(55 * time.Millisecond)
+	stop()
+	stop()
+
+	sent := calls.Load()
+	if sent < 3 {
+		t.Fatalf("calls=%d, want the action refreshed", sent)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if got := calls.Load(); got != sent {
+		t.Fatalf("calls=%d after stop, want %d", got, sent)
+	}
+}
+
+func TestResponderKeepActionStopsWithContext(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	responder := respond.New(&mockClient{
+		actionFunc: func(context.Context, client.SendChatActionJSONRequestBody) (*client.SendChatActionResponse, error) {
+			calls.Add(1)
+
+			return chatActionResponse(), nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stop, err := responder.KeepAction(ctx, respond.ChatTarget{ChatID: 1}, respond.ActionTyping, respond.WithActionInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("KeepAction() unexpected error: %v", err)
+	}
+
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+
+	sent := calls.Load()
+
+	time.Sleep(10 * time.Millisecond)
+	stop()
+
+	if got := calls.Load(); got != sent {
+		t.Fatalf("calls=%d after the context ended, want %d", got, sent)
+	}
+}
+
+func TestResponderKeepActionReturnsFirstError(t *testing.T) {
+	t.Parallel()
+
+	responder := respond.New(&mockClient{
+		actionFunc: func(context.Context, client.SendChatActionJSONRequestBody) (*client.SendChatActionResponse, error) {
+			return nil, errors.New("network down")
+		},
+	})
+
+	stop, err := responder.KeepAction(context.Background(), respond.ChatTarget{ChatID: 1}, respond.ActionTyping)
+	if err == nil {
+		t.Fatal("KeepAction() error is nil, want non-nil")
+	}
+
+	stop()
+}
+
+func chatActionResponse() *client.SendChatActionResponse {
+	return &client.SendChatActionResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"},
+		JSON200: &struct {
+			Ok     client.SendChatAction200Ok `json:"ok"`
+			Result bool                       `json:"result"`
+		}{
+			Ok:     true,
+			Result: true,
+		},
+	}
+}
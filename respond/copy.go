@@ -0,0 +1,242 @@
+package respond
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tgbotkit/client"
+)
+
+// maxBulkMessages is how many messages forwardMessages and copyMessages take
+// in one call.
+const maxBulkMessages = 100
+
+// CopyRequest holds the optional parameters of a forward or copy built by
+// Responder. Forwards keep the original caption, and CopyMany can only remove
+// captions, so caption, video start and reply parameters apply to Copy alone.
+type CopyRequest struct {
+	Caption         *string
+	ParseMode       *string
+	CaptionEntities []client.MessageEntity
+	// RemoveCaption drops the caption of copied media.
+	RemoveCaption         bool
+	ShowCaptionAboveMedia bool
+	// VideoStart is where a copied video starts playing, sent in whole
+	// seconds. Zero keeps the start of the original.
+	VideoStart          time.Duration
+	DisableNotification bool
+	ProtectContent      bool
+	ReplyParameters     *client.ReplyParameters
+	// ReplyMarkup is any JSON-serializable keyboard, e.g. client.InlineKeyboardMarkup.
+	ReplyMarkup any
+	// Params holds parameters not wrapped here, keyed by their Bot API names.
+	Params map[string]any
+}
+
+// Forward forwards the message source refers to into the target chat. The
+// forward shows where the message came from.
+func (r *Responder) Forward(
+	ctx context.Context,
+	target ChatTarget,
+	source MessageRef,
+	opts ...CopyOption,
+) (*client.Message, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
+	}
+
+	form, err := newCopyForm("forward message", target, source)
+	if err != nil {
+		return nil, err
+	}
+
+	req := newCopyRequest(opts)
+	req.applyDelivery(form.values)
+	req.applyParams(form.values)
+
+	return postForm[client.Message](ctx, "forward message", form,
+		bodyCallOf(r.api.ForwardMessageWithBodyWithResponse))
+}
+
+// Copy sends a copy of the message source refers to into the target chat,
+// without a link to the original, and returns the ID of the copy. The caption
+// of copied media can be replaced with WithCopyCaption or dropped with
+// WithoutCaption. Service messages, giveaways and invoices cannot be copied.
+func (r *Responder) Copy(
+	ctx context.Context,
+	target ChatTarget,
+	source MessageRef,
+	opts ...CopyOption,
+) (int, error) {
+	if r == nil || r.api == nil {
+		return 0, ErrNilClient
+	}
+
+	form, err := newCopyForm("copy message", target, source)
+	if err != nil {
+		return 0, err
+	}
+
+	req := newCopyRequest(opts)
+	req.applyCaption(form.values)
+	req.applyDelivery(form.values)
+
+	if req.ReplyParameters != nil {
+		form.values["reply_parameters"] = req.ReplyParameters
+	}
+
+	if req.ReplyMarkup != nil {
+		form.values["reply_markup"] = req.ReplyMarkup
+	}
+
+	req.applyParams(form.values)
+
+	result, err := postForm[client.MessageId](ctx, "copy message", form,
+		bodyCallOf(r.api.CopyMessageWithBodyWithResponse))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.MessageId, nil
+}
+
+// ForwardMany forwards messages of one chat into the target chat and returns
+// the IDs of the forwards. Albums stay grouped. Messages that cannot be found
+// or forwarded are skipped, so fewer IDs than messageIDs may be returned.
+// More than 100 messages are forwarded in several calls; on failure, the IDs
+// forwarded so far are returned with the error.
+func (r *Responder) ForwardMany(
+	ctx context.Context,
+	target ChatTarget,
+	fromChatID int64,
+	messageIDs []int,
+	opts ...CopyOption,
+) ([]int, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
+	}
+
+	return r.bulk(ctx, "forward messages", target, fromChatID, messageIDs, opts, false,
+		bodyCallOf(r.api.ForwardMessagesWithBodyWithResponse))
+}
+
+// CopyMany copies messages of one chat into the target chat, like Copy, and
+// returns the IDs of the copies. Albums stay grouped, and captions are kept
+// unless WithoutCaption is given. Messages that cannot be found or copied are
+// skipped. More than 100 messages are copied in several calls; on failure,
+// the IDs copied so far are returned with the error.
+func (r *Responder) CopyMany(
+	ctx context.Context,
+	target ChatTarget,
+	fromChatID int64,
+	messageIDs []int,
+	opts ...CopyOption,
+) ([]int, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
+	}
+
+	return r.bulk(ctx, "copy messages", target, fromChatID, messageIDs, opts, true,
+		bodyCallOf(r.api.CopyMessagesWithBodyWithResponse))
+}
+
+// bulk forwards or copies messageIDs in batches. Telegram requires the IDs of
+// a batch to be strictly increasing, so they are sorted and deduplicated.
+func (r *Responder) bulk(
+	ctx context.Context,
+	op string,
+	target ChatTarget,
+	fromChatID int64,
+	messageIDs []int,
+	opts []CopyOption,
+	copying bool,
+	call bodyCall,
+) ([]int, error) {
+	ids := slices.Compact(slices.Sorted(slices.Values(messageIDs)))
+	req := newCopyRequest(opts)
+	result := make([]int, 0, len(ids))
+
+	for batch := range slices.Chunk(ids, maxBulkMessages) {
+		form := newRequestForm()
+		target.applyToForm(form.values)
+		form.values["from_chat_id"] = fromChatID
+		form.values["message_ids"] = batch
+		req.applyDelivery(form.values)
+
+		if copying {
+			setFlag(form.values, "remove_caption", req.RemoveCaption)
+		}
+
+		req.applyParams(form.values)
+
+		sent, err := postForm[[]client.MessageId](ctx, op, form, call)
+		if err != nil {
+			return result, err
+		}
+
+		for _, id := range *sent {
+			result = append(result, id.MessageId)
+		}
+	}
+
+	return result, nil
+}
+
+func newCopyForm(op string, target ChatTarget, source MessageRef) (*requestForm, error) {
+	if source.InlineMessageID != "" || source.MessageID == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrNoMessageTarget)
+	}
+
+	form := newRequestForm()
+	target.applyToForm(form.values)
+	form.values["from_chat_id"] = source.ChatID
+	form.values["message_id"] = source.MessageID
+
+	return form, nil
+}
+
+func newCopyRequest(opts []CopyOption) CopyRequest {
+	var req CopyRequest
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&req)
+		}
+	}
+
+	return req
+}
+
+func (req *CopyRequest) applyCaption(values map[string]any) {
+	switch {
+	case req.RemoveCaption:
+		// An empty caption replaces the original one.
+		values["caption"] = ""
+	case req.Caption != nil:
+		values["caption"] = *req.Caption
+	}
+
+	if req.ParseMode != nil {
+		values["parse_mode"] = *req.ParseMode
+	}
+
+	if len(req.CaptionEntities) > 0 {
+		values["caption_entities"] = req.CaptionEntities
+	}
+
+	setFlag(values, "show_caption_above_media", req.ShowCaptionAboveMedia)
+	setPositive(values, "video_start_timestamp", int(req.VideoStart/time.Second))
+}
+
+func (req *CopyRequest) applyDelivery(values map[string]any) {
+	setFlag(values, "disable_notification", req.DisableNotification)
+	setFlag(values, "protect_content", req.ProtectContent)
+}
+
+func (req *CopyRequest) applyParams(values map[string]any) {
+	for name, value := range req.Params {
+		values[name] = value
+	}
+}
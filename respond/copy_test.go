@@ -0,0 +1,179 @@
+package respond_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+func (m *mediaMockClient) ForwardMessageWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.ForwardMessageResponse, error) {
+	data, httpResp, err := m.call("forwardMessage", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.ForwardMessageResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func (m *mediaMockClient) CopyMessageWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.CopyMessageResponse, error) {
+	data, httpResp, err := m.call("copyMessage", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.CopyMessageResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func (m *mediaMockClient) CopyMessagesWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.CopyMessagesResponse, error) {
+	data, httpResp, err := m.call("copyMessages", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.CopyMessagesResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func (m *mediaMockClient) ForwardMessagesWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.ForwardMessagesResponse, error) {
+	data, httpResp, err := m.call("forwardMessages", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.ForwardMessagesResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func TestResponderForward(t *testing.T) {
+	t.Parallel()
+
+	responder, calls := newRecordingResponder(t, `{"message_id":5,"date":1,"chat":{"id":7,"type":"group"}}`)
+
+	msg, err := responder.Forward(context.Background(), respond.ChatTarget{ChatID: 7},
+		respond.MessageRef{ChatID: 3, MessageID: 11}, respond.WithCopySilent())
+	if err != nil {
+		t.Fatalf("Forward() unexpected error: %v", err)
+	}
+	if msg.MessageId != 5 {
+		t.Fatalf("Forward() message id=%d, want 5", msg.MessageId)
+	}
+
+	got := (*calls)[0]
+	if got.method != "forwardMessage" {
+		t.Fatalf("method=%q, want forwardMessage", got.method)
+	}
+	if got.values["chat_id"] != float64(7) || got.values["from_chat_id"] != float64(3) ||
+		got.values["message_id"] != float64(11) || got.values["disable_notification"] != true {
+		t.Fatalf("values=%v, want silent forward of 3/11 to 7", got.values)
+	}
+
+	_, err = responder.Forward(context.Background(), respond.ChatTarget{ChatID: 7}, respond.MessageRef{InlineMessageID: "x"})
+	if !errors.Is(err, respond.ErrNoMessageTarget) {
+		t.Fatalf("Forward(inline) error=%v, want ErrNoMessageTarget", err)
+	}
+}
+
+func TestResponderCopyReplacesCaption(t *testing.T) {
+	t.Parallel()
+
+	responder, calls := newRecordingResponder(t, `{"message_id":8}`)
+
+	id, err := responder.Copy(context.Background(), respond.ChatTarget{ChatID: 7},
+		respond.MessageRef{ChatID: 3, MessageID: 11},
+		respond.WithCopyCaption("<b>new</b>"),
+		respond.WithCopyCaptionHTML(),
+		respond.WithCopyProtectedContent(),
+		respond.WithCopyCaptionAboveMedia(),
+		respond.WithCopyVideoStart(15*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("Copy() unexpected error: %v", err)
+	}
+	if id != 8 {
+		t.Fatalf("Copy() id=%d, want 8", id)
+	}
+
+	got := (*calls)[0]
+	if got.method != "copyMessage" || got.values["caption"] != "<b>new</b>" ||
+		got.values["parse_mode"] != "HTML" || got.values["protect_content"] != true ||
+		got.values["show_caption_above_media"] != true || got.values["video_start_timestamp"] != float64(15) {
+		t.Fatalf("call=%v, want protected copy with HTML caption above the video starting at 15 seconds", got)
+	}
+
+	if _, err := responder.Copy(context.Background(), respond.ChatTarget{ChatID: 7},
+		respond.MessageRef{ChatID: 3, MessageID: 11}, respond.WithoutCaption()); err != nil {
+		t.Fatalf("Copy() unexpected error: %v", err)
+	}
+
+	if caption, ok := (*calls)[1].values["caption"]; !ok || caption != "" {
+		t.Fatalf("caption=%v, want empty caption to remove it", caption)
+	}
+}
+
+func TestResponderCopyManyBatchesSortedIDs(t *testing.T) {
+	t.Parallel()
+
+	responder, calls := newRecordingResponder(t, `[{"message_id":1}]`)
+
+	ids := make([]int, 0, 150)
+	for i := 150; i > 0; i-- {
+		ids = append(ids, i)
+	}
+
+	got, err := responder.CopyMany(context.Background(), respond.ChatTarget{ChatID: 7}, 3, append(ids, 5),
+		respond.WithoutCaption())
+	if err != nil {
+		t.Fatalf("CopyMany() unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("CopyMany() ids=%v, want one per batch from the mock", got)
+	}
+
+	if len(*calls) != 2 {
+		t.Fatalf("calls=%d, want 2 batches", len(*calls))
+	}
+
+	first := (*calls)[0].values
+	batch, _ := first["message_ids"].([]any)
+	if len(batch) != 100 || batch[0] != float64(1) || batch[99] != float64(100) {
+		t.Fatalf("first batch=%v, want IDs 1 to 100", batch)
+	}
+	if first["remove_caption"] != true || first["from_chat_id"] != float64(3) {
+		t.Fatalf("values=%v, want remove_caption from chat 3", first)
+	}
+
+	if _, err := responder.ForwardMany(context.Background(), respond.ChatTarget{ChatID: 7}, 3, []int{2, 1}); err != nil {
+		t.Fatalf("ForwardMany() unexpected error: %v", err)
+	}
+
+	last := (*calls)[2]
+	if last.method != "forwardMessages" {
+		t.Fatalf("method=%q, want forwardMessages", last.method)
+	}
+	if _, ok := last.values["remove_caption"]; ok {
+		t.Fatalf("values=%v, forwards cannot remove captions", last.values)
+	}
+}
@@ -0,0 +1,459 @@
+package respond
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tgbotkit/client"
+)
+
+// MessageRef identifies an existing message: a chat message by chat and
+// message ID, or a message sent via inline mode by its inline message ID.
+type MessageRef struct {
+	ChatID               int64
+	MessageID            int
+	InlineMessageID      string
+	BusinessConnectionID *string
+}
+
+// EditRequest holds the optional parameters of an edit built by Responder.
+type EditRequest struct {
+	ParseMode             *string
+	Entities              []client.MessageEntity
+	ShowCaptionAboveMedia bool
+	LinkPreviewOptions    *client.LinkPreviewOptions
+	ReplyMarkup           *client.InlineKeyboardMarkup
+	// Params holds parameters not wrapped here, keyed by their Bot API names.
+	Params map[string]any
+}
+
+// RefFromMessage builds a reference to an existing chat message.
+func RefFromMessage(message *client.Message) (MessageRef, error) {
+	if message == nil {
+		return MessageRef{}, ErrNilMessage
+	}
+
+	return MessageRef{
+		ChatID:               message.Chat.Id,
+		MessageID:            message.MessageId,
+		BusinessConnectionID: message.BusinessConnectionId,
+	}, nil
+}
+
+// RefFromCallback builds a reference to the message a callback button was
+// attached to. It returns ErrNoMessageTarget when that message is inaccessible.
+func RefFromCallback(query *client.CallbackQuery) (MessageRef, error) {
+	if query == nil {
+		return MessageRef{}, ErrNilCallbackQuery
+	}
+
+	if query.InlineMessageId != nil && *query.InlineMessageId != "" {
+		return MessageRef{InlineMessageID: *query.InlineMessageId}, nil
+	}
+
+	message, err := CallbackMessage(query)
+	if err != nil {
+		return MessageRef{}, err
+	}
+
+	return RefFromMessage(message)
+}
+
+// CallbackMessage decodes the message a callback button was attached to.
+// It returns ErrNoMessageTarget when the query has no message, the message is
+// too old to be accessible, or the button belongs to an inline message.
+func CallbackMessage(query *client.CallbackQuery) (*client.Message, error) {
+	if query == nil {
+		return nil, ErrNilCallbackQuery
+	}
+
+	if query.Message == nil {
+		return nil, ErrNoMessageTarget
+	}
+
+	data, err := json.Marshal(*query.Message)
+	if err != nil {
+		return nil, fmt.Errorf("decode callback message: %w", err)
+	}
+
+	var message client.Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, fmt.Errorf("decode callback message: %w", err)
+	}
+
+	// Telegram reports inaccessible messages with a zero date.
+	if message.Date == 0 {
+		return nil, ErrNoMessageTarget
+	}
+
+	return &message, nil
+}
+
+// EditText replaces the text of a message. The returned message is nil when
+// an inline message was edited.
+func (r *Responder) EditText(
+	ctx context.Context,
+	ref MessageRef,
+	text string,
+	opts ...EditOption,
+) (*client.Message, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
+	}
+
+	return r.editForm(ctx, "edit message text", ref, opts,
+		func(req *EditRequest, form *requestForm) error {
+			form.values["text"] = text
+			req.applyText(form.values)
+
+			return nil
+		},
+		bodyCallOf(r.api.EditMessageTextWithBodyWithResponse))
+}
+
+// EditCaption replaces the caption of a media message. The returned message
+// is nil when an inline message was edited.
+func (r *Responder) EditCaption(
+	ctx context.Context,
+	ref MessageRef,
+	caption string,
+	opts ...EditOption,
+) (*client.Message, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
+	}
+
+	return r.editForm(ctx, "edit message caption", ref, opts,
+		func(req *EditRequest, form *requestForm) error {
+			form.values["caption"] = caption
+			req.applyCaption(form.values)
+
+			return nil
+		},
+		bodyCallOf(r.api.EditMessageCaptionWithBodyWithResponse))
+}
+
+// EditReplyMarkup replaces the inline keyboard of a message; a nil markup
+// removes it. The returned message is nil when an inline message was edited.
+func (r *Responder) EditReplyMarkup(
+	ctx context.Context,
+	ref MessageRef,
+	markup *client.InlineKeyboardMarkup,
+	opts ...EditOption,
+) (*client.Message, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
+	}
+
+	opts = append([]EditOption{WithEditReplyMarkup(markup)}, opts...)
+
+	return r.editForm(ctx, "edit message reply markup", ref, opts,
+		func(*EditRequest, *requestForm) error { return nil },
+		bodyCallOf(r.api.EditMessageReplyMarkupWithBodyWithResponse))
+}
+
+// EditMedia replaces the media of a message with an item built by GroupPhoto,
+// GroupVideo, GroupAudio, GroupDocument or AnimationMedia, uploading readers
+// as needed. The caption is that of the item; the parse mode, entities and
+// caption position set by opts apply to it unless the item sets its own. The
+// returned message is nil when an inline message was edited.
+func (r *Responder) EditMedia(
+	ctx context.Context,
+	ref MessageRef,
+	media GroupMedia,
+	opts ...EditOption,
+) (*client.Message, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
+	}
+
+	if media.file.isZero() {
+		return nil, fmt.Errorf("edit message media: %w", ErrEmptyInputFile)
+	}
+
+	return r.editForm(ctx, "edit message media", ref, opts,
+		func(req *EditRequest, form *requestForm) error {
+			items, err := form.groupItems([]GroupMedia{media})
+			if err != nil {
+				return err
+			}
+
+			req.applyMedia(items[0])
+			form.values["media"] = items[0]
+
+			return nil
+		},
+		bodyCallOf(r.api.EditMessageMediaWithBodyWithResponse))
+}
+
+// DeleteMessage deletes a chat message. Inline messages cannot be deleted.
+func (r *Responder) DeleteMessage(ctx context.Context, ref MessageRef) error {
+	if r == nil || r.api == nil {
+		return ErrNilClient
+	}
+
+	if ref.InlineMessageID != "" || ref.MessageID == 0 {
+		return fmt.Errorf("delete message: %w", ErrNoMessageTarget)
+	}
+
+	form := newRequestForm()
+	form.values["chat_id"] = ref.ChatID
+	form.values["message_id"] = ref.MessageID
+
+	return postBoolForm(ctx, "delete message", form,
+		bodyCallOf(r.api.DeleteMessageWithBodyWithResponse))
+}
+
+// DeleteMessages deletes up to 100 messages from one chat in a single call.
+func (r *Responder) DeleteMessages(ctx context.Context, chatID int64, messageIDs []int) error {
+	if r == nil || r.api == nil {
+		return ErrNilClient
+	}
+
+	if len(messageIDs) == 0 {
+		return nil
+	}
+
+	form := newRequestForm()
+	form.values["chat_id"] = chatID
+	form.values["message_ids"] = messageIDs
+
+	return postBoolForm(ctx, "delete messages", form,
+		bodyCallOf(r.api.DeleteMessagesWithBodyWithResponse))
+}
+
+// PinMessage pins a chat message. Inline messages cannot be pinned.
+func (r *Responder) PinMessage(ctx context.Context, ref MessageRef, opts ...PinOption) error {
+	if r == nil || r.api == nil {
+		return ErrNilClient
+	}
+
+	if ref.InlineMessageID != "" || ref.MessageID == 0 {
+		return fmt.Errorf("pin chat message: %w", ErrNoMessageTarget)
+	}
+
+	form := newRequestForm()
+	ref.applyToForm(form.values)
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(form.values)
+		}
+	}
+
+	return postBoolForm(ctx, "pin chat message", form,
+		bodyCallOf(r.api.PinChatMessageWithBodyWithResponse))
+}
+
+// EditCallbackText replaces the text of the message a callback button was attached to.
+func (r *Responder) EditCallbackText(
+	ctx context.Context,
+	query *client.CallbackQuery,
+	text string,
+	opts ...EditOption,
+) (*client.Message, error) {
+	ref, err := RefFromCallback(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.EditText(ctx, ref, text, opts...)
+}
+
+// EditCallbackCaption replaces the caption of the message a callback button was attached to.
+func (r *Responder) EditCallbackCaption(
+	ctx context.Context,
+	query *client.CallbackQuery,
+	caption string,
+	opts ...EditOption,
+) (*client.Message, error) {
+	ref, err := RefFromCallback(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.EditCaption(ctx, ref, caption, opts...)
+}
+
+// EditCallbackReplyMarkup replaces the keyboard of the message a callback button was attached to.
+func (r *Responder) EditCallbackReplyMarkup(
+	ctx context.Context,
+	query *client.CallbackQuery,
+	markup *client.InlineKeyboardMarkup,
+	opts ...EditOption,
+) (*client.Message, error) {
+	ref, err := RefFromCallback(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.EditReplyMarkup(ctx, ref, markup, opts...)
+}
+
+// EditCallbackMedia replaces the media of the message a callback button was attached to.
+func (r *Responder) EditCallbackMedia(
+	ctx context.Context,
+	query *client.CallbackQuery,
+	media GroupMedia,
+	opts ...EditOption,
+) (*client.Message, error) {
+	ref, err := RefFromCallback(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.EditMedia(ctx, ref, media, opts...)
+}
+
+// PinCallbackMessage pins the message a callback button was attached to.
+func (r *Responder) PinCallbackMessage(ctx context.Context, query *client.CallbackQuery, opts ...PinOption) error {
+	ref, err := RefFromCallback(query)
+	if err != nil {
+		return err
+	}
+
+	return r.PinMessage(ctx, ref, opts...)
+}
+
+// DeleteCallbackMessage deletes the message a callback button was attached to.
+func (r *Responder) DeleteCallbackMessage(ctx context.Context, query *client.CallbackQuery) error {
+	ref, err := RefFromCallback(query)
+	if err != nil {
+		return err
+	}
+
+	return r.DeleteMessage(ctx, ref)
+}
+
+func (r *Responder) editForm(
+	ctx context.Context,
+	op string,
+	ref MessageRef,
+	opts []EditOption,
+	fill func(*EditRequest, *requestForm) error,
+	call bodyCall,
+) (*client.Message, error) {
+	if ref.InlineMessageID == "" && ref.MessageID == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrNoMessageTarget)
+	}
+
+	req := newEditRequest(opts)
+	form := newRequestForm()
+	ref.applyToForm(form.values)
+
+	if err := fill(&req, form); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	req.applyCommon(form.values)
+
+	result, err := postForm[json.RawMessage](ctx, op, form, call)
+	if err != nil {
+		return nil, err
+	}
+
+	return editedMessage(op, *result)
+}
+
+// editedMessage decodes an edit result. Inline message edits return true
+// instead of the edited message, which yields a nil message.
+func editedMessage(op string, result json.RawMessage) (*client.Message, error) {
+	if len(result) == 0 || result[0] != '{' {
+		return nil, nil //nolint:nilnil // inline edits have no message
+	}
+
+	var message client.Message
+	if err := json.Unmarshal(result, &message); err != nil {
+		return nil, fmt.Errorf("%s: decode result: %w", op, err)
+	}
+
+	return &message, nil
+}
+
+func postBoolForm(ctx context.Context, op string, form *requestForm, call bodyCall) error {
+	result, err := postForm[bool](ctx, op, form, call)
+	if err != nil {
+		return err
+	}
+
+	if !*result {
+		return fmt.Errorf("%s: unexpected result: false", op)
+	}
+
+	return nil
+}
+
+func (m MessageRef) applyToForm(values map[string]any) {
+	if m.InlineMessageID != "" {
+		values["inline_message_id"] = m.InlineMessageID
+	} else {
+		values["chat_id"] = m.ChatID
+		values["message_id"] = m.MessageID
+	}
+
+	if m.BusinessConnectionID != nil {
+		values["business_connection_id"] = *m.BusinessConnectionID
+	}
+}
+
+func newEditRequest(opts []EditOption) EditRequest {
+	var req EditRequest
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&req)
+		}
+	}
+
+	return req
+}
+
+func (req *EditRequest) applyText(values map[string]any) {
+	if req.ParseMode != nil {
+		values["parse_mode"] = *req.ParseMode
+	}
+
+	if len(req.Entities) > 0 {
+		values["entities"] = req.Entities
+	}
+
+	if req.LinkPreviewOptions != nil {
+		values["link_preview_options"] = req.LinkPreviewOptions
+	}
+}
+
+func (req *EditRequest) applyCaption(values map[string]any) {
+	if req.ParseMode != nil {
+		values["parse_mode"] = *req.ParseMode
+	}
+
+	if len(req.Entities) > 0 {
+		values["caption_entities"] = req.Entities
+	}
+
+	setFlag(values, "show_caption_above_media", req.ShowCaptionAboveMedia)
+}
+
+// applyMedia formats the caption of the new media item, which Telegram reads
+// from the item rather than from the request.
+func (req *EditRequest) applyMedia(item map[string]any) {
+	caption := make(map[string]any)
+	req.applyCaption(caption)
+
+	for name, value := range caption {
+		if _, ok := item[name]; !ok {
+			item[name] = value
+		}
+	}
+}
+
+func (req *EditRequest) applyCommon(values map[string]any) {
+	if req.ReplyMarkup != nil {
+		values["reply_markup"] = req.ReplyMarkup
+	}
+
+	for name, value := range req.Params {
+		values[name] = value
+	}
+}
@@ -0,0 +1,293 @@
+package respond_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+func (m *mediaMockClient) EditMessageTextWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.EditMessageTextResponse, error) {
+	data, httpResp, err := m.call("editMessageText", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.EditMessageTextResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func (m *mediaMockClient) EditMessageReplyMarkupWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.EditMessageReplyMarkupResponse, error) {
+	data, httpResp, err := m.call("editMessageReplyMarkup", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.EditMessageReplyMarkupResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func (m *mediaMockClient) EditMessageMediaWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.EditMessageMediaResponse, error) {
+	data, httpResp, err := m.call("editMessageMedia", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.EditMessageMediaResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func (m *mediaMockClient) DeleteMessageWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.DeleteMessageResponse, error) {
+	data, httpResp, err := m.call("deleteMessage", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.DeleteMessageResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func (m *mediaMockClient) DeleteMessagesWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.DeleteMessagesResponse, error) {
+	data, httpResp, err := m.call("deleteMessages", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.DeleteMessagesResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func (m *mediaMockClient) PinChatMessageWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.PinChatMessageResponse, error) {
+	data, httpResp, err := m.call("pinChatMessage", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.PinChatMessageResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+type recordedCall struct {
+	method string
+	values map[string]any
+}
+
+func newRecordingResponder(t *testing.T, result string) (*respond.Responder, *[]recordedCall) {
+	t.Helper()
+
+	calls := &[]recordedCall{}
+	responder := respond.New(&mediaMockClient{
+		bodyFunc: func(method string, _ string, body []byte) (int, string) {
+			var values map[string]any
+			if err := json.Unmarshal(body, &values); err != nil {
+				t.Errorf("Unmarshal() unexpected error: %v", err)
+			}
+
+			*calls = append(*calls, recordedCall{method: method, values: values})
+
+			return http.StatusOK, `{"ok":true,"result":` + result + `}`
+		},
+	})
+
+	return responder, calls
+}
+
+func callbackWithMessage(t *testing.T, message map[string]any) *client.CallbackQuery {
+	t.Helper()
+
+	var maybe client.MaybeInaccessibleMessage = message
+
+	return &client.CallbackQuery{Id: "cb", Message: &maybe}
+}
+
+func TestResponderEditCallbackText(t *testing.T) {
+	t.Parallel()
+
+	responder, calls := newRecordingResponder(t, `{"message_id":9,"date":1,"chat":{"id":42,"type":"private"}}`)
+	query := callbackWithMessage(t, map[string]any{
+		"message_id": 9,
+		"date":       1,
+		"chat":       map[string]any{"id": 42, "type": "private"},
+	})
+
+	msg, err := responder.EditCallbackText(context.Background(), query, "<b>done</b>", respond.WithEditHTML())
+	if err != nil {
+		t.Fatalf("EditCallbackText() unexpected error: %v", err)
+	}
+	if msg == nil || msg.MessageId != 9 {
+		t.Fatalf("EditCallbackText() message=%v, want id 9", msg)
+	}
+
+	got := (*calls)[0]
+	if got.method != "editMessageText" {
+		t.Fatalf("method=%q, want editMessageText", got.method)
+	}
+	if got.values["chat_id"] != float64(42) || got.values["message_id"] != float64(9) {
+		t.Fatalf("target=%v, want chat 42 message 9", got.values)
+	}
+	if got.values["text"] != "<b>done</b>" || got.values["parse_mode"] != "HTML" {
+		t.Fatalf("values=%v, want HTML text", got.values)
+	}
+}
+
+func TestResponderEditCallbackInlineMessage(t *testing.T) {
+	t.Parallel()
+
+	responder, calls := newRecordingResponder(t, `true`)
+	inlineID := "inline-1"
+	query := &client.CallbackQuery{Id: "cb", InlineMessageId: &inlineID}
+
+	msg, err := responder.EditCallbackReplyMarkup(context.Background(), query, &client.InlineKeyboardMarkup{})
+	if err != nil {
+		t.Fatalf("EditCallbackReplyMarkup() unexpected error: %v", err)
+	}
+	if msg != nil {
+		t.Fatalf("EditCallbackReplyMarkup() message=%v, want nil for inline message", msg)
+	}
+
+	got := (*calls)[0]
+	if got.values["inline_message_id"] != inlineID {
+		t.Fatalf("inline_message_id=%v, want %q", got.values["inline_message_id"], inlineID)
+	}
+	if _, ok := got.values["chat_id"]; ok {
+		t.Fatalf("chat_id set for inline message: %v", got.values)
+	}
+	if _, ok := got.values["reply_markup"]; !ok {
+		t.Fatalf("reply_markup missing: %v", got.values)
+	}
+
+	if err := responder.DeleteCallbackMessage(context.Background(), query); !errors.Is(err, respond.ErrNoMessageTarget) {
+		t.Fatalf("DeleteCallbackMessage(inline) error=%v, want ErrNoMessageTarget", err)
+	}
+}
+
+func TestResponderEditMedia(t *testing.T) {
+	t.Parallel()
+
+	responder, calls := newRecordingResponder(t, `{"message_id":3,"date":1,"chat":{"id":5,"type":"private"}}`)
+
+	_, err := responder.EditMedia(
+		context.Background(),
+		respond.MessageRef{ChatID: 5, MessageID: 3},
+		respond.GroupPhoto(respond.FileID("new-photo"), respond.WithCaption("<b>updated</b>")),
+		respond.WithEditHTML(),
+		respond.WithEditCaptionAboveMedia(),
+	)
+	if err != nil {
+		t.Fatalf("EditMedia() unexpected error: %v", err)
+	}
+
+	got := (*calls)[0]
+	media, _ := got.values["media"].(map[string]any)
+	if media["type"] != "photo" || media["media"] != "new-photo" || media["caption"] != "<b>updated</b>" {
+		t.Fatalf("media=%v, want photo with caption", media)
+	}
+	if media["parse_mode"] != "HTML" || media["show_caption_above_media"] != true {
+		t.Fatalf("media=%v, want HTML caption above the photo", media)
+	}
+	if _, ok := got.values["parse_mode"]; ok {
+		t.Fatalf("parse_mode set on the request, which Telegram ignores: %v", got.values)
+	}
+}
+
+func TestResponderDeleteAndPin(t *testing.T) {
+	t.Parallel()
+
+	responder, calls := newRecordingResponder(t, `true`)
+	ref := respond.MessageRef{ChatID: 5, MessageID: 3}
+
+	if err := responder.DeleteMessage(context.Background(), ref); err != nil {
+		t.Fatalf("DeleteMessage() unexpected error: %v", err)
+	}
+	if err := responder.DeleteMessages(context.Background(), 5, []int{1, 2}); err != nil {
+		t.Fatalf("DeleteMessages() unexpected error: %v", err)
+	}
+	if err := responder.PinMessage(context.Background(), ref, respond.WithPinSilent()); err != nil {
+		t.Fatalf("PinMessage() unexpected error: %v", err)
+	}
+
+	query := callbackWithMessage(t, map[string]any{
+		"message_id": 9,
+		"date":       1,
+		"chat":       map[string]any{"id": 42, "type": "group"},
+	})
+	if err := responder.PinCallbackMessage(context.Background(), query); err != nil {
+		t.Fatalf("PinCallbackMessage() unexpected error: %v", err)
+	}
+
+	if len(*calls) != 4 {
+		t.Fatalf("calls=%d, want 4", len(*calls))
+	}
+	if ids, _ := (*calls)[1].values["message_ids"].([]any); len(ids) != 2 {
+		t.Fatalf("message_ids=%v, want 2 ids", (*calls)[1].values["message_ids"])
+	}
+	pin := (*calls)[2]
+	if pin.method != "pinChatMessage" || pin.values["disable_notification"] != true {
+		t.Fatalf("pin call=%v, want silent pinChatMessage", pin)
+	}
+	if pin := (*calls)[3]; pin.method != "pinChatMessage" || pin.values["chat_id"] != float64(42) ||
+		pin.values["message_id"] != float64(9) {
+		t.Fatalf("pin call=%v, want pinChatMessage of chat 42 message 9", pin)
+	}
+}
+
+func TestResponderCallbackTargetErrors(t *testing.T) {
+	t.Parallel()
+
+	responder, calls := newRecordingResponder(t, `true`)
+
+	inaccessible := callbackWithMessage(t, map[string]any{
+		"message_id": 9,
+		"date":       0,
+		"chat":       map[string]any{"id": 42, "type": "private"},
+	})
+
+	tests := []struct {
+		name  string
+		query *client.CallbackQuery
+		want  error
+	}{
+		{name: "nil query", query: nil, want: respond.ErrNilCallbackQuery},
+		{name: "no message", query: &client.CallbackQuery{Id: "cb"}, want: respond.ErrNoMessageTarget},
+		{name: "inaccessible message", query: inaccessible, want: respond.ErrNoMessageTarget},
+	}
+
+	for _, tt := range tests {
+		if _, err := responder.EditCallbackText(context.Background(), tt.query, "x"); !errors.Is(err, tt.want) {
+			t.Fatalf("%s: EditCallbackText() error=%v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	if len(*calls) != 0 {
+		t.Fatalf("calls=%d, want 0", len(*calls))
+	}
+}
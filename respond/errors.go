@@ -13,3 +13,20 @@ var ErrNilCallbackQuery = errors.New("nil callback query")
 
 // ErrNoMessageTarget is returned when a callback query has no usable message target.
 var ErrNoMessageTarget = errors.New("message target unavailable")
+
+// ErrEmptyInputFile is returned when a media request has no file_id, URL or reader.
+var ErrEmptyInputFile = errors.New("empty input file")
+
+// ErrInvalidMediaGroup is returned when a media group has fewer than 2 or more than 10 items.
+var ErrInvalidMediaGroup = errors.New("invalid media group size")
+
+// ErrInvalidPoll is returned when a poll has fewer than 2 or more than 12
+// options, or a quiz names no valid correct option.
+var ErrInvalidPoll = errors.New("invalid poll")
+
+// ErrUnsupportedParseMode is returned when text in a parse mode other than HTML
+// or MarkdownV2 must be split.
+var ErrUnsupportedParseMode = errors.New("unsupported parse mode")
+
+// ErrStreamClosed is returned when text is written to a closed StreamWriter.
+var ErrStreamClosed = errors.New("stream closed")
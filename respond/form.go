@@ -0,0 +1,163 @@
+package respond
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/throttle"
+)
+
+const attachPrefix = "attach://"
+
+// bodyCall sends a request body through one of the generated *WithBodyWithResponse
+// methods and returns the raw response.
+type bodyCall func(ctx context.Context, contentType string, body io.Reader) ([]byte, *http.Response, error)
+
+// bodyCallOf adapts a generated *WithBodyWithResponse method to a bodyCall. The
+// generated responses share their Body and HTTPResponse fields but no method
+// to read them, so the fields are read by name.
+func bodyCallOf[R any](
+	send func(ctx context.Context, contentType string, body io.Reader, editors ...client.RequestEditorFn) (*R, error),
+) bodyCall {
+	return func(ctx context.Context, contentType string, body io.Reader) ([]byte, *http.Response, error) {
+		resp, err := send(ctx, contentType, body)
+		if err != nil || resp == nil {
+			return nil, nil, err
+		}
+
+		fields := reflect.ValueOf(resp).Elem()
+		httpResp, _ := fields.FieldByName("HTTPResponse").Interface().(*http.Response)
+
+		return fields.FieldByName("Body").Bytes(), httpResp, nil
+	}
+}
+
+// requestForm collects request parameters and any files uploaded with them.
+type requestForm struct {
+	values map[string]any
+	files  []formFile
+}
+
+type formFile struct {
+	field string
+	file  InputFile
+}
+
+func newRequestForm() *requestForm {
+	return &requestForm{values: make(map[string]any)}
+}
+
+// setFile stores a reference in field, or uploads the file directly as field.
+func (f *requestForm) setFile(field string, file InputFile) {
+	if file.IsUpload() {
+		f.files = append(f.files, formFile{field: field, file: file})
+
+		return
+	}
+
+	f.values[field] = file.ref()
+}
+
+// attachFile returns the value referencing file from a parameter, uploading it
+// under name when it is read from a reader.
+func (f *requestForm) attachFile(name string, file InputFile) string {
+	if !file.IsUpload() {
+		return file.ref()
+	}
+
+	f.files = append(f.files, formFile{field: name, file: file})
+
+	return attachPrefix + name
+}
+
+func (f *requestForm) hasUploads() bool {
+	return len(f.files) > 0
+}
+
+// post sends the form as JSON, or as a streamed multipart body when it has uploads.
+func (f *requestForm) post(ctx context.Context, call bodyCall) ([]byte, *http.Response, error) {
+	if !f.hasUploads() {
+		data, err := json.Marshal(f.values)
+		if err != nil {
+			return nil, nil, fmt.Errorf("encode request: %w", err)
+		}
+
+		return call(ctx, "application/json", bytes.NewReader(data))
+	}
+
+	// A streamed body cannot be inspected, so tell a throttle.Limiter the chat.
+	if chatID, ok := f.values["chat_id"].(int64); ok {
+		ctx = throttle.WithChat(ctx, chatID)
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	contentType := writer.FormDataContentType()
+
+	go func() {
+		pw.CloseWithError(f.writeMultipart(writer))
+	}()
+
+	body, httpResp, err := call(ctx, contentType, pr)
+
+	// Unblock the writer if the call returned without draining the body.
+	_ = pr.Close()
+
+	return body, httpResp, err
+}
+
+func (f *requestForm) writeMultipart(writer *multipart.Writer) error {
+	keys := make([]string, 0, len(f.values))
+	for key := range f.values {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		value, err := formValue(f.values[key])
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", key, err)
+		}
+
+		if err := writer.WriteField(key, value); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range f.files {
+		part, err := writer.CreateFormFile(file.field, file.file.fileName(file.field))
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(part, file.file.reader); err != nil {
+			return fmt.Errorf("upload %s: %w", file.field, err)
+		}
+	}
+
+	return writer.Close()
+}
+
+// formValue encodes a parameter for multipart requests: strings as is,
+// everything else as JSON.
+func formValue(value any) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,54 @@
+package respond
+
+import "io"
+
+// InputFile is a file sent with a media request: an existing Telegram file_id,
+// an HTTP URL Telegram downloads itself, or content uploaded from a reader.
+type InputFile struct {
+	fileID string
+	url    string
+	name   string
+	reader io.Reader
+}
+
+// FileID references a file already stored on Telegram servers.
+func FileID(fileID string) InputFile {
+	return InputFile{fileID: fileID}
+}
+
+// FileURL references a file Telegram downloads from an HTTP URL.
+func FileURL(url string) InputFile {
+	return InputFile{url: url}
+}
+
+// FileReader uploads content read from r under the given file name.
+// The reader is streamed into the multipart request body and is not closed.
+func FileReader(name string, r io.Reader) InputFile {
+	return InputFile{name: name, reader: r}
+}
+
+// IsUpload reports whether the file is uploaded from a reader.
+func (f InputFile) IsUpload() bool {
+	return f.reader != nil
+}
+
+func (f InputFile) isZero() bool {
+	return f.fileID == "" && f.url == "" && f.reader == nil
+}
+
+// ref returns the string Telegram accepts for a file_id or URL reference.
+func (f InputFile) ref() string {
+	if f.fileID != "" {
+		return f.fileID
+	}
+
+	return f.url
+}
+
+func (f InputFile) fileName(fallback string) string {
+	if f.name != "" {
+		return f.name
+	}
+
+	return fallback
+}
@@ -0,0 +1,44 @@
+package respond
+
+import (
+	"context"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/i18n"
+)
+
+// SendLocalized sends the translation of key, with data for its template, in
+// the locale of ctx set by the middleware.Localizer middleware. Without a
+// Localizer in ctx the key itself is sent.
+func (r *Responder) SendLocalized(
+	ctx context.Context,
+	target ChatTarget,
+	key string,
+	data any,
+	opts ...SendTextOption,
+) (*client.Message, error) {
+	return r.SendText(ctx, target, i18n.FromContext(ctx).T(key, data), opts...)
+}
+
+// ReplyLocalized sends the translation of key as a reply to source.
+func (r *Responder) ReplyLocalized(
+	ctx context.Context,
+	source *client.Message,
+	key string,
+	data any,
+	opts ...SendTextOption,
+) (*client.Message, error) {
+	return r.ReplyText(ctx, source, i18n.FromContext(ctx).T(key, data), opts...)
+}
+
+// AnswerCallbackLocalized answers a callback query with the translation of
+// key as a notification.
+func (r *Responder) AnswerCallbackLocalized(
+	ctx context.Context,
+	query *client.CallbackQuery,
+	key string,
+	data any,
+	opts ...AnswerCallbackOption,
+) error {
+	return r.AnswerCallbackText(ctx, query, i18n.FromContext(ctx).T(key, data), opts...)
+}
@@ -0,0 +1,541 @@
+package respond
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/botapi"
+)
+
+const (
+	minMediaGroupSize = 2
+	maxMediaGroupSize = 10
+)
+
+// MediaRequest holds the optional parameters of a media send built by Responder.
+type MediaRequest struct {
+	Caption               *string
+	ParseMode             *string
+	CaptionEntities       []client.MessageEntity
+	ShowCaptionAboveMedia bool
+	HasSpoiler            bool
+	DisableNotification   bool
+	ProtectContent        bool
+	ReplyParameters       *client.ReplyParameters
+	// ReplyMarkup is any JSON-serializable keyboard, e.g. client.InlineKeyboardMarkup.
+	ReplyMarkup any
+	Thumbnail   *InputFile
+	// Duration, Width and Height describe videos, animations and audio. Zero
+	// values are not sent. Durations are sent in whole seconds.
+	Duration time.Duration
+	Width    int
+	Height   int
+	// Title and Performer describe audio.
+	Title     *string
+	Performer *string
+	// Emoji is the emoji of a sticker uploaded with the send.
+	Emoji             *string
+	SupportsStreaming bool
+	// Params holds parameters not wrapped here, keyed by their Bot API names.
+	Params map[string]any
+}
+
+// GroupMedia is one item of a media group.
+type GroupMedia struct {
+	kind string
+	file InputFile
+	req  MediaRequest
+}
+
+// GroupPhoto builds a photo item for SendMediaGroup.
+func GroupPhoto(file InputFile, opts ...SendMediaOption) GroupMedia {
+	return GroupMedia{kind: "photo", file: file, req: newMediaRequest(opts)}
+}
+
+// GroupVideo builds a video item for SendMediaGroup.
+func GroupVideo(file InputFile, opts ...SendMediaOption) GroupMedia {
+	return GroupMedia{kind: "video", file: file, req: newMediaRequest(opts)}
+}
+
+// GroupAudio builds an audio item for SendMediaGroup.
+func GroupAudio(file InputFile, opts ...SendMediaOption) GroupMedia {
+	return GroupMedia{kind: "audio", file: file, req: newMediaRequest(opts)}
+}
+
+// GroupDocument builds a document item for SendMediaGroup.
+func GroupDocument(file InputFile, opts ...SendMediaOption) GroupMedia {
+	return GroupMedia{kind: "document", file: file, req: newMediaRequest(opts)}
+}
+
+// AnimationMedia builds an animation item for EditMedia. Telegram does not
+// accept animations in media groups.
+func AnimationMedia(file InputFile, opts ...SendMediaOption) GroupMedia {
+	return GroupMedia{kind: "animation", file: file, req: newMediaRequest(opts)}
+}
+
+// SendPhoto sends a photo to the target.
+func (r *Responder) SendPhoto(
+	ctx context.Context,
+	target ChatTarget,
+	photo InputFile,
+	opts ...SendMediaOption,
+) (*client.Message, error) {
+	return r.sendMedia(ctx, "send photo", target, "photo", photo, opts)
+}
+
+// SendDocument sends a general file to the target.
+func (r *Responder) SendDocument(
+	ctx context.Context,
+	target ChatTarget,
+	document InputFile,
+	opts ...SendMediaOption,
+) (*client.Message, error) {
+	return r.sendMedia(ctx, "send document", target, "document", document, opts)
+}
+
+// SendVideo sends a video to the target.
+func (r *Responder) SendVideo(
+	ctx context.Context,
+	target ChatTarget,
+	video InputFile,
+	opts ...SendMediaOption,
+) (*client.Message, error) {
+	return r.sendMedia(ctx, "send video", target, "video", video, opts)
+}
+
+// SendAudio sends an audio file to the target.
+func (r *Responder) SendAudio(
+	ctx context.Context,
+	target ChatTarget,
+	audio InputFile,
+	opts ...SendMediaOption,
+) (*client.Message, error) {
+	return r.sendMedia(ctx, "send audio", target, "audio", audio, opts)
+}
+
+// SendVoice sends a voice note to the target.
+func (r *Responder) SendVoice(
+	ctx context.Context,
+	target ChatTarget,
+	voice InputFile,
+	opts ...SendMediaOption,
+) (*client.Message, error) {
+	return r.sendMedia(ctx, "send voice", target, "voice", voice, opts)
+}
+
+// SendAnimation sends a GIF or soundless video to the target.
+func (r *Responder) SendAnimation(
+	ctx context.Context,
+	target ChatTarget,
+	animation InputFile,
+	opts ...SendMediaOption,
+) (*client.Message, error) {
+	return r.sendMedia(ctx, "send animation", target, "animation", animation, opts)
+}
+
+// SendSticker sends a sticker to the target. Caption options are ignored by Telegram.
+func (r *Responder) SendSticker(
+	ctx context.Context,
+	target ChatTarget,
+	sticker InputFile,
+	opts ...SendMediaOption,
+) (*client.Message, error) {
+	return r.sendMedia(ctx, "send sticker", target, "sticker", sticker, opts)
+}
+
+// SendMediaGroup sends 2-10 items as an album. Captions are set per item;
+// opts only apply delivery settings such as silence, protection and reply.
+func (r *Responder) SendMediaGroup(
+	ctx context.Context,
+	target ChatTarget,
+	media []GroupMedia,
+	opts ...SendMediaOption,
+) ([]client.Message, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
+	}
+
+	if len(media) < minMediaGroupSize || len(media) > maxMediaGroupSize {
+		return nil, fmt.Errorf("send media group: %w: got %d items", ErrInvalidMediaGroup, len(media))
+	}
+
+	form := newRequestForm()
+	target.applyToForm(form.values)
+
+	items, err := form.groupItems(media)
+	if err != nil {
+		return nil, fmt.Errorf("send media group: %w", err)
+	}
+
+	form.values["media"] = items
+
+	req := newMediaRequest(opts)
+	req.applyDelivery(form.values)
+	req.applyParams(form.values)
+
+	// Albums cannot carry a keyboard.
+	delete(form.values, "reply_markup")
+
+	messages, err := postForm[[]client.Message](ctx, "send media group", form,
+		bodyCallOf(r.api.SendMediaGroupWithBodyWithResponse))
+	if err != nil {
+		return nil, err
+	}
+
+	return *messages, nil
+}
+
+// SendLongCaption sends media whose caption may be longer than Telegram's
+// caption limit. The media is an item built by GroupPhoto, GroupVideo,
+// GroupAudio, GroupDocument or AnimationMedia with its caption, and opts apply
+// over it. The caption is split like SendLongText splits text: the first part
+// goes on the media and the others follow as text messages. Reply parameters
+// apply to the media and reply markup to the last message. On failure it
+// returns the messages sent so far with the error.
+func (r *Responder) SendLongCaption(
+	ctx context.Context,
+	target ChatTarget,
+	media GroupMedia,
+	opts ...SendMediaOption,
+) ([]client.Message, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
+	}
+
+	op := "send " + media.kind
+
+	if media.file.isZero() {
+		return nil, fmt.Errorf("%s: %w", op, ErrEmptyInputFile)
+	}
+
+	req := media.req
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&req)
+		}
+	}
+
+	parts, err := req.captionParts()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	first := req
+	if len(parts) > 0 {
+		first.Caption = &parts[0].Text
+		first.CaptionEntities = derefEntities(parts[0].Entities)
+	}
+
+	if len(parts) > 1 {
+		first.ReplyMarkup = nil
+	}
+
+	message, err := r.sendMediaRequest(ctx, op, target, media.kind, media.file, first)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.sendCaptionOverflow(ctx, target, *message, req, parts)
+}
+
+// sendCaptionOverflow sends the caption parts after the first as text
+// messages following message, the media they belong to.
+func (r *Responder) sendCaptionOverflow(
+	ctx context.Context,
+	target ChatTarget,
+	message client.Message,
+	req MediaRequest,
+	parts []messagePart,
+) ([]client.Message, error) {
+	messages := []client.Message{message}
+
+	for i := 1; i < len(parts); i++ {
+		body := client.SendMessageJSONRequestBody{
+			Text:      parts[i].Text,
+			Entities:  parts[i].Entities,
+			ParseMode: req.ParseMode,
+		}
+		target.applyTo(&body)
+		// The media went to the new chat after a migration.
+		body.ChatId = message.Chat.Id
+
+		if req.DisableNotification {
+			WithSilent()(&body)
+		}
+
+		if req.ProtectContent {
+			WithProtectedContent()(&body)
+		}
+
+		if i == len(parts)-1 && req.ReplyMarkup != nil {
+			WithReplyMarkup(req.ReplyMarkup)(&body)
+		}
+
+		sent, err := r.sendMessageBody(ctx, body)
+		if err != nil {
+			return messages, fmt.Errorf("send caption part %d of %d: %w", i+1, len(parts), err)
+		}
+
+		messages = append(messages, *sent)
+	}
+
+	return messages, nil
+}
+
+// mediaCall returns the call that sends a file of kind, the request field it
+// is uploaded as, such as "photo".
+func (r *Responder) mediaCall(kind string) bodyCall {
+	switch kind {
+	case "photo":
+		return bodyCallOf(r.api.SendPhotoWithBodyWithResponse)
+	case "video":
+		return bodyCallOf(r.api.SendVideoWithBodyWithResponse)
+	case "audio":
+		return bodyCallOf(r.api.SendAudioWithBodyWithResponse)
+	case "animation":
+		return bodyCallOf(r.api.SendAnimationWithBodyWithResponse)
+	case "voice":
+		return bodyCallOf(r.api.SendVoiceWithBodyWithResponse)
+	case "sticker":
+		return bodyCallOf(r.api.SendStickerWithBodyWithResponse)
+	default:
+		return bodyCallOf(r.api.SendDocumentWithBodyWithResponse)
+	}
+}
+
+// groupItems encodes media group items, attaching uploads as file0, file1, ...
+func (f *requestForm) groupItems(media []GroupMedia) ([]map[string]any, error) {
+	items := make([]map[string]any, 0, len(media))
+
+	for i, item := range media {
+		if item.file.isZero() {
+			return nil, fmt.Errorf("item %d: %w", i, ErrEmptyInputFile)
+		}
+
+		values := map[string]any{
+			"type":  item.kind,
+			"media": f.attachFile("file"+strconv.Itoa(i), item.file),
+		}
+		item.req.applyCaption(values)
+		item.req.applyAttributes(values)
+		item.req.applyParams(values)
+
+		if item.req.Thumbnail != nil {
+			values["thumbnail"] = f.attachFile("thumbnail"+strconv.Itoa(i), *item.req.Thumbnail)
+		}
+
+		items = append(items, values)
+	}
+
+	return items, nil
+}
+
+func (r *Responder) sendMedia(
+	ctx context.Context,
+	op string,
+	target ChatTarget,
+	field string,
+	file InputFile,
+	opts []SendMediaOption,
+) (*client.Message, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
+	}
+
+	if file.isZero() {
+		return nil, fmt.Errorf("%s: %w", op, ErrEmptyInputFile)
+	}
+
+	return r.sendMediaRequest(ctx, op, target, field, file, newMediaRequest(opts))
+}
+
+func (r *Responder) sendMediaRequest(
+	ctx context.Context,
+	op string,
+	target ChatTarget,
+	field string,
+	file InputFile,
+	req MediaRequest,
+) (*client.Message, error) {
+	form := newRequestForm()
+	target.applyToForm(form.values)
+	form.setFile(field, file)
+	req.applyCaption(form.values)
+	req.applyAttributes(form.values)
+	req.applyDelivery(form.values)
+	req.applyParams(form.values)
+
+	if req.Thumbnail != nil {
+		form.values["thumbnail"] = form.attachFile("thumbnail", *req.Thumbnail)
+	}
+
+	return postForm[client.Message](ctx, op, form, r.mediaCall(field))
+}
+
+// apiResponse is the Bot API response envelope.
+type apiResponse[T any] struct {
+	Ok     bool `json:"ok"`
+	Result T    `json:"result"`
+
+	status string
+	err    *botapi.APIError
+}
+
+func postForm[T any](ctx context.Context, op string, form *requestForm, call bodyCall) (*T, error) {
+	resp, err := postFormOnce[T](ctx, op, form, call)
+	if err != nil {
+		return nil, err
+	}
+
+	// Uploaded readers are consumed by the first attempt, so only requests made
+	// of file references can be resent to a migrated chat.
+	if resp.err != nil && resp.err.MigrateToChatID != 0 && !form.hasUploads() {
+		chatID := resp.err.MigrateToChatID
+		if chatID != form.values["chat_id"] {
+			form.values["chat_id"] = chatID
+
+			resp, err = postFormOnce[T](ctx, op, form, call)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if resp.err != nil {
+		return nil, fmt.Errorf("%s: %w", op, resp.err)
+	}
+
+	if !resp.Ok {
+		return nil, fmt.Errorf("%s: unexpected response: %s", op, resp.status)
+	}
+
+	return &resp.Result, nil
+}
+
+func postFormOnce[T any](ctx context.Context, op string, form *requestForm, call bodyCall) (*apiResponse[T], error) {
+	body, httpResp, err := form.post(ctx, call)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if httpResp == nil {
+		return nil, fmt.Errorf("%s: empty response", op)
+	}
+
+	resp := &apiResponse[T]{status: httpResp.Status, err: botapi.FromResponse(httpResp.StatusCode, body)}
+	if resp.err != nil {
+		return resp, nil
+	}
+
+	// A malformed result leaves Ok false and is reported as an unexpected response.
+	_ = json.Unmarshal(body, resp)
+
+	return resp, nil
+}
+
+func newMediaRequest(opts []SendMediaOption) MediaRequest {
+	var req MediaRequest
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&req)
+		}
+	}
+
+	return req
+}
+
+// captionParts splits the caption for SendLongCaption.
+func (req *MediaRequest) captionParts() ([]messagePart, error) {
+	if req.Caption == nil {
+		return nil, nil
+	}
+
+	parts, err := splitText(*req.Caption, req.ParseMode, req.CaptionEntities, true)
+	if err != nil {
+		return nil, fmt.Errorf("split caption: %w", err)
+	}
+
+	return parts, nil
+}
+
+func derefEntities(entities *[]client.MessageEntity) []client.MessageEntity {
+	if entities == nil {
+		return nil
+	}
+
+	return *entities
+}
+
+func (req *MediaRequest) applyCaption(values map[string]any) {
+	if req.Caption != nil {
+		values["caption"] = *req.Caption
+	}
+
+	if req.ParseMode != nil {
+		values["parse_mode"] = *req.ParseMode
+	}
+
+	if len(req.CaptionEntities) > 0 {
+		values["caption_entities"] = req.CaptionEntities
+	}
+
+	setFlag(values, "show_caption_above_media", req.ShowCaptionAboveMedia)
+	setFlag(values, "has_spoiler", req.HasSpoiler)
+}
+
+func (req *MediaRequest) applyAttributes(values map[string]any) {
+	setPositive(values, "duration", int(req.Duration/time.Second))
+	setPositive(values, "width", req.Width)
+	setPositive(values, "height", req.Height)
+
+	if req.Title != nil {
+		values["title"] = *req.Title
+	}
+
+	if req.Performer != nil {
+		values["performer"] = *req.Performer
+	}
+
+	if req.Emoji != nil {
+		values["emoji"] = *req.Emoji
+	}
+
+	setFlag(values, "supports_streaming", req.SupportsStreaming)
+}
+
+func (req *MediaRequest) applyDelivery(values map[string]any) {
+	setFlag(values, "disable_notification", req.DisableNotification)
+	setFlag(values, "protect_content", req.ProtectContent)
+
+	if req.ReplyParameters != nil {
+		values["reply_parameters"] = req.ReplyParameters
+	}
+
+	if req.ReplyMarkup != nil {
+		values["reply_markup"] = req.ReplyMarkup
+	}
+}
+
+func (req *MediaRequest) applyParams(values map[string]any) {
+	for name, value := range req.Params {
+		values[name] = value
+	}
+}
+
+func setFlag(values map[string]any, name string, value bool) {
+	if value {
+		values[name] = true
+	}
+}
+
+func setPositive(values map[string]any, name string, value int) {
+	if value > 0 {
+		values[name] = value
+	}
+}
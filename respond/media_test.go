@@ -0,0 +1,432 @@
+package respond_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/botapi"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+type mediaMockClient struct {
+	client.ClientWithResponsesInterface
+	bodyFunc func(method, contentType string, body []byte) (int, string)
+}
+
+func (m *mediaMockClient) call(method, contentType string, body io.Reader) ([]byte, *http.Response, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	code, respBody := m.bodyFunc(method, contentType, data)
+
+	return []byte(respBody), &http.Response{StatusCode: code, Status: http.StatusText(code)}, nil
+}
+
+func (m *mediaMockClient) SendPhotoWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.SendPhotoResponse, error) {
+	data, httpResp, err := m.call("sendPhoto", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.SendPhotoResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func (m *mediaMockClient) SendDocumentWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.SendDocumentResponse, error) {
+	data, httpResp, err := m.call("sendDocument", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.SendDocumentResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func (m *mediaMockClient) SendMediaGroupWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.SendMediaGroupResponse, error) {
+	data, httpResp, err := m.call("sendMediaGroup", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.SendMediaGroupResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func TestResponderSendPhotoByFileID(t *testing.T) {
+	t.Parallel()
+
+	var gotContentType string
+	var got map[string]any
+	responder := respond.New(&mediaMockClient{
+		bodyFunc: func(_ string, contentType string, body []byte) (int, string) {
+			gotContentType = contentType
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Errorf("Unmarshal() unexpected error: %v", err)
+			}
+
+			return http.StatusOK, `{"ok":true,"result":{"message_id":5,"date":0,"chat":{"id":42,"type":"private"}}}`
+		},
+	})
+
+	threadID := 7
+	source := &client.Message{MessageId: 3}
+	msg, err := responder.SendPhoto(
+		context.Background(),
+		respond.ChatTarget{ChatID: 42, MessageThreadID: &threadID},
+		respond.FileID("photo-id"),
+		respond.WithCaption("<b>hi</b>"),
+		respond.WithCaptionHTML(),
+		respond.WithSpoiler(),
+		respond.WithMediaReplyTo(source),
+		respond.WithMediaParam("message_effect_id", "effect"),
+	)
+	if err != nil {
+		t.Fatalf("SendPhoto() unexpected error: %v", err)
+	}
+	if msg == nil || msg.MessageId != 5 {
+		t.Fatalf("SendPhoto() message=%v, want id 5", msg)
+	}
+	if gotContentType != "application/json" {
+		t.Fatalf("content type=%q, want application/json", gotContentType)
+	}
+
+	want := map[string]any{
+		"chat_id":           float64(42),
+		"message_thread_id": float64(7),
+		"photo":             "photo-id",
+		"caption":           "<b>hi</b>",
+		"parse_mode":        "HTML",
+		"has_spoiler":       true,
+		"message_effect_id": "effect",
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Fatalf("%s=%v, want %v", key, got[key], value)
+		}
+	}
+
+	reply, _ := got["reply_parameters"].(map[string]any)
+	if reply["message_id"] != float64(3) {
+		t.Fatalf("reply_parameters=%v, want message_id 3", got["reply_parameters"])
+	}
+}
+
+func TestResponderSendDocumentUploadsReader(t *testing.T) {
+	t.Parallel()
+
+	var fields map[string]string
+	var files map[string]string
+	responder := respond.New(&mediaMockClient{
+		bodyFunc: func(_ string, contentType string, body []byte) (int, string) {
+			fields, files = parseMultipart(t, contentType, body)
+
+			return http.StatusOK, `{"ok":true,"result":{"message_id":6,"date":0,"chat":{"id":42,"type":"private"}}}`
+		},
+	})
+
+	_, err := responder.SendDocument(
+		context.Background(),
+		respond.ChatTarget{ChatID: 42},
+		respond.FileReader("report.txt", strings.NewReader("report body")),
+		respond.WithCaption("report"),
+		respond.WithThumbnail(respond.FileReader("thumb.jpg", strings.NewReader("thumb body"))),
+		respond.WithMediaReplyMarkup(client.InlineKeyboardMarkup{}),
+	)
+	if err != nil {
+		t.Fatalf("SendDocument() unexpected error: %v", err)
+	}
+
+	if fields["chat_id"] != "42" {
+		t.Fatalf("chat_id=%q, want 42", fields["chat_id"])
+	}
+	if fields["caption"] != "report" {
+		t.Fatalf("caption=%q, want report", fields["caption"])
+	}
+	if fields["thumbnail"] != "attach://thumbnail" {
+		t.Fatalf("thumbnail=%q, want attach://thumbnail", fields["thumbnail"])
+	}
+	if fields["reply_markup"] != `{"inline_keyboard":null}` {
+		t.Fatalf("reply_markup=%q, want JSON keyboard", fields["reply_markup"])
+	}
+	if files["document"] != "report body" {
+		t.Fatalf("document=%q, want report body", files["document"])
+	}
+	if files["thumbnail"] != "thumb body" {
+		t.Fatalf("thumbnail file=%q, want thumb body", files["thumbnail"])
+	}
+}
+
+func TestResponderSendMediaGroup(t *testing.T) {
+	t.Parallel()
+
+	var fields map[string]string
+	var files map[string]string
+	responder := respond.New(&mediaMockClient{
+		bodyFunc: func(_ string, contentType string, body []byte) (int, string) {
+			fields, files = parseMultipart(t, contentType, body)
+
+			return http.StatusOK, `{"ok":true,"result":[{"message_id":1,"date":0,"chat":{"id":42,"type":"private"}},` +
+				`{"message_id":2,"date":0,"chat":{"id":42,"type":"private"}}]}`
+		},
+	})
+
+	messages, err := responder.SendMediaGroup(
+		context.Background(),
+		respond.ChatTarget{ChatID: 42},
+		[]respond.GroupMedia{
+			respond.GroupPhoto(respond.FileID("photo-id"), respond.WithCaption("first")),
+			respond.GroupVideo(respond.FileReader("clip.mp4", strings.NewReader("video body"))),
+		},
+		respond.WithMediaSilent(),
+	)
+	if err != nil {
+		t.Fatalf("SendMediaGroup() unexpected error: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("messages=%d, want 2", len(messages))
+	}
+	if fields["disable_notification"] != "true" {
+		t.Fatalf("disable_notification=%q, want true", fields["disable_notification"])
+	}
+
+	var media []map[string]any
+	if err := json.Unmarshal([]byte(fields["media"]), &media); err != nil {
+		t.Fatalf("Unmarshal(media) unexpected error: %v", err)
+	}
+	if len(media) != 2 {
+		t.Fatalf("media items=%d, want 2", len(media))
+	}
+	if media[0]["type"] != "photo" || media[0]["media"] != "photo-id" || media[0]["caption"] != "first" {
+		t.Fatalf("media[0]=%v, want photo-id with caption", media[0])
+	}
+	if media[1]["type"] != "video" || media[1]["media"] != "attach://file1" {
+		t.Fatalf("media[1]=%v, want attached video", media[1])
+	}
+	if files["file1"] != "video body" {
+		t.Fatalf("file1=%q, want video body", files["file1"])
+	}
+}
+
+func TestResponderSendPhotoRetriesMigratedChat(t *testing.T) {
+	t.Parallel()
+
+	var chatIDs []float64
+	responder := respond.New(&mediaMockClient{
+		bodyFunc: func(_ string, _ string, body []byte) (int, string) {
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Errorf("Unmarshal() unexpected error: %v", err)
+			}
+
+			chatID, _ := got["chat_id"].(float64)
+			chatIDs = append(chatIDs, chatID)
+			if len(chatIDs) == 1 {
+				return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"migrated",` +
+					`"parameters":{"migrate_to_chat_id":-100}}`
+			}
+
+			return http.StatusOK, `{"ok":true,"result":{"message_id":1,"date":0,"chat":{"id":-100,"type":"supergroup"}}}`
+		},
+	})
+
+	_, err := responder.SendPhoto(context.Background(), respond.ChatTarget{ChatID: -5}, respond.FileURL("https://x/y.png"))
+	if err != nil {
+		t.Fatalf("SendPhoto() unexpected error: %v", err)
+	}
+	if len(chatIDs) != 2 || chatIDs[0] != -5 || chatIDs[1] != -100 {
+		t.Fatalf("chat ids=%v, want [-5 -100]", chatIDs)
+	}
+}
+
+func TestResponderMediaErrors(t *testing.T) {
+	t.Parallel()
+
+	responder := respond.New(&mediaMockClient{
+		bodyFunc: func(_ string, _ string, _ []byte) (int, string) {
+			return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request"}`
+		},
+	})
+
+	if _, err := responder.SendPhoto(context.Background(), respond.ChatTarget{ChatID: 1}, respond.InputFile{}); !errors.Is(err, respond.ErrEmptyInputFile) {
+		t.Fatalf("SendPhoto(empty) error=%v, want ErrEmptyInputFile", err)
+	}
+
+	group := []respond.GroupMedia{respond.GroupPhoto(respond.FileID("a"))}
+	if _, err := responder.SendMediaGroup(context.Background(), respond.ChatTarget{ChatID: 1}, group); !errors.Is(err, respond.ErrInvalidMediaGroup) {
+		t.Fatalf("SendMediaGroup(1 item) error=%v, want ErrInvalidMediaGroup", err)
+	}
+
+	_, err := responder.SendPhoto(context.Background(), respond.ChatTarget{ChatID: 1}, respond.FileID("a"))
+
+	apiErr, ok := botapi.As(err)
+	if !ok || apiErr.ErrorCode != http.StatusBadRequest || !strings.HasPrefix(err.Error(), "send photo: ") {
+		t.Fatalf("SendPhoto() error=%v, want wrapped APIError 400", err)
+	}
+
+	var nilResponder *respond.Responder
+	if _, err := nilResponder.SendPhoto(context.Background(), respond.ChatTarget{}, respond.FileID("a")); !errors.Is(err, respond.ErrNilClient) {
+		t.Fatalf("nil SendPhoto() error=%v, want ErrNilClient", err)
+	}
+}
+
+func parseMultipart(t *testing.T, contentType string, body []byte) (map[string]string, map[string]string) {
+	t.Helper()
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Errorf("content type=%q, want multipart/form-data", contentType)
+
+		return nil, nil
+	}
+
+	fields := make(map[string]string)
+	files := make(map[string]string)
+	reader := multipart.NewReader(strings.NewReader(string(body)), params["boundary"])
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Errorf("NextPart() unexpected error: %v", err)
+
+			return nil, nil
+		}
+
+		data, _ := io.ReadAll(part)
+		if part.FileName() != "" {
+			files[part.FormName()] = string(data)
+		} else {
+			fields[part.FormName()] = string(data)
+		}
+	}
+
+	return fields, files
+}
+
+func TestResponderSendMediaGroupAttributes(t *testing.T) {
+	t.Parallel()
+
+	responder, calls := newRecordingResponder(t, `[{"message_id":1},{"message_id":2}]`)
+
+	_, err := responder.SendMediaGroup(context.Background(), respond.ChatTarget{ChatID: 7}, []respond.GroupMedia{
+		respond.GroupVideo(respond.FileID("video"),
+			respond.WithDuration(90*time.Second+500*time.Millisecond),
+			respond.WithDimensions(1280, 720),
+			respond.WithSupportsStreaming(),
+		),
+		respond.GroupAudio(respond.FileID("audio"),
+			respond.WithAudioTitle("Song"),
+			respond.WithAudioPerformer("Band"),
+		),
+	})
+	if err != nil {
+		t.Fatalf("SendMediaGroup() unexpected error: %v", err)
+	}
+
+	items, _ := (*calls)[0].values["media"].([]any)
+	if len(items) != 2 {
+		t.Fatalf("media=%v, want 2 items", (*calls)[0].values["media"])
+	}
+
+	video, _ := items[0].(map[string]any)
+	if video["duration"] != float64(90) || video["width"] != float64(1280) || video["height"] != float64(720) ||
+		video["supports_streaming"] != true {
+		t.Fatalf("video=%v, want a 90 seconds 1280x720 streamable video", video)
+	}
+
+	audio, _ := items[1].(map[string]any)
+	if audio["title"] != "Song" || audio["performer"] != "Band" || audio["duration"] != nil {
+		t.Fatalf("audio=%v, want title and performer only", audio)
+	}
+}
+
+func (m *mediaMockClient) SendMessageWithResponse(
+	_ context.Context,
+	body client.SendMessageJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.SendMessageResponse, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	data, httpResp, err := m.call("sendMessage", "application/json", bytes.NewReader(encoded))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &client.SendMessageResponse{Body: data, HTTPResponse: httpResp}
+	if httpResp.StatusCode == http.StatusOK {
+		if err := json.Unmarshal(data, &resp.JSON200); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+func TestResponderSendLongCaption(t *testing.T) {
+	t.Parallel()
+
+	responder, calls := newRecordingResponder(t, `{"message_id":1,"date":1,"chat":{"id":7,"type":"private"}}`)
+	caption := "<b>" + strings.Repeat("word ", 1000) + "</b>"
+	markup := &client.InlineKeyboardMarkup{InlineKeyboard: [][]client.InlineKeyboardButton{{{Text: "ok"}}}}
+
+	messages, err := responder.SendLongCaption(context.Background(), respond.ChatTarget{ChatID: 7},
+		respond.GroupPhoto(respond.FileID("photo"), respond.WithCaption(caption), respond.WithCaptionHTML()),
+		respond.WithMediaReplyMarkup(markup),
+		respond.WithMediaSilent(),
+	)
+	if err != nil {
+		t.Fatalf("SendLongCaption() unexpected error: %v", err)
+	}
+	if len(messages) != 2 || len(*calls) != 2 {
+		t.Fatalf("SendLongCaption() sent %d messages in %d calls, want 2", len(messages), len(*calls))
+	}
+
+	media, text := (*calls)[0], (*calls)[1]
+	if media.method != "sendPhoto" || media.values["parse_mode"] != "HTML" || media.values["reply_markup"] != nil {
+		t.Fatalf("media call=%v, want an HTML caption without markup", media.method)
+	}
+
+	first, _ := media.values["caption"].(string)
+	if !strings.HasPrefix(first, "<b>") || !strings.HasSuffix(first, "</b>") || len(first) > 1100 {
+		t.Fatalf("caption has %d bytes, want a balanced part within the caption limit", len(first))
+	}
+
+	if text.method != "sendMessage" || text.values["parse_mode"] != "HTML" ||
+		text.values["disable_notification"] != true || text.values["reply_markup"] == nil {
+		t.Fatalf("text call=%v %v, want a silent HTML message with the markup", text.method, text.values["parse_mode"])
+	}
+
+	rest, _ := text.values["text"].(string)
+	if strings.Count(first+rest, "word") != 1000 {
+		t.Fatalf("parts hold %d words, want 1000", strings.Count(first+rest, "word"))
+	}
+}
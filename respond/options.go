@@ -1,6 +1,12 @@
 package respond
 
-import "github.com/tgbotkit/client"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/format"
+)
 
 // SendTextOption configures a SendMessage request built by Responder.
 type SendTextOption func(*client.SendMessageJSONRequestBody)
@@ -22,6 +28,29 @@ func WithParseMode(mode string) SendTextOption {
 	}
 }
 
+// WithEntities sets message entities instead of a parse mode.
+func WithEntities(entities []client.MessageEntity) SendTextOption {
+	return func(body *client.SendMessageJSONRequestBody) {
+		body.ParseMode = nil
+		body.Entities = nil
+
+		if len(entities) > 0 {
+			body.Entities = &entities
+		}
+	}
+}
+
+// WithCommonMark converts the text from standard Markdown with
+// format.FromCommonMark and sends it with entities. Combined with
+// Responder.SendLongText, the converted text is split keeping its entities.
+func WithCommonMark() SendTextOption {
+	return func(body *client.SendMessageJSONRequestBody) {
+		text, entities := format.FromCommonMark(body.Text).Entities()
+		body.Text = text
+		WithEntities(entities)(body)
+	}
+}
+
 // WithSilent sends the message without notification.
 func WithSilent() SendTextOption {
 	return func(body *client.SendMessageJSONRequestBody) {
@@ -38,6 +67,22 @@ func WithProtectedContent() SendTextOption {
 	}
 }
 
+// WithReplyMarkup attaches a keyboard to the sent message: a
+// *client.InlineKeyboardMarkup or a markup from the keyboard package.
+// A nil markup, or one that cannot be encoded as JSON, leaves the request unchanged.
+func WithReplyMarkup(markup any) SendTextOption {
+	return func(body *client.SendMessageJSONRequestBody) {
+		// The generated request uses an anonymous union struct for reply_markup,
+		// so the markup is copied into it through its JSON form.
+		data, err := json.Marshal(markup)
+		if err != nil || string(data) == "null" {
+			return
+		}
+
+		_ = json.Unmarshal(data, &body.ReplyMarkup)
+	}
+}
+
 // WithSendMessagePatch applies advanced SendMessage options not wrapped here.
 func WithSendMessagePatch(patch func(*client.SendMessageJSONRequestBody)) SendTextOption {
 	return func(body *client.SendMessageJSONRequestBody) {
@@ -72,3 +117,489 @@ func WithAnswerCallbackPatch(patch func(*client.AnswerCallbackQueryJSONRequestBo
 		}
 	}
 }
+
+// SendMediaOption configures a media request built by Responder.
+type SendMediaOption func(*MediaRequest)
+
+// WithCaption sets the media caption.
+func WithCaption(caption string) SendMediaOption {
+	return func(req *MediaRequest) {
+		req.Caption = &caption
+	}
+}
+
+// WithCaptionHTML sets HTML parse mode for the caption.
+func WithCaptionHTML() SendMediaOption {
+	return WithCaptionParseMode("HTML")
+}
+
+// WithCaptionMarkdownV2 sets MarkdownV2 parse mode for the caption.
+func WithCaptionMarkdownV2() SendMediaOption {
+	return WithCaptionParseMode("MarkdownV2")
+}
+
+// WithCaptionParseMode sets Telegram parse mode for the caption.
+func WithCaptionParseMode(mode string) SendMediaOption {
+	return func(req *MediaRequest) {
+		req.ParseMode = &mode
+	}
+}
+
+// WithCaptionEntities sets caption entities instead of a parse mode.
+func WithCaptionEntities(entities []client.MessageEntity) SendMediaOption {
+	return func(req *MediaRequest) {
+		req.CaptionEntities = entities
+	}
+}
+
+// WithFormattedCaption sets a caption built with the format package.
+func WithFormattedCaption(caption format.Text) SendMediaOption {
+	return func(req *MediaRequest) {
+		text, entities := caption.Entities()
+		req.Caption = &text
+		req.ParseMode = nil
+		req.CaptionEntities = entities
+	}
+}
+
+// WithCaptionAboveMedia shows the caption above the media.
+func WithCaptionAboveMedia() SendMediaOption {
+	return func(req *MediaRequest) {
+		req.ShowCaptionAboveMedia = true
+	}
+}
+
+// WithSpoiler covers the media with a spoiler animation.
+func WithSpoiler() SendMediaOption {
+	return func(req *MediaRequest) {
+		req.HasSpoiler = true
+	}
+}
+
+// WithMediaSilent sends the media without notification.
+func WithMediaSilent() SendMediaOption {
+	return func(req *MediaRequest) {
+		req.DisableNotification = true
+	}
+}
+
+// WithMediaProtectedContent prevents forwarding and saving the sent media.
+func WithMediaProtectedContent() SendMediaOption {
+	return func(req *MediaRequest) {
+		req.ProtectContent = true
+	}
+}
+
+// WithMediaReplyTo sends the media as a reply to source.
+func WithMediaReplyTo(source *client.Message) SendMediaOption {
+	return func(req *MediaRequest) {
+		if source == nil {
+			return
+		}
+
+		messageID := source.MessageId
+		req.ReplyParameters = &client.ReplyParameters{
+			MessageId: &messageID,
+		}
+	}
+}
+
+// WithMediaReplyMarkup attaches a keyboard to the sent media.
+func WithMediaReplyMarkup(markup any) SendMediaOption {
+	return func(req *MediaRequest) {
+		req.ReplyMarkup = markup
+	}
+}
+
+// WithThumbnail sets the thumbnail of a document, video, audio or animation.
+func WithThumbnail(file InputFile) SendMediaOption {
+	return func(req *MediaRequest) {
+		req.Thumbnail = &file
+	}
+}
+
+// WithDuration sets the duration of a video, animation, audio or voice note.
+// It is sent in whole seconds.
+func WithDuration(duration time.Duration) SendMediaOption {
+	return func(req *MediaRequest) {
+		req.Duration = duration
+	}
+}
+
+// WithDimensions sets the width and height of a video or animation.
+func WithDimensions(width, height int) SendMediaOption {
+	return func(req *MediaRequest) {
+		req.Width = width
+		req.Height = height
+	}
+}
+
+// WithAudioTitle sets the track name of an audio file.
+func WithAudioTitle(title string) SendMediaOption {
+	return func(req *MediaRequest) {
+		req.Title = &title
+	}
+}
+
+// WithAudioPerformer sets the performer of an audio file.
+func WithAudioPerformer(performer string) SendMediaOption {
+	return func(req *MediaRequest) {
+		req.Performer = &performer
+	}
+}
+
+// WithStickerEmoji sets the emoji of a sticker uploaded with the send.
+func WithStickerEmoji(emoji string) SendMediaOption {
+	return func(req *MediaRequest) {
+		req.Emoji = &emoji
+	}
+}
+
+// WithSupportsStreaming marks a video as suitable for streaming.
+func WithSupportsStreaming() SendMediaOption {
+	return func(req *MediaRequest) {
+		req.SupportsStreaming = true
+	}
+}
+
+// WithMediaParam sets a media parameter not wrapped here, such as
+// message_effect_id, by its Bot API name.
+func WithMediaParam(name string, value any) SendMediaOption {
+	return func(req *MediaRequest) {
+		if req.Params == nil {
+			req.Params = make(map[string]any)
+		}
+
+		req.Params[name] = value
+	}
+}
+
+// EditOption configures an edit request built by Responder.
+type EditOption func(*EditRequest)
+
+// WithEditHTML sets HTML parse mode for the edited text or caption.
+func WithEditHTML() EditOption {
+	return WithEditParseMode("HTML")
+}
+
+// WithEditMarkdownV2 sets MarkdownV2 parse mode for the edited text or caption.
+func WithEditMarkdownV2() EditOption {
+	return WithEditParseMode("MarkdownV2")
+}
+
+// WithEditParseMode sets Telegram parse mode for the edited text or caption.
+func WithEditParseMode(mode string) EditOption {
+	return func(req *EditRequest) {
+		req.ParseMode = &mode
+	}
+}
+
+// WithEditEntities sets entities of the edited text or caption instead of a parse mode.
+func WithEditEntities(entities []client.MessageEntity) EditOption {
+	return func(req *EditRequest) {
+		req.Entities = entities
+	}
+}
+
+// WithEditCaptionAboveMedia shows the edited caption above the media.
+func WithEditCaptionAboveMedia() EditOption {
+	return func(req *EditRequest) {
+		req.ShowCaptionAboveMedia = true
+	}
+}
+
+// WithEditReplyMarkup sets the inline keyboard of the edited message.
+func WithEditReplyMarkup(markup *client.InlineKeyboardMarkup) EditOption {
+	return func(req *EditRequest) {
+		req.ReplyMarkup = markup
+	}
+}
+
+// WithEditParam sets an edit parameter not wrapped here by its Bot API name.
+func WithEditParam(name string, value any) EditOption {
+	return func(req *EditRequest) {
+		if req.Params == nil {
+			req.Params = make(map[string]any)
+		}
+
+		req.Params[name] = value
+	}
+}
+
+// PinOption configures a pinChatMessage request built by Responder.
+type PinOption func(map[string]any)
+
+// WithPinSilent pins the message without notifying chat members.
+func WithPinSilent() PinOption {
+	return func(values map[string]any) {
+		values["disable_notification"] = true
+	}
+}
+
+// CopyOption configures a forward or copy built by Responder.
+type CopyOption func(*CopyRequest)
+
+// WithCopyCaption replaces the caption of copied media.
+func WithCopyCaption(caption string) CopyOption {
+	return func(req *CopyRequest) {
+		req.Caption = &caption
+		req.RemoveCaption = false
+	}
+}
+
+// WithCopyCaptionHTML sets HTML parse mode for the new caption.
+func WithCopyCaptionHTML() CopyOption {
+	return WithCopyCaptionParseMode("HTML")
+}
+
+// WithCopyCaptionMarkdownV2 sets MarkdownV2 parse mode for the new caption.
+func WithCopyCaptionMarkdownV2() CopyOption {
+	return WithCopyCaptionParseMode("MarkdownV2")
+}
+
+// WithCopyCaptionParseMode sets Telegram parse mode for the new caption.
+func WithCopyCaptionParseMode(mode string) CopyOption {
+	return func(req *CopyRequest) {
+		req.ParseMode = &mode
+	}
+}
+
+// WithCopyCaptionEntities sets entities of the new caption instead of a parse mode.
+func WithCopyCaptionEntities(entities []client.MessageEntity) CopyOption {
+	return func(req *CopyRequest) {
+		req.CaptionEntities = entities
+	}
+}
+
+// WithCopyFormattedCaption replaces the caption of copied media with one
+// built with the format package.
+func WithCopyFormattedCaption(caption format.Text) CopyOption {
+	return func(req *CopyRequest) {
+		text, entities := caption.Entities()
+		req.Caption = &text
+		req.ParseMode = nil
+		req.CaptionEntities = entities
+		req.RemoveCaption = false
+	}
+}
+
+// WithoutCaption drops the caption of copied media.
+func WithoutCaption() CopyOption {
+	return func(req *CopyRequest) {
+		req.Caption = nil
+		req.RemoveCaption = true
+	}
+}
+
+// WithCopySilent forwards or copies without notification.
+func WithCopySilent() CopyOption {
+	return func(req *CopyRequest) {
+		req.DisableNotification = true
+	}
+}
+
+// WithCopyProtectedContent prevents forwarding and saving the new messages.
+func WithCopyProtectedContent() CopyOption {
+	return func(req *CopyRequest) {
+		req.ProtectContent = true
+	}
+}
+
+// WithCopyReplyTo sends the copy as a reply to source.
+func WithCopyReplyTo(source *client.Message) CopyOption {
+	return func(req *CopyRequest) {
+		if source == nil {
+			return
+		}
+
+		messageID := source.MessageId
+		req.ReplyParameters = &client.ReplyParameters{
+			MessageId: &messageID,
+		}
+	}
+}
+
+// WithCopyReplyMarkup attaches a keyboard to the copy.
+func WithCopyReplyMarkup(markup any) CopyOption {
+	return func(req *CopyRequest) {
+		req.ReplyMarkup = markup
+	}
+}
+
+// WithCopyCaptionAboveMedia shows the caption of copied media above it.
+func WithCopyCaptionAboveMedia() CopyOption {
+	return func(req *CopyRequest) {
+		req.ShowCaptionAboveMedia = true
+	}
+}
+
+// WithCopyVideoStart sets where the copied video starts playing, in whole
+// seconds.
+func WithCopyVideoStart(start time.Duration) CopyOption {
+	return func(req *CopyRequest) {
+		req.VideoStart = start
+	}
+}
+
+// WithCopyParam sets a forward or copy parameter not wrapped here by its Bot
+// API name, such as message_thread_id.
+func WithCopyParam(name string, value any) CopyOption {
+	return func(req *CopyRequest) {
+		if req.Params == nil {
+			req.Params = make(map[string]any)
+		}
+
+		req.Params[name] = value
+	}
+}
+
+// SendPollOption configures a poll or quiz built by Responder.
+type SendPollOption func(*PollRequest)
+
+// WithPollAnonymous sets whether the voters of the poll are hidden. Only the
+// answers to polls that are not anonymous are reported to the bot.
+func WithPollAnonymous(anonymous bool) SendPollOption {
+	return func(req *PollRequest) {
+		req.Anonymous = &anonymous
+	}
+}
+
+// WithMultipleAnswers lets voters choose several options.
+func WithMultipleAnswers() SendPollOption {
+	return func(req *PollRequest) {
+		req.AllowsMultipleAnswers = true
+	}
+}
+
+// WithQuizExplanation sets the text shown when a quiz is answered incorrectly.
+func WithQuizExplanation(explanation string) SendPollOption {
+	return func(req *PollRequest) {
+		req.Explanation = &explanation
+	}
+}
+
+// WithQuizExplanationParseMode sets Telegram parse mode for the quiz explanation.
+func WithQuizExplanationParseMode(mode string) SendPollOption {
+	return func(req *PollRequest) {
+		req.ExplanationParseMode = &mode
+	}
+}
+
+// WithFormattedQuizExplanation sets a quiz explanation built with the format
+// package.
+func WithFormattedQuizExplanation(explanation format.Text) SendPollOption {
+	return func(req *PollRequest) {
+		text, entities := explanation.Entities()
+		req.Explanation = &text
+		req.ExplanationParseMode = nil
+		req.ExplanationEntities = entities
+	}
+}
+
+// WithPollOpenPeriod closes the poll after period, from 5 to 600 seconds.
+func WithPollOpenPeriod(period time.Duration) SendPollOption {
+	return func(req *PollRequest) {
+		req.OpenPeriod = period
+		req.CloseDate = time.Time{}
+	}
+}
+
+// WithPollCloseDate closes the poll at date, 5 to 600 seconds in the future.
+func WithPollCloseDate(date time.Time) SendPollOption {
+	return func(req *PollRequest) {
+		req.CloseDate = date
+		req.OpenPeriod = 0
+	}
+}
+
+// WithPollSilent sends the poll without notification.
+func WithPollSilent() SendPollOption {
+	return func(req *PollRequest) {
+		req.DisableNotification = true
+	}
+}
+
+// WithPollProtectedContent prevents forwarding and saving the poll.
+func WithPollProtectedContent() SendPollOption {
+	return func(req *PollRequest) {
+		req.ProtectContent = true
+	}
+}
+
+// WithPollReplyTo sends the poll as a reply to source.
+func WithPollReplyTo(source *client.Message) SendPollOption {
+	return func(req *PollRequest) {
+		if source == nil {
+			return
+		}
+
+		messageID := source.MessageId
+		req.ReplyParameters = &client.ReplyParameters{
+			MessageId: &messageID,
+		}
+	}
+}
+
+// WithPollReplyMarkup attaches a keyboard to the poll.
+func WithPollReplyMarkup(markup any) SendPollOption {
+	return func(req *PollRequest) {
+		req.ReplyMarkup = markup
+	}
+}
+
+// WithQuestionParseMode sets Telegram parse mode for the question. Telegram
+// only allows custom emoji in questions.
+func WithQuestionParseMode(mode string) SendPollOption {
+	return func(req *PollRequest) {
+		req.QuestionParseMode = &mode
+	}
+}
+
+// WithQuestionEntities sets entities of the question instead of a parse mode.
+func WithQuestionEntities(entities []client.MessageEntity) SendPollOption {
+	return func(req *PollRequest) {
+		req.QuestionEntities = entities
+	}
+}
+
+// WithPollRevoting sets whether voters can change their answers. Telegram
+// allows it by default for regular polls and not for quizzes.
+func WithPollRevoting(allowed bool) SendPollOption {
+	return func(req *PollRequest) {
+		req.AllowsRevoting = &allowed
+	}
+}
+
+// WithShuffledOptions shows the options of the poll in random order.
+func WithShuffledOptions() SendPollOption {
+	return func(req *PollRequest) {
+		req.ShuffleOptions = true
+	}
+}
+
+// WithResultsHiddenUntilClosed shows the results of the poll only once it is
+// closed.
+func WithResultsHiddenUntilClosed() SendPollOption {
+	return func(req *PollRequest) {
+		req.HideResultsUntilClosed = true
+	}
+}
+
+// WithPollClosed sends the poll already closed, as a preview.
+func WithPollClosed() SendPollOption {
+	return func(req *PollRequest) {
+		req.Closed = true
+	}
+}
+
+// WithPollParam sets a poll parameter not wrapped here by its Bot API name,
+// such as message_thread_id.
+func WithPollParam(name string, value any) SendPollOption {
+	return func(req *PollRequest) {
+		if req.Params == nil {
+			req.Params = make(map[string]any)
+		}
+
+		req.Params[name] = value
+	}
+}
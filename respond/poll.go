@@ -0,0 +1,213 @@
+package respond
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tgbotkit/client"
+)
+
+const (
+	minPollOptions = 2
+	maxPollOptions = 12
+)
+
+// PollRequest holds the optional parameters of a poll or quiz built by Responder.
+type PollRequest struct {
+	// Anonymous hides the voters. When nil, Telegram's default of an
+	// anonymous poll applies.
+	Anonymous             *bool
+	AllowsMultipleAnswers bool
+	// QuestionParseMode and QuestionEntities format the question.
+	QuestionParseMode *string
+	QuestionEntities  []client.MessageEntity
+	// AllowsRevoting lets voters change their answers. When nil, Telegram
+	// allows it for regular polls and not for quizzes.
+	AllowsRevoting         *bool
+	ShuffleOptions         bool
+	HideResultsUntilClosed bool
+	// Closed sends the poll already closed, as a preview.
+	Closed bool
+	// Explanation is shown when a quiz is answered incorrectly.
+	Explanation          *string
+	ExplanationParseMode *string
+	ExplanationEntities  []client.MessageEntity
+	// OpenPeriod closes the poll this long after it is sent, from 5 to 600
+	// seconds. CloseDate closes it at a point in time instead.
+	OpenPeriod          time.Duration
+	CloseDate           time.Time
+	DisableNotification bool
+	ProtectContent      bool
+	ReplyParameters     *client.ReplyParameters
+	// ReplyMarkup is any JSON-serializable keyboard, e.g. client.InlineKeyboardMarkup.
+	ReplyMarkup any
+	// Params holds parameters not wrapped here, keyed by their Bot API names.
+	Params map[string]any
+}
+
+// SendPoll sends a regular poll with 2 to 12 options. Polls are anonymous
+// unless WithPollAnonymous(false) is given; only the answers to public polls
+// are reported to the bot.
+func (r *Responder) SendPoll(
+	ctx context.Context,
+	target ChatTarget,
+	question string,
+	options []string,
+	opts ...SendPollOption,
+) (*client.Message, error) {
+	return r.sendPoll(ctx, "send poll", target, question, options, nil, opts)
+}
+
+// SendQuiz sends a quiz with 2 to 12 options, of which the one at index
+// correct is the right answer.
+func (r *Responder) SendQuiz(
+	ctx context.Context,
+	target ChatTarget,
+	question string,
+	options []string,
+	correct int,
+	opts ...SendPollOption,
+) (*client.Message, error) {
+	if correct < 0 || correct >= len(options) {
+		return nil, fmt.Errorf("send quiz: %w: correct option %d out of range", ErrInvalidPoll, correct)
+	}
+
+	return r.sendPoll(ctx, "send quiz", target, question, options, []int{correct}, opts)
+}
+
+// StopPoll closes a poll the bot sent and returns its final state.
+func (r *Responder) StopPoll(ctx context.Context, ref MessageRef) (*client.Poll, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
+	}
+
+	if ref.InlineMessageID != "" || ref.MessageID == 0 {
+		return nil, fmt.Errorf("stop poll: %w", ErrNoMessageTarget)
+	}
+
+	form := newRequestForm()
+	ref.applyToForm(form.values)
+
+	return postForm[client.Poll](ctx, "stop poll", form,
+		bodyCallOf(r.api.StopPollWithBodyWithResponse))
+}
+
+// sendPoll sends a quiz when correct is set, and a regular poll otherwise.
+func (r *Responder) sendPoll(
+	ctx context.Context,
+	op string,
+	target ChatTarget,
+	question string,
+	options []string,
+	correct []int,
+	opts []SendPollOption,
+) (*client.Message, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
+	}
+
+	if len(options) < minPollOptions || len(options) > maxPollOptions {
+		return nil, fmt.Errorf("%s: %w: %d options", op, ErrInvalidPoll, len(options))
+	}
+
+	pollOptions := make([]client.InputPollOption, 0, len(options))
+	for _, option := range options {
+		pollOptions = append(pollOptions, client.InputPollOption{Text: option})
+	}
+
+	form := newRequestForm()
+	target.applyToForm(form.values)
+	form.values["question"] = question
+	form.values["options"] = pollOptions
+	form.values["type"] = "regular"
+
+	if len(correct) > 0 {
+		form.values["type"] = "quiz"
+		form.values["correct_option_ids"] = correct
+	}
+
+	req := newPollRequest(opts)
+	req.apply(form.values)
+
+	return postForm[client.Message](ctx, op, form,
+		bodyCallOf(r.api.SendPollWithBodyWithResponse))
+}
+
+func newPollRequest(opts []SendPollOption) PollRequest {
+	var req PollRequest
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&req)
+		}
+	}
+
+	return req
+}
+
+func (req *PollRequest) apply(values map[string]any) {
+	if req.Anonymous != nil {
+		values["is_anonymous"] = *req.Anonymous
+	}
+
+	if req.QuestionParseMode != nil {
+		values["question_parse_mode"] = *req.QuestionParseMode
+	}
+
+	if len(req.QuestionEntities) > 0 {
+		values["question_entities"] = req.QuestionEntities
+	}
+
+	req.applyVoting(values)
+	req.applyExplanation(values)
+
+	setFlag(values, "disable_notification", req.DisableNotification)
+	setFlag(values, "protect_content", req.ProtectContent)
+
+	if req.ReplyParameters != nil {
+		values["reply_parameters"] = req.ReplyParameters
+	}
+
+	if req.ReplyMarkup != nil {
+		values["reply_markup"] = req.ReplyMarkup
+	}
+
+	for name, value := range req.Params {
+		values[name] = value
+	}
+}
+
+func (req *PollRequest) applyVoting(values map[string]any) {
+	setFlag(values, "allows_multiple_answers", req.AllowsMultipleAnswers)
+
+	if req.AllowsRevoting != nil {
+		values["allows_revoting"] = *req.AllowsRevoting
+	}
+
+	setFlag(values, "shuffle_options", req.ShuffleOptions)
+	setFlag(values, "hide_results_until_closes", req.HideResultsUntilClosed)
+	setFlag(values, "is_closed", req.Closed)
+
+	if req.OpenPeriod > 0 {
+		values["open_period"] = int(req.OpenPeriod / time.Second)
+	}
+
+	if !req.CloseDate.IsZero() {
+		values["close_date"] = req.CloseDate.Unix()
+	}
+}
+
+func (req *PollRequest) applyExplanation(values map[string]any) {
+	if req.Explanation != nil {
+		values["explanation"] = *req.Explanation
+	}
+
+	if req.ExplanationParseMode != nil {
+		values["explanation_parse_mode"] = *req.ExplanationParseMode
+	}
+
+	if len(req.ExplanationEntities) > 0 {
+		values["explanation_entities"] = req.ExplanationEntities
+	}
+}
@@ -0,0 +1,122 @@
+package respond_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+func (m *mediaMockClient) SendPollWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.SendPollResponse, error) {
+	data, httpResp, err := m.call("sendPoll", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.SendPollResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func (m *mediaMockClient) StopPollWithBodyWithResponse(
+	_ context.Context,
+	contentType string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.StopPollResponse, error) {
+	data, httpResp, err := m.call("stopPoll", contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.StopPollResponse{Body: data, HTTPResponse: httpResp}, nil
+}
+
+func TestResponderSendPoll(t *testing.T) {
+	t.Parallel()
+
+	responder, calls := newRecordingResponder(t,
+		`{"message_id":5,"date":1,"chat":{"id":7,"type":"group"},"poll":{"id":"p1","question":"Lunch?"}}`)
+
+	msg, err := responder.SendPoll(context.Background(), respond.ChatTarget{ChatID: 7}, "Lunch?",
+		[]string{"Pizza", "Sushi"},
+		respond.WithPollAnonymous(false),
+		respond.WithMultipleAnswers(),
+		respond.WithPollOpenPeriod(time.Minute),
+		respond.WithPollRevoting(false),
+		respond.WithShuffledOptions(),
+	)
+	if err != nil {
+		t.Fatalf("SendPoll() unexpected error: %v", err)
+	}
+	if msg.Poll == nil || msg.Poll.Id != "p1" {
+		t.Fatalf("SendPoll() poll=%v, want p1", msg.Poll)
+	}
+
+	got := (*calls)[0]
+	if got.method != "sendPoll" || got.values["type"] != "regular" || got.values["is_anonymous"] != false ||
+		got.values["allows_multiple_answers"] != true || got.values["open_period"] != float64(60) ||
+		got.values["allows_revoting"] != false || got.values["shuffle_options"] != true {
+		t.Fatalf("call=%v, want a public shuffled multiple answer poll open for 60 seconds without revoting", got)
+	}
+
+	options, _ := got.values["options"].([]any)
+	if len(options) != 2 || options[1].(map[string]any)["text"] != "Sushi" {
+		t.Fatalf("options=%v, want Pizza and Sushi", options)
+	}
+
+	_, err = responder.SendPoll(context.Background(), respond.ChatTarget{ChatID: 7}, "Lunch?", []string{"Pizza"})
+	if !errors.Is(err, respond.ErrInvalidPoll) {
+		t.Fatalf("SendPoll(one option) error=%v, want ErrInvalidPoll", err)
+	}
+}
+
+func TestResponderSendQuiz(t *testing.T) {
+	t.Parallel()
+
+	responder, calls := newRecordingResponder(t, `{"message_id":5,"date":1,"chat":{"id":7,"type":"group"}}`)
+
+	if _, err := responder.SendQuiz(context.Background(), respond.ChatTarget{ChatID: 7}, "2+2?",
+		[]string{"3", "4", "5"}, 1, respond.WithQuizExplanation("Count again")); err != nil {
+		t.Fatalf("SendQuiz() unexpected error: %v", err)
+	}
+
+	got := (*calls)[0]
+	correct, _ := got.values["correct_option_ids"].([]any)
+	if got.values["type"] != "quiz" || len(correct) != 1 || correct[0] != float64(1) ||
+		got.values["explanation"] != "Count again" {
+		t.Fatalf("call=%v, want a quiz with option 1 correct", got)
+	}
+
+	_, err := responder.SendQuiz(context.Background(), respond.ChatTarget{ChatID: 7}, "2+2?", []string{"3", "4"}, 2)
+	if !errors.Is(err, respond.ErrInvalidPoll) {
+		t.Fatalf("SendQuiz(correct out of range) error=%v, want ErrInvalidPoll", err)
+	}
+}
+
+func TestResponderStopPoll(t *testing.T) {
+	t.Parallel()
+
+	responder, calls := newRecordingResponder(t,
+		`{"id":"p1","question":"Lunch?","is_closed":true,"options":[{"text":"Pizza","voter_count":3}]}`)
+
+	poll, err := responder.StopPoll(context.Background(), respond.MessageRef{ChatID: 7, MessageID: 5})
+	if err != nil {
+		t.Fatalf("StopPoll() unexpected error: %v", err)
+	}
+	if !poll.IsClosed || poll.Options[0].VoterCount != 3 {
+		t.Fatalf("StopPoll() poll=%+v, want the closed poll", poll)
+	}
+
+	got := (*calls)[0]
+	if got.method != "stopPoll" || got.values["chat_id"] != float64(7) || got.values["message_id"] != float64(5) {
+		t.Fatalf("call=%v, want stopPoll of 7/5", got)
+	}
+}
@@ -3,8 +3,12 @@ package respond
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/botapi"
+	"github.com/tgbotkit/runtime/format"
+	"github.com/tgbotkit/runtime/textsplit"
 )
 
 // Responder sends common Telegram responses through the generated client.
@@ -34,20 +38,74 @@ func (r *Responder) SendText(
 	target.applyTo(&body)
 	applySendTextOptions(&body, opts)
 
-	resp, err := r.api.SendMessageWithResponse(ctx, body)
-	if err != nil {
-		return nil, fmt.Errorf("send message: %w", err)
+	return r.sendMessageBody(ctx, body)
+}
+
+// SendFormatted sends text built with the format package. It is sent as
+// entities, so options setting a parse mode are ignored.
+func (r *Responder) SendFormatted(
+	ctx context.Context,
+	target ChatTarget,
+	text format.Text,
+	opts ...SendTextOption,
+) (*client.Message, error) {
+	plain, entities := text.Entities()
+
+	return r.SendText(ctx, target, plain, append(slices.Clip(opts), WithEntities(entities))...)
+}
+
+// SendLongText sends text longer than Telegram's message limit as several
+// messages. The text is split on paragraph, line and word boundaries, keeping
+// HTML, MarkdownV2 and entity formatting balanced in every part. Reply
+// parameters apply to the first part and reply markup to the last. On failure
+// it returns the messages sent so far with the error.
+func (r *Responder) SendLongText(
+	ctx context.Context,
+	target ChatTarget,
+	text string,
+	opts ...SendTextOption,
+) ([]client.Message, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
 	}
 
-	if resp == nil {
-		return nil, fmt.Errorf("send message: empty response")
+	template := client.SendMessageJSONRequestBody{
+		Text: text,
 	}
+	target.applyTo(&template)
+	applySendTextOptions(&template, opts)
 
-	if resp.JSON200 == nil || !bool(resp.JSON200.Ok) {
-		return nil, fmt.Errorf("send message: unexpected response: %s", resp.Status())
+	parts, err := splitMessage(template)
+	if err != nil {
+		return nil, err
 	}
 
-	return &resp.JSON200.Result, nil
+	messages := make([]client.Message, 0, len(parts))
+
+	for i, part := range parts {
+		body := template
+		body.Text = part.Text
+		body.Entities = part.Entities
+
+		if i > 0 {
+			body.ReplyParameters = nil
+		}
+
+		if i < len(parts)-1 {
+			body.ReplyMarkup = nil
+		}
+
+		message, err := r.sendMessageBody(ctx, body)
+		if err != nil {
+			return messages, fmt.Errorf("send part %d of %d: %w", i+1, len(parts), err)
+		}
+
+		// Later parts go straight to the new chat after a migration.
+		template.ChatId = message.Chat.Id
+		messages = append(messages, *message)
+	}
+
+	return messages, nil
 }
 
 // SendTextInChat sends a text message to the same chat context as source.
@@ -117,6 +175,10 @@ func (r *Responder) AnswerCallback(
 		return fmt.Errorf("answer callback query: empty response")
 	}
 
+	if apiErr := responseError(resp.JSON200 != nil, resp.StatusCode(), resp.Body); apiErr != nil {
+		return fmt.Errorf("answer callback query: %w", apiErr)
+	}
+
 	if resp.JSON200 == nil || !bool(resp.JSON200.Ok) || !resp.JSON200.Result {
 		return fmt.Errorf("answer callback query: unexpected response: %s", resp.Status())
 	}
@@ -158,6 +220,137 @@ func (r *Responder) AnswerCallbackAlert(
 	return r.AnswerCallback(ctx, query, opts...)
 }
 
+// messagePart is one part of a long text with its entities.
+type messagePart struct {
+	Text     string
+	Entities *[]client.MessageEntity
+}
+
+func splitMessage(body client.SendMessageJSONRequestBody) ([]messagePart, error) {
+	parts, err := splitText(body.Text, body.ParseMode, derefEntities(body.Entities), false)
+	if err != nil {
+		return nil, fmt.Errorf("split message: %w", err)
+	}
+
+	return parts, nil
+}
+
+// splitText splits text formatted with parseMode or entities into message
+// parts. A caption's first part fits the caption limit instead.
+func splitText(text string, parseMode *string, entities []client.MessageEntity, caption bool) ([]messagePart, error) {
+	if parseMode == nil || *parseMode == "" {
+		return splitEntities(text, entities, caption), nil
+	}
+
+	texts, err := splitMarkup(text, *parseMode, caption)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]messagePart, 0, len(texts))
+	for _, text := range texts {
+		parts = append(parts, messagePart{Text: text})
+	}
+
+	return parts, nil
+}
+
+func splitMarkup(text, mode string, caption bool) ([]string, error) {
+	switch {
+	case mode == "HTML" && caption:
+		return textsplit.CaptionHTML(text), nil
+	case mode == "HTML":
+		return textsplit.HTML(text, textsplit.MessageLimit), nil
+	case mode == "MarkdownV2" && caption:
+		return textsplit.CaptionMarkdownV2(text), nil
+	case mode == "MarkdownV2":
+		return textsplit.MarkdownV2(text, textsplit.MessageLimit), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedParseMode, mode)
+	}
+}
+
+func splitEntities(text string, entities []client.MessageEntity, caption bool) []messagePart {
+	var chunks []textsplit.Chunk
+	if caption {
+		chunks = textsplit.CaptionEntities(text, entities)
+	} else {
+		chunks = textsplit.Entities(text, entities, textsplit.MessageLimit)
+	}
+
+	parts := make([]messagePart, 0, len(chunks))
+
+	for _, chunk := range chunks {
+		part := messagePart{Text: chunk.Text}
+		if len(chunk.Entities) > 0 {
+			part.Entities = &chunk.Entities
+		}
+
+		parts = append(parts, part)
+	}
+
+	return parts
+}
+
+func (r *Responder) sendMessageBody(
+	ctx context.Context,
+	body client.SendMessageJSONRequestBody,
+) (*client.Message, error) {
+	resp, err := r.sendMessage(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// A group upgraded to a supergroup rejects sends to its old ID and reports
+	// the new one; retry once there.
+	if apiErr := responseError(resp.JSON200 != nil, resp.StatusCode(), resp.Body); apiErr != nil &&
+		apiErr.MigrateToChatID != 0 && apiErr.MigrateToChatID != body.ChatId {
+		body.ChatId = apiErr.MigrateToChatID
+
+		resp, err = r.sendMessage(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if apiErr := responseError(resp.JSON200 != nil, resp.StatusCode(), resp.Body); apiErr != nil {
+		return nil, fmt.Errorf("send message: %w", apiErr)
+	}
+
+	if resp.JSON200 == nil || !bool(resp.JSON200.Ok) {
+		return nil, fmt.Errorf("send message: unexpected response: %s", resp.Status())
+	}
+
+	return &resp.JSON200.Result, nil
+}
+
+func (r *Responder) sendMessage(
+	ctx context.Context,
+	body client.SendMessageJSONRequestBody,
+) (*client.SendMessageResponse, error) {
+	resp, err := r.api.SendMessageWithResponse(ctx, body)
+	if err != nil {
+		return nil, fmt.Errorf("send message: %w", err)
+	}
+
+	if resp == nil {
+		return nil, fmt.Errorf("send message: empty response")
+	}
+
+	return resp, nil
+}
+
+// responseError returns the Bot API error of a typed call. Successful
+// responses are decoded into JSON200 by the generated client; the body of
+// anything else is parsed, since the client leaves most error statuses undecoded.
+func responseError(decoded bool, statusCode int, body []byte) *botapi.APIError {
+	if decoded {
+		return nil
+	}
+
+	return botapi.FromResponse(statusCode, body)
+}
+
 func applySendTextOptions(body *client.SendMessageJSONRequestBody, opts []SendTextOption) {
 	for _, opt := range opts {
 		if opt != nil {
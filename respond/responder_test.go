@@ -2,11 +2,17 @@ package respond_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/botapi"
+	"github.com/tgbotkit/runtime/format"
+	"github.com/tgbotkit/runtime/i18n"
+	"github.com/tgbotkit/runtime/keyboard"
 	"github.com/tgbotkit/runtime/respond"
 )
 
@@ -14,6 +20,7 @@ type mockClient struct {
 	client.ClientWithResponsesInterface
 	sendFunc   func(context.Context, client.SendMessageJSONRequestBody) (*client.SendMessageResponse, error)
 	answerFunc func(context.Context, client.AnswerCallbackQueryJSONRequestBody) (*client.AnswerCallbackQueryResponse, error)
+	actionFunc func(context.Context, client.SendChatActionJSONRequestBody) (*client.SendChatActionResponse, error)
 }
 
 func (m *mockClient) SendMessageWithResponse(
@@ -32,6 +39,14 @@ func (m *mockClient) AnswerCallbackQueryWithResponse(
 	return m.answerFunc(ctx, body)
 }
 
+func (m *mockClient) SendChatActionWithResponse(
+	ctx context.Context,
+	body client.SendChatActionJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.SendChatActionResponse, error) {
+	return m.actionFunc(ctx, body)
+}
+
 func TestResponderSendText(t *testing.T) {
 	t.Parallel()
 
@@ -165,6 +180,248 @@ func TestResponderSendTextPatch(t *testing.T) {
 	}
 }
 
+func TestResponderSendTextWithReplyMarkup(t *testing.T) {
+	t.Parallel()
+
+	var got client.SendMessageJSONRequestBody
+	responder := respond.New(&mockClient{
+		sendFunc: func(_ context.Context, body client.SendMessageJSONRequestBody) (*client.SendMessageResponse, error) {
+			got = body
+
+			return sendMessageResponse(client.Message{}), nil
+		},
+	})
+
+	markup, err := keyboard.Inline().Add(keyboard.Callback("Next", "page:2")).Build()
+	if err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+
+	if _, err := responder.SendText(context.Background(), respond.ChatTarget{ChatID: 1}, "menu", respond.WithReplyMarkup(markup)); err != nil {
+		t.Fatalf("SendText() unexpected error: %v", err)
+	}
+	if got.ReplyMarkup == nil || got.ReplyMarkup.InlineKeyboard == nil {
+		t.Fatalf("ReplyMarkup=%v, want inline keyboard", got.ReplyMarkup)
+	}
+	if data := (*got.ReplyMarkup.InlineKeyboard)[0][0].CallbackData; data == nil || *data != "page:2" {
+		t.Fatalf("callback data=%v, want page:2", data)
+	}
+
+	if _, err := responder.SendText(context.Background(), respond.ChatTarget{ChatID: 1}, "bye", respond.WithReplyMarkup(keyboard.Remove())); err != nil {
+		t.Fatalf("SendText() unexpected error: %v", err)
+	}
+	if got.ReplyMarkup == nil || got.ReplyMarkup.RemoveKeyboard == nil || !*got.ReplyMarkup.RemoveKeyboard {
+		t.Fatalf("ReplyMarkup=%v, want remove_keyboard", got.ReplyMarkup)
+	}
+}
+
+func TestResponderSendFormatted(t *testing.T) {
+	t.Parallel()
+
+	var got client.SendMessageJSONRequestBody
+	responder := respond.New(&mockClient{
+		sendFunc: func(_ context.Context, body client.SendMessageJSONRequestBody) (*client.SendMessageResponse, error) {
+			got = body
+
+			return sendMessageResponse(client.Message{MessageId: 1}), nil
+		},
+	})
+
+	text := format.Join("Hi ", format.Bold("*you*"))
+
+	_, err := responder.SendFormatted(context.Background(), respond.ChatTarget{ChatID: 42}, text, respond.WithHTML())
+	if err != nil {
+		t.Fatalf("SendFormatted() unexpected error: %v", err)
+	}
+
+	if got.Text != "Hi *you*" || got.ParseMode != nil {
+		t.Fatalf("text=%q parse mode=%v, want plain text without parse mode", got.Text, got.ParseMode)
+	}
+
+	if got.Entities == nil || len(*got.Entities) != 1 || (*got.Entities)[0].Type != "bold" || (*got.Entities)[0].Offset != 3 {
+		t.Fatalf("entities=%v, want bold at 3", got.Entities)
+	}
+}
+
+func TestResponderSendLongText(t *testing.T) {
+	t.Parallel()
+
+	var bodies []client.SendMessageJSONRequestBody
+	responder := respond.New(&mockClient{
+		sendFunc: func(_ context.Context, body client.SendMessageJSONRequestBody) (*client.SendMessageResponse, error) {
+			bodies = append(bodies, body)
+
+			return sendMessageResponse(client.Message{MessageId: len(bodies), Chat: client.Chat{Id: body.ChatId}}), nil
+		},
+	})
+
+	markup, err := keyboard.Inline().Add(keyboard.Callback("More", "more")).Build()
+	if err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+
+	source := &client.Message{MessageId: 77}
+	text := "<b>" + strings.Repeat("word ", 1000) + "</b>"
+
+	messages, err := responder.SendLongText(
+		context.Background(),
+		respond.ChatTarget{ChatID: 42},
+		text,
+		respond.WithHTML(),
+		respond.WithReplyMarkup(markup),
+		respond.WithSendMessagePatch(func(body *client.SendMessageJSONRequestBody) {
+			messageID := source.MessageId
+			body.ReplyParameters = &client.ReplyParameters{MessageId: &messageID}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("SendLongText() unexpected error: %v", err)
+	}
+	if len(messages) != 2 || len(bodies) != 2 {
+		t.Fatalf("messages=%d bodies=%d, want 2", len(messages), len(bodies))
+	}
+
+	for i, body := range bodies {
+		if !strings.HasPrefix(body.Text, "<b>") || !strings.HasSuffix(body.Text, "</b>") {
+			t.Fatalf("part %d is not balanced: %q...", i, body.Text[:20])
+		}
+		if body.ParseMode == nil || *body.ParseMode != "HTML" {
+			t.Fatalf("part %d ParseMode=%v, want HTML", i, body.ParseMode)
+		}
+	}
+	if bodies[0].ReplyParameters == nil || bodies[1].ReplyParameters != nil {
+		t.Fatalf("reply parameters=%v,%v, want first part only", bodies[0].ReplyParameters, bodies[1].ReplyParameters)
+	}
+	if bodies[0].ReplyMarkup != nil || bodies[1].ReplyMarkup == nil {
+		t.Fatalf("reply markup=%v,%v, want last part only", bodies[0].ReplyMarkup, bodies[1].ReplyMarkup)
+	}
+
+	_, err = responder.SendLongText(context.Background(), respond.ChatTarget{ChatID: 42}, "x", respond.WithParseMode("Markdown"))
+	if !errors.Is(err, respond.ErrUnsupportedParseMode) {
+		t.Fatalf("SendLongText(Markdown) error=%v, want ErrUnsupportedParseMode", err)
+	}
+}
+
+func TestResponderSendLongTextWithCommonMark(t *testing.T) {
+	t.Parallel()
+
+	var bodies []client.SendMessageJSONRequestBody
+	responder := respond.New(&mockClient{
+		sendFunc: func(_ context.Context, body client.SendMessageJSONRequestBody) (*client.SendMessageResponse, error) {
+			bodies = append(bodies, body)
+
+			return sendMessageResponse(client.Message{MessageId: len(bodies), Chat: client.Chat{Id: body.ChatId}}), nil
+		},
+	})
+
+	text := "## Notes\n\n**" + strings.TrimSpace(strings.Repeat("word ", 1000)) + "**"
+
+	_, err := responder.SendLongText(context.Background(), respond.ChatTarget{ChatID: 42}, text, respond.WithCommonMark())
+	if err != nil {
+		t.Fatalf("SendLongText() unexpected error: %v", err)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("bodies=%d, want 2", len(bodies))
+	}
+
+	if !strings.HasPrefix(bodies[0].Text, "Notes\n\nword") || strings.Contains(bodies[0].Text, "*") {
+		t.Fatalf("first part=%q..., want converted text", bodies[0].Text[:20])
+	}
+
+	for i, body := range bodies {
+		if body.ParseMode != nil {
+			t.Fatalf("part %d ParseMode=%v, want nil", i, *body.ParseMode)
+		}
+		if body.Entities == nil || len(*body.Entities) == 0 || (*body.Entities)[len(*body.Entities)-1].Type != "bold" {
+			t.Fatalf("part %d entities=%v, want bold", i, body.Entities)
+		}
+	}
+}
+
+func TestResponderSendLocalized(t *testing.T) {
+	t.Parallel()
+
+	var got client.SendMessageJSONRequestBody
+	responder := respond.New(&mockClient{
+		sendFunc: func(_ context.Context, body client.SendMessageJSONRequestBody) (*client.SendMessageResponse, error) {
+			got = body
+
+			return sendMessageResponse(client.Message{Chat: client.Chat{Id: body.ChatId}}), nil
+		},
+	})
+
+	bundle, err := i18n.New(i18n.NewOptions())
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	if err := bundle.AddMessages("fr", map[string]string{"welcome": "Bienvenue, {{.}} !"}); err != nil {
+		t.Fatalf("AddMessages() unexpected error: %v", err)
+	}
+
+	ctx := i18n.WithLocalizer(context.Background(), bundle.Localizer("fr"))
+
+	if _, err := responder.SendLocalized(ctx, respond.ChatTarget{ChatID: 42}, "welcome", "Ann"); err != nil {
+		t.Fatalf("SendLocalized() unexpected error: %v", err)
+	}
+
+	if got.Text != "Bienvenue, Ann !" {
+		t.Fatalf("Text=%q, want the French translation", got.Text)
+	}
+}
+
+func TestResponderSendTextRetriesMigratedChat(t *testing.T) {
+	t.Parallel()
+
+	var chatIDs []int64
+	newChatID := int64(-1001234)
+	responder := respond.New(&mockClient{
+		sendFunc: func(_ context.Context, body client.SendMessageJSONRequestBody) (*client.SendMessageResponse, error) {
+			chatIDs = append(chatIDs, body.ChatId)
+			if body.ChatId != newChatID {
+				return migratedChatResponse(newChatID), nil
+			}
+
+			return sendMessageResponse(client.Message{Chat: client.Chat{Id: body.ChatId}, Text: &body.Text}), nil
+		},
+	})
+
+	msg, err := responder.SendText(context.Background(), respond.ChatTarget{ChatID: -42}, "hello")
+	if err != nil {
+		t.Fatalf("SendText() unexpected error: %v", err)
+	}
+	if msg.Chat.Id != newChatID {
+		t.Fatalf("message chat=%d, want %d", msg.Chat.Id, newChatID)
+	}
+	if len(chatIDs) != 2 || chatIDs[0] != -42 || chatIDs[1] != newChatID {
+		t.Fatalf("send chat ids=%v, want [-42 %d]", chatIDs, newChatID)
+	}
+}
+
+func TestResponderSendTextReturnsAPIError(t *testing.T) {
+	t.Parallel()
+
+	responder := respond.New(&mockClient{
+		sendFunc: func(context.Context, client.SendMessageJSONRequestBody) (*client.SendMessageResponse, error) {
+			// The generated client decodes only 400 and 401 bodies; 403 stays raw.
+			return &client.SendMessageResponse{
+				Body:         []byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`),
+				HTTPResponse: &http.Response{StatusCode: http.StatusForbidden, Status: "403 Forbidden"},
+			}, nil
+		},
+	})
+
+	_, err := responder.SendText(context.Background(), respond.ChatTarget{ChatID: 42}, "hello")
+	if !botapi.IsBlockedByUser(err) {
+		t.Fatalf("SendText() error=%v, want blocked by user", err)
+	}
+
+	var apiErr *botapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode != http.StatusForbidden {
+		t.Fatalf("SendText() error=%v, want *botapi.APIError with code 403", err)
+	}
+}
+
 func TestResponderAnswerCallback(t *testing.T) {
 	t.Parallel()
 
@@ -219,6 +476,12 @@ func TestResponderErrors(t *testing.T) {
 	if err := responder.AnswerCallback(context.Background(), &client.CallbackQuery{}); !errors.Is(err, respond.ErrNilClient) {
 		t.Fatalf("AnswerCallback() err=%v, want ErrNilClient", err)
 	}
+	if _, err := responder.EditText(context.Background(), respond.MessageRef{}, "hello"); !errors.Is(err, respond.ErrNilClient) {
+		t.Fatalf("EditText() err=%v, want ErrNilClient", err)
+	}
+	if _, err := responder.CopyMany(context.Background(), respond.ChatTarget{}, 1, []int{1}); !errors.Is(err, respond.ErrNilClient) {
+		t.Fatalf("CopyMany() err=%v, want ErrNilClient", err)
+	}
 
 	responder = respond.New(&mockClient{})
 	if err := responder.AnswerCallback(context.Background(), nil); !errors.Is(err, respond.ErrNilCallbackQuery) {
@@ -256,6 +519,21 @@ func sendMessageResponse(message client.Message) *client.SendMessageResponse {
 	}
 }
 
+func migratedChatResponse(chatID int64) *client.SendMessageResponse {
+	errResp := &client.ErrorResponse{
+		ErrorCode:   http.StatusBadRequest,
+		Description: "Bad Request: group chat was upgraded to a supergroup chat",
+		Parameters:  &client.ResponseParameters{MigrateToChatId: &chatID},
+	}
+	body, _ := json.Marshal(errResp)
+
+	return &client.SendMessageResponse{
+		Body:         body,
+		HTTPResponse: &http.Response{StatusCode: http.StatusBadRequest, Status: "400 Bad Request"},
+		JSON400:      errResp,
+	}
+}
+
 func answerCallbackResponse(result bool) *client.AnswerCallbackQueryResponse {
 	return &client.AnswerCallbackQueryResponse{
 		HTTPResponse: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"},
@@ -0,0 +1,514 @@
+package respond
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/botapi"
+	"github.com/tgbotkit/runtime/textsplit"
+)
+
+// DefaultStreamInterval is how often a StreamWriter updates the chat by
+// default. Telegram allows about one message per second in a private chat.
+const DefaultStreamInterval = time.Second
+
+// defaultStreamPlaceholder is the text of the first message streamed by
+// editing, shown until text is written.
+const defaultStreamPlaceholder = "…"
+
+// draftIDs numbers the drafts of all streams; draft IDs must be non-zero.
+var draftIDs atomic.Int64
+
+// StreamOption customizes Responder.Stream.
+type StreamOption func(*streamConfig)
+
+type streamConfig struct {
+	interval    time.Duration
+	placeholder *string
+	edits       bool
+	opts        []SendTextOption
+}
+
+// WithStreamInterval sets how often the stream updates the chat. Writes in
+// between are coalesced into one update.
+func WithStreamInterval(interval time.Duration) StreamOption {
+	return func(config *streamConfig) {
+		if interval > 0 {
+			config.interval = interval
+		}
+	}
+}
+
+// WithStreamPlaceholder sets the text shown until text is written. Drafts
+// show "Thinking…" for an empty placeholder; when streaming by editing, an
+// empty placeholder defers the first message until text is written.
+func WithStreamPlaceholder(text string) StreamOption {
+	return func(config *streamConfig) {
+		config.placeholder = &text
+	}
+}
+
+// WithStreamEdits streams by editing sent messages even where drafts are
+// available.
+func WithStreamEdits() StreamOption {
+	return func(config *streamConfig) {
+		config.edits = true
+	}
+}
+
+// WithStreamFormat sets the send options of the final text, such as
+// WithCommonMark, WithHTML or WithReplyMarkup. Partial text may have
+// unbalanced markup, so it is shown as plain text until Close.
+func WithStreamFormat(opts ...SendTextOption) StreamOption {
+	return func(config *streamConfig) {
+		config.opts = append(config.opts, opts...)
+	}
+}
+
+// StreamWriter shows text written to it in a chat as it arrives, such as the
+// output of a language model. It is safe for concurrent use.
+type StreamWriter struct {
+	responder *Responder
+	ctx       context.Context
+	config    streamConfig
+	template  client.SendMessageJSONRequestBody
+	draftID   int
+
+	mu       sync.Mutex
+	text     strings.Builder
+	messages []client.Message
+	shown    []string
+	draft    *string
+	next     time.Time
+	timer    *time.Timer
+	err      error
+	closed   bool
+}
+
+// Stream starts showing text in the target chat and returns a writer for it.
+//
+// In private chats the text is streamed as a draft with sendMessageDraft and
+// sent as a message on Close. Elsewhere, or after WithStreamEdits, a
+// placeholder message is sent and edited as text is written. Updates happen
+// at most once per DefaultStreamInterval, skip unchanged text and wait as
+// long as Telegram asks when it reports a flood limit. Text longer than a
+// message continues in a new one.
+//
+// Updates use ctx until the stream is closed. Close sends the final text
+// with the options of WithStreamFormat, even when ctx is already done, and
+// Finish does so with a context of its own; every stream must be closed.
+func (r *Responder) Stream(ctx context.Context, target ChatTarget, opts ...StreamOption) (*StreamWriter, error) {
+	if r == nil || r.api == nil {
+		return nil, ErrNilClient
+	}
+
+	config := streamConfig{interval: DefaultStreamInterval}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&config)
+		}
+	}
+
+	w := &StreamWriter{responder: r, ctx: ctx, config: config}
+	target.applyTo(&w.template)
+	applySendTextOptions(&w.template, config.opts)
+
+	if !config.edits && target.private() {
+		w.draftID = int(draftIDs.Add(1))
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.start(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Write adds p to the streamed text. It returns the error of a failed update
+// made since the previous call.
+func (w *StreamWriter) Write(p []byte) (int, error) {
+	return w.WriteString(string(p))
+}
+
+// WriteString adds s to the streamed text, like Write.
+func (w *StreamWriter) WriteString(s string) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrStreamClosed
+	}
+
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	w.text.WriteString(s)
+	w.schedule()
+
+	return len(s), nil
+}
+
+// Flush shows the text written so far at once, without waiting for the
+// update interval. When Telegram asks to slow down, the text is shown once
+// it allows, as between regular updates.
+func (w *StreamWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrStreamClosed
+	}
+
+	if w.err != nil {
+		return w.err
+	}
+
+	w.stopTimer()
+	w.err = w.updateOrDefer()
+
+	return w.err
+}
+
+// Close shows the final text formatted with the options of
+// WithStreamFormat. Text split over several messages is split again for its
+// formatting, sending or deleting messages as needed. When nothing was
+// written, the placeholder message is deleted. Closing again does nothing.
+//
+// Close makes its calls with the values of the context passed to Stream but
+// not its cancellation, so that a stream outliving its handler is still
+// finished. Use Finish to bound them.
+func (w *StreamWriter) Close() error {
+	return w.Finish(context.WithoutCancel(w.ctx))
+}
+
+// Finish closes the stream like Close, making the final calls with ctx.
+func (w *StreamWriter) Finish(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+	w.stopTimer()
+	// No update runs after the stream is closed.
+	w.ctx = ctx
+
+	return errors.Join(w.err, w.finish())
+}
+
+// Messages returns the messages sent so far. After Close they hold the final
+// text.
+func (w *StreamWriter) Messages() []client.Message {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]client.Message(nil), w.messages...)
+}
+
+func (w *StreamWriter) start() error {
+	w.next = time.Now().Add(w.config.interval)
+
+	if w.draftID != 0 {
+		placeholder := ""
+		if w.config.placeholder != nil {
+			placeholder = *w.config.placeholder
+		}
+
+		err := w.showDraft(placeholder)
+
+		_, rejected := botapi.As(err)
+
+		switch {
+		case botapi.IsFlood(err):
+			// The placeholder is skipped; text follows when Telegram allows.
+			return nil
+		case !rejected:
+			return err
+		}
+
+		// Drafts are unavailable to this bot or chat; stream by editing.
+		w.draftID = 0
+	}
+
+	placeholder := defaultStreamPlaceholder
+	if w.config.placeholder != nil {
+		placeholder = *w.config.placeholder
+	}
+
+	if strings.TrimSpace(placeholder) == "" {
+		return nil
+	}
+
+	return w.show(0, placeholder)
+}
+
+// schedule arranges an update for when the interval since the last one
+// ends.
+func (w *StreamWriter) schedule() {
+	if w.timer != nil {
+		return
+	}
+
+	w.timer = time.AfterFunc(time.Until(w.next), w.tick)
+}
+
+func (w *StreamWriter) stopTimer() {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+}
+
+func (w *StreamWriter) tick() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed || w.timer == nil {
+		return
+	}
+
+	w.timer = nil
+	w.err = w.updateOrDefer()
+}
+
+// updateOrDefer runs update, rescheduling it when Telegram rejects it with a
+// flood limit instead of failing the stream.
+func (w *StreamWriter) updateOrDefer() error {
+	err := w.update()
+	if apiErr, ok := botapi.As(err); ok && botapi.IsFlood(err) {
+		// Intermediate text may be skipped; try again when Telegram allows.
+		w.next = time.Now().Add(apiErr.RetryAfter)
+		w.schedule()
+
+		return nil
+	}
+
+	return err
+}
+
+// update shows the plain text written so far: parts that filled a message
+// as messages and the rest as a draft or the last message.
+func (w *StreamWriter) update() error {
+	w.next = time.Now().Add(w.config.interval)
+
+	text := w.text.String()
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	parts := textsplit.Plain(text, textsplit.MessageLimit)
+	last := len(parts) - 1
+
+	for i, part := range parts[:last] {
+		if err := w.show(i, part); err != nil {
+			return err
+		}
+	}
+
+	if w.draftID != 0 {
+		return w.showDraft(parts[last])
+	}
+
+	return w.show(last, parts[last])
+}
+
+// show makes the i-th message show text as plain text, sending it when it
+// does not exist yet.
+func (w *StreamWriter) show(i int, text string) error {
+	if i < len(w.messages) {
+		if w.shown[i] == text {
+			return nil
+		}
+
+		if err := w.edit(i, text, nil); err != nil {
+			return err
+		}
+
+		w.shown[i] = text
+
+		return nil
+	}
+
+	body := w.template
+	body.Text = text
+	body.ParseMode = nil
+	body.Entities = nil
+	body.ReplyMarkup = nil
+
+	return w.send(body)
+}
+
+func (w *StreamWriter) send(body client.SendMessageJSONRequestBody) error {
+	if len(w.messages) > 0 {
+		body.ReplyParameters = nil
+	}
+
+	message, err := w.responder.sendMessageBody(w.ctx, body)
+	if err != nil {
+		return err
+	}
+
+	// Later messages go straight to the new chat after a migration.
+	w.template.ChatId = message.Chat.Id
+	w.messages = append(w.messages, *message)
+	w.shown = append(w.shown, body.Text)
+
+	return nil
+}
+
+// edit replaces the text of the i-th message. Telegram rejecting unchanged
+// text is not an error.
+func (w *StreamWriter) edit(i int, text string, opts []EditOption) error {
+	opts = append(opts, func(req *EditRequest) {
+		req.LinkPreviewOptions = w.template.LinkPreviewOptions
+	})
+
+	message, err := w.responder.EditText(w.ctx, MessageRef{
+		ChatID:               w.messages[i].Chat.Id,
+		MessageID:            w.messages[i].MessageId,
+		BusinessConnectionID: w.template.BusinessConnectionId,
+	}, text, opts...)
+	if err != nil && !botapi.IsMessageNotModified(err) {
+		return err
+	}
+
+	if message != nil {
+		w.messages[i] = *message
+	}
+
+	return nil
+}
+
+func (w *StreamWriter) showDraft(text string) error {
+	if w.draft != nil && *w.draft == text {
+		return nil
+	}
+
+	body := client.SendMessageDraftJSONRequestBody{
+		ChatId:          w.template.ChatId,
+		DraftId:         w.draftID,
+		MessageThreadId: w.template.MessageThreadId,
+		Text:            &text,
+	}
+
+	resp, err := w.responder.api.SendMessageDraftWithResponse(w.ctx, body)
+	if err != nil {
+		return fmt.Errorf("send message draft: %w", err)
+	}
+
+	if resp == nil {
+		return fmt.Errorf("send message draft: empty response")
+	}
+
+	if apiErr := responseError(resp.JSON200 != nil, resp.StatusCode(), resp.Body); apiErr != nil {
+		return fmt.Errorf("send message draft: %w", apiErr)
+	}
+
+	if resp.JSON200 == nil || !bool(resp.JSON200.Ok) || !resp.JSON200.Result {
+		return fmt.Errorf("send message draft: unexpected response: %s", resp.Status())
+	}
+
+	w.draft = &text
+
+	return nil
+}
+
+// finish shows the final formatted text, reusing the messages already sent.
+func (w *StreamWriter) finish() error {
+	text := w.text.String()
+	if strings.TrimSpace(text) == "" {
+		return w.deleteFrom(0)
+	}
+
+	body := w.template
+	body.Text = text
+	body.ParseMode = nil
+	body.Entities = nil
+	applySendTextOptions(&body, w.config.opts)
+
+	parts, err := splitMessage(body)
+	if err != nil {
+		return err
+	}
+
+	for i, part := range parts {
+		if err := w.finishPart(body, i, part, i == len(parts)-1); err != nil {
+			return fmt.Errorf("finish part %d of %d: %w", i+1, len(parts), err)
+		}
+	}
+
+	return w.deleteFrom(len(parts))
+}
+
+func (w *StreamWriter) finishPart(body client.SendMessageJSONRequestBody, i int, part messagePart, last bool) error {
+	if !last {
+		body.ReplyMarkup = nil
+	}
+
+	if i >= len(w.messages) {
+		body.Text = part.Text
+		body.Entities = part.Entities
+
+		return w.send(body)
+	}
+
+	var opts []EditOption
+
+	if body.ParseMode != nil {
+		opts = append(opts, WithEditParseMode(*body.ParseMode))
+	}
+
+	if part.Entities != nil {
+		opts = append(opts, WithEditEntities(*part.Entities))
+	}
+
+	// Edits can attach only inline keyboards.
+	if body.ReplyMarkup != nil && body.ReplyMarkup.InlineKeyboard != nil {
+		opts = append(opts, WithEditReplyMarkup(&client.InlineKeyboardMarkup{
+			InlineKeyboard: *body.ReplyMarkup.InlineKeyboard,
+		}))
+	}
+
+	if len(opts) == 0 && w.shown[i] == part.Text {
+		return nil
+	}
+
+	if err := w.edit(i, part.Text, opts); err != nil {
+		return err
+	}
+
+	w.shown[i] = part.Text
+
+	return nil
+}
+
+// deleteFrom deletes the messages from the i-th on, left over when the final
+// text needs fewer messages.
+func (w *StreamWriter) deleteFrom(i int) error {
+	for _, message := range w.messages[i:] {
+		if err := w.responder.DeleteMessage(w.ctx, MessageRef{
+			ChatID:    message.Chat.Id,
+			MessageID: message.MessageId,
+		}); err != nil {
+			return err
+		}
+	}
+
+	w.messages = w.messages[:i]
+	w.shown = w.shown[:i]
+
+	return nil
+}
@@ -0,0 +1,388 @@
+package respond_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/respond"
+)
+
+type streamMockClient struct {
+	client.ClientWithResponsesInterface
+
+	mu        sync.Mutex
+	calls     []recordedCall
+	nextID    int
+	draftCode int
+	// editFloods is how many edits are rejected with a flood limit first.
+	editFloods int
+}
+
+func (m *streamMockClient) record(method string, body any) {
+	data, _ := json.Marshal(body)
+
+	var values map[string]any
+	_ = json.Unmarshal(data, &values)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, recordedCall{method: method, values: values})
+}
+
+func (m *streamMockClient) recorded() []recordedCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]recordedCall(nil), m.calls...)
+}
+
+func (m *streamMockClient) SendMessageWithResponse(
+	_ context.Context,
+	body client.SendMessageJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.SendMessageResponse, error) {
+	m.record("sendMessage", body)
+
+	m.mu.Lock()
+	m.nextID++
+	id := m.nextID
+	m.mu.Unlock()
+
+	return sendMessageResponse(client.Message{MessageId: id, Chat: client.Chat{Id: body.ChatId}, Text: &body.Text}), nil
+}
+
+func (m *streamMockClient) SendMessageDraftWithResponse(
+	_ context.Context,
+	body client.SendMessageDraftJSONRequestBody,
+	_ ...client.RequestEditorFn,
+) (*client.SendMessageDraftResponse, error) {
+	m.record("sendMessageDraft", body)
+
+	if m.draftCode != 0 {
+		return &client.SendMessageDraftResponse{
+			Body:         []byte(`{"ok":false,"error_code":400,"description":"Bad Request: method is unavailable"}`),
+			HTTPResponse: &http.Response{StatusCode: m.draftCode, Status: http.StatusText(m.draftCode)},
+		}, nil
+	}
+
+	resp := &client.SendMessageDraftResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"},
+	}
+	resp.JSON200 = &struct {
+		Ok     client.SendMessageDraft200Ok `json:"ok"`
+		Result bool                         `json:"result"`
+	}{Ok: true, Result: true}
+
+	return resp, nil
+}
+
+func (m *streamMockClient) EditMessageTextWithBodyWithResponse(
+	_ context.Context,
+	_ string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.EditMessageTextResponse, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	m.record("editMessageText", json.RawMessage(data))
+
+	m.mu.Lock()
+	flood := m.editFloods > 0
+	m.editFloods--
+	m.mu.Unlock()
+
+	if flood {
+		return &client.EditMessageTextResponse{
+			Body:         []byte(`{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":1}}`),
+			HTTPResponse: &http.Response{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"},
+		}, nil
+	}
+
+	return &client.EditMessageTextResponse{
+		Body:         []byte(`{"ok":true,"result":{"message_id":1,"date":1,"chat":{"id":-100,"type":"group"}}}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"},
+	}, nil
+}
+
+func (m *streamMockClient) DeleteMessageWithBodyWithResponse(
+	ctx context.Context,
+	_ string,
+	body io.Reader,
+	_ ...client.RequestEditorFn,
+) (*client.DeleteMessageResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	m.record("deleteMessage", json.RawMessage(data))
+
+	return &client.DeleteMessageResponse{
+		Body:         []byte(`{"ok":true,"result":true}`),
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"},
+	}, nil
+}
+
+func methods(calls []recordedCall) []string {
+	names := make([]string, 0, len(calls))
+	for _, call := range calls {
+		names = append(names, call.method)
+	}
+
+	return names
+}
+
+func TestStreamEditsPlaceholderAndFormatsOnClose(t *testing.T) {
+	t.Parallel()
+
+	api := &streamMockClient{}
+	stream, err := respond.New(api).Stream(context.Background(), respond.ChatTarget{ChatID: -100},
+		respond.WithStreamInterval(time.Hour),
+		respond.WithStreamFormat(respond.WithCommonMark()),
+	)
+	if err != nil {
+		t.Fatalf("Stream() unexpected error: %v", err)
+	}
+
+	for _, chunk := range []string{"Hello ", "**world**"} {
+		if _, err := io.WriteString(stream, chunk); err != nil {
+			t.Fatalf("WriteString() unexpected error: %v", err)
+		}
+	}
+
+	if err := stream.Flush(); err != nil {
+		t.Fatalf("Flush() unexpected error: %v", err)
+	}
+	if err := stream.Flush(); err != nil {
+		t.Fatalf("Flush() unexpected error: %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	calls := api.recorded()
+	want := []string{"sendMessage", "editMessageText", "editMessageText"}
+	if got := methods(calls); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("calls=%v, want %v", got, want)
+	}
+	if calls[0].values["text"] != "…" {
+		t.Fatalf("placeholder=%v, want …", calls[0].values["text"])
+	}
+	if calls[1].values["text"] != "Hello **world**" || calls[1].values["entities"] != nil {
+		t.Fatalf("update=%v, want plain partial text", calls[1].values)
+	}
+	if calls[2].values["text"] != "Hello world" || calls[2].values["entities"] == nil {
+		t.Fatalf("final=%v, want formatted text", calls[2].values)
+	}
+
+	if _, err := stream.Write([]byte("late")); !errors.Is(err, respond.ErrStreamClosed) {
+		t.Fatalf("Write() after Close error=%v, want ErrStreamClosed", err)
+	}
+}
+
+func TestStreamCoalescesWrites(t *testing.T) {
+	t.Parallel()
+
+	api := &streamMockClient{}
+	stream, err := respond.New(api).Stream(context.Background(), respond.ChatTarget{ChatID: -100},
+		respond.WithStreamInterval(20*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Stream() unexpected error: %v", err)
+	}
+
+	for range 100 {
+		if _, err := io.WriteString(stream, "x"); err != nil {
+			t.Fatalf("WriteString() unexpected error: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(api.recorded()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	calls := api.recorded()
+	want := []string{"sendMessage", "editMessageText"}
+	if got := methods(calls); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("calls=%v, want %v", got, want)
+	}
+	if calls[1].values["text"] != strings.Repeat("x", 100) {
+		t.Fatalf("update=%v, want all writes in one edit", calls[1].values["text"])
+	}
+}
+
+func TestStreamRollsOverLongText(t *testing.T) {
+	t.Parallel()
+
+	api := &streamMockClient{}
+	stream, err := respond.New(api).Stream(context.Background(), respond.ChatTarget{ChatID: -100},
+		respond.WithStreamInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("Stream() unexpected error: %v", err)
+	}
+
+	first := strings.Repeat("a", 3000)
+	second := strings.Repeat("b", 3000)
+
+	if _, err := io.WriteString(stream, first+"\n\n"+second); err != nil {
+		t.Fatalf("WriteString() unexpected error: %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	calls := api.recorded()
+	want := []string{"sendMessage", "editMessageText", "sendMessage"}
+	if got := methods(calls); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("calls=%v, want %v", got, want)
+	}
+	if calls[1].values["text"] != first || calls[2].values["text"] != second {
+		t.Fatal("parts were not split on the paragraph break")
+	}
+	if got := len(stream.Messages()); got != 2 {
+		t.Fatalf("Messages() len=%d, want 2", got)
+	}
+}
+
+func TestStreamUsesDraftsInPrivateChats(t *testing.T) {
+	t.Parallel()
+
+	api := &streamMockClient{}
+	stream, err := respond.New(api).Stream(context.Background(), respond.ChatTarget{ChatID: 42},
+		respond.WithStreamInterval(time.Hour),
+		respond.WithStreamFormat(respond.WithHTML()),
+	)
+	if err != nil {
+		t.Fatalf("Stream() unexpected error: %v", err)
+	}
+
+	if _, err := io.WriteString(stream, "<b>hi</b>"); err != nil {
+		t.Fatalf("WriteString() unexpected error: %v", err)
+	}
+	if err := stream.Flush(); err != nil {
+		t.Fatalf("Flush() unexpected error: %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	calls := api.recorded()
+	want := []string{"sendMessageDraft", "sendMessageDraft", "sendMessage"}
+	if got := methods(calls); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("calls=%v, want %v", got, want)
+	}
+	if calls[0].values["text"] != "" || calls[0].values["draft_id"] == float64(0) {
+		t.Fatalf("placeholder draft=%v, want empty text with a draft ID", calls[0].values)
+	}
+	if calls[0].values["draft_id"] != calls[1].values["draft_id"] {
+		t.Fatal("draft updates use different draft IDs")
+	}
+	if calls[2].values["text"] != "<b>hi</b>" || calls[2].values["parse_mode"] != "HTML" {
+		t.Fatalf("final=%v, want HTML message", calls[2].values)
+	}
+}
+
+func TestStreamFlushSurvivesFloodLimits(t *testing.T) {
+	t.Parallel()
+
+	api := &streamMockClient{editFloods: 1}
+	stream, err := respond.New(api).Stream(context.Background(), respond.ChatTarget{ChatID: -100},
+		respond.WithStreamInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("Stream() unexpected error: %v", err)
+	}
+
+	for _, text := range []string{"a", "b"} {
+		if _, err := io.WriteString(stream, text); err != nil {
+			t.Fatalf("WriteString() unexpected error: %v", err)
+		}
+
+		if err := stream.Flush(); err != nil {
+			t.Fatalf("Flush() unexpected error: %v", err)
+		}
+	}
+
+	calls := api.recorded()
+	if len(calls) != 3 || calls[2].values["text"] != "ab" {
+		t.Fatalf("calls=%v, want the rejected edit and then one with all the text", calls)
+	}
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+}
+
+func TestStreamFallsBackToEditsWithoutDrafts(t *testing.T) {
+	t.Parallel()
+
+	api := &streamMockClient{draftCode: http.StatusBadRequest}
+	stream, err := respond.New(api).Stream(context.Background(), respond.ChatTarget{ChatID: 42})
+	if err != nil {
+		t.Fatalf("Stream() unexpected error: %v", err)
+	}
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	want := []string{"sendMessageDraft", "sendMessage", "deleteMessage"}
+	if got := methods(api.recorded()); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("calls=%v, want %v", got, want)
+	}
+}
+
+func TestStreamClosesAfterContextEnds(t *testing.T) {
+	t.Parallel()
+
+	api := &streamMockClient{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stream, err := respond.New(api).Stream(ctx, respond.ChatTarget{ChatID: -100}, respond.WithStreamInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("Stream() unexpected error: %v", err)
+	}
+
+	// The handler that started the stream returned.
+	cancel()
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	want := []string{"sendMessage", "deleteMessage"}
+	if got := methods(api.recorded()); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("calls=%v, want %v", got, want)
+	}
+
+	stream, err = respond.New(api).Stream(context.Background(), respond.ChatTarget{ChatID: -100})
+	if err != nil {
+		t.Fatalf("Stream() unexpected error: %v", err)
+	}
+
+	if err := stream.Finish(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Finish() error=%v, want context.Canceled", err)
+	}
+}
@@ -45,6 +45,22 @@ func (t ChatTarget) applyTo(body *client.SendMessageJSONRequestBody) {
 	body.BusinessConnectionId = t.BusinessConnectionID
 }
 
+func (t ChatTarget) applyToForm(values map[string]any) {
+	values["chat_id"] = t.ChatID
+
+	if t.MessageThreadID != nil {
+		values["message_thread_id"] = *t.MessageThreadID
+	}
+
+	if t.DirectMessagesTopicID != nil {
+		values["direct_messages_topic_id"] = *t.DirectMessagesTopicID
+	}
+
+	if t.BusinessConnectionID != nil {
+		values["business_connection_id"] = *t.BusinessConnectionID
+	}
+}
+
 func intFromInt64(value int64) (int, error) {
 	maxInt := int64(int(^uint(0) >> 1))
 	minInt := -maxInt - 1
@@ -55,3 +71,9 @@ func intFromInt64(value int64) (int, error) {
 
 	return int(value), nil
 }
+
+// private reports whether the target is a private chat the bot writes to
+// itself, where message drafts are available.
+func (t ChatTarget) private() bool {
+	return t.ChatID > 0 && t.BusinessConnectionID == nil && t.DirectMessagesTopicID == nil
+}
@@ -0,0 +1,104 @@
+package textsplit
+
+import (
+	"html"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// HTML splits text written in Telegram's HTML parse mode into parts whose
+// visible text is at most limit units. Tags open at a split are closed at the
+// end of one part and reopened, with their attributes, at the start of the next.
+func HTML(text string, limit int) []string {
+	atoms := htmlAtoms(text)
+
+	return renderMarkup(atoms, splitAtoms(atoms, limit, limit))
+}
+
+// CaptionHTML splits an HTML caption like HTML for a media message followed
+// by text messages: the first part fits CaptionLimit and the others
+// MessageLimit.
+func CaptionHTML(text string) []string {
+	atoms := htmlAtoms(text)
+
+	return renderMarkup(atoms, splitAtoms(atoms, CaptionLimit, MessageLimit))
+}
+
+func htmlAtoms(text string) []atom {
+	atoms := make([]atom, 0, len(text))
+
+	for i := 0; i < len(text); {
+		switch text[i] {
+		case '<':
+			if a, n, ok := htmlTag(text[i:]); ok {
+				atoms = append(atoms, a)
+				i += n
+
+				continue
+			}
+		case '&':
+			if a, n, ok := htmlEntity(text[i:]); ok {
+				atoms = append(atoms, a)
+				i += n
+
+				continue
+			}
+		}
+
+		r, n := utf8.DecodeRuneInString(text[i:])
+		atoms = append(atoms, runeAtom(text[i:i+n], r))
+		i += n
+	}
+
+	return atoms
+}
+
+// htmlTag parses the tag at the start of s.
+func htmlTag(s string) (atom, int, bool) {
+	end := strings.IndexByte(s, '>')
+	if end < 0 {
+		return atom{}, 0, false
+	}
+
+	src := s[:end+1]
+	if strings.HasPrefix(src, "</") {
+		return atom{kind: atomClose, src: src}, len(src), true
+	}
+
+	name := src[1:end]
+	if cut := strings.IndexAny(name, " \t\n/"); cut >= 0 {
+		name = name[:cut]
+	}
+
+	if name == "" {
+		return atom{}, 0, false
+	}
+
+	return atom{kind: atomOpen, src: src, closer: "</" + name + ">"}, len(src), true
+}
+
+// htmlEntity parses a character reference such as &amp; or &#128512; at the
+// start of s as one visible character.
+func htmlEntity(s string) (atom, int, bool) {
+	end := strings.IndexByte(s, ';')
+
+	const maxEntityLength = 12
+	if end < 0 || end > maxEntityLength {
+		return atom{}, 0, false
+	}
+
+	decoded := html.UnescapeString(s[:end+1])
+	if decoded == s[:end+1] {
+		return atom{}, 0, false
+	}
+
+	width := 0
+	for _, r := range decoded {
+		width += max(utf16.RuneLen(r), 1)
+	}
+
+	r, _ := utf8.DecodeRuneInString(decoded)
+
+	return atom{kind: atomText, src: s[:end+1], width: width, char: r}, end + 1, true
+}
@@ -0,0 +1,195 @@
+package textsplit
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// MarkdownV2 splits text written in Telegram's MarkdownV2 parse mode into
+// parts whose visible text is at most limit units. Styles, links and code
+// blocks open at a split are closed at the end of one part and reopened at
+// the start of the next; code blocks keep their language.
+func MarkdownV2(text string, limit int) []string {
+	atoms := markdownAtoms(text)
+
+	return renderMarkup(atoms, splitAtoms(atoms, limit, limit))
+}
+
+// CaptionMarkdownV2 splits a MarkdownV2 caption like MarkdownV2 for a media
+// message followed by text messages: the first part fits CaptionLimit and the
+// others MessageLimit.
+func CaptionMarkdownV2(text string) []string {
+	atoms := markdownAtoms(text)
+
+	return renderMarkup(atoms, splitAtoms(atoms, CaptionLimit, MessageLimit))
+}
+
+// markdownDelimiters are the style markers, longest first.
+var markdownDelimiters = []string{"__", "||", "*", "_", "~"}
+
+type markdownLexer struct {
+	text  string
+	atoms []atom
+	// open holds the delimiters of open styles, innermost last.
+	open []string
+	// linkEnds maps the index of a link's closing bracket to its "](url)" suffix.
+	linkEnds map[int]string
+}
+
+func markdownAtoms(text string) []atom {
+	lx := &markdownLexer{text: text, atoms: make([]atom, 0, len(text)), linkEnds: map[int]string{}}
+
+	for i := 0; i < len(text); {
+		i = lx.next(i)
+	}
+
+	return lx.atoms
+}
+
+//nolint:cyclop // one branch per MarkdownV2 token
+func (lx *markdownLexer) next(i int) int {
+	s := lx.text[i:]
+	inCode := lx.top() == "`" || lx.top() == "```"
+
+	switch {
+	case s[0] == '\\' && len(s) > 1:
+		return lx.escaped(i)
+	case inCode && strings.HasPrefix(s, lx.top()):
+		return lx.closeStyle(i, lx.top())
+	case inCode:
+		return lx.char(i)
+	case strings.HasPrefix(s, "```"):
+		return lx.openPre(i)
+	case s[0] == '`':
+		return lx.openStyle(i, "`", "`")
+	case lx.linkEnds[i] != "":
+		suffix := lx.linkEnds[i]
+		lx.pop()
+		lx.atoms = append(lx.atoms, atom{kind: atomClose, src: suffix})
+
+		return i + len(suffix)
+	case s[0] == '[' || strings.HasPrefix(s, "!["):
+		return lx.openLink(i)
+	}
+
+	for _, delim := range markdownDelimiters {
+		if !strings.HasPrefix(s, delim) {
+			continue
+		}
+
+		// In "___", a single "_" closes an open italic before "__" is considered.
+		if delim == "__" && lx.top() == "_" {
+			return lx.closeStyle(i, "_")
+		}
+
+		if lx.isOpen(delim) {
+			return lx.closeStyle(i, delim)
+		}
+
+		return lx.openStyle(i, delim, delim)
+	}
+
+	return lx.char(i)
+}
+
+func (lx *markdownLexer) char(i int) int {
+	r, n := utf8.DecodeRuneInString(lx.text[i:])
+	lx.atoms = append(lx.atoms, runeAtom(lx.text[i:i+n], r))
+
+	return i + n
+}
+
+func (lx *markdownLexer) escaped(i int) int {
+	r, n := utf8.DecodeRuneInString(lx.text[i+1:])
+	lx.atoms = append(lx.atoms, runeAtom(lx.text[i:i+1+n], r))
+
+	return i + 1 + n
+}
+
+func (lx *markdownLexer) openStyle(i int, delim, src string) int {
+	lx.open = append(lx.open, delim)
+	lx.atoms = append(lx.atoms, atom{kind: atomOpen, src: src, closer: delim})
+
+	return i + len(src)
+}
+
+func (lx *markdownLexer) closeStyle(i int, delim string) int {
+	lx.pop()
+	lx.atoms = append(lx.atoms, atom{kind: atomClose, src: delim})
+
+	return i + len(delim)
+}
+
+// openPre opens a code block; its opener keeps the language line so parts
+// after a split stay highlighted.
+func (lx *markdownLexer) openPre(i int) int {
+	src := "```"
+	rest := lx.text[i+len(src):]
+
+	if nl := strings.IndexByte(rest, '\n'); nl >= 0 && !strings.Contains(rest[:nl], "`") {
+		src = lx.text[i : i+len(src)+nl+1]
+	}
+
+	return lx.openStyle(i, "```", src)
+}
+
+// openLink opens a link or custom emoji when its "](url)" suffix is found.
+func (lx *markdownLexer) openLink(i int) int {
+	opener := "["
+	if lx.text[i] == '!' {
+		opener = "!["
+	}
+
+	textStart := i + len(opener)
+
+	closeBracket := indexUnescaped(lx.text, textStart, ']')
+	if closeBracket < 0 || !strings.HasPrefix(lx.text[closeBracket:], "](") {
+		return lx.char(i)
+	}
+
+	closeParen := indexUnescaped(lx.text, closeBracket+len("]("), ')')
+	if closeParen < 0 {
+		return lx.char(i)
+	}
+
+	suffix := lx.text[closeBracket : closeParen+1]
+	lx.linkEnds[closeBracket] = suffix
+	lx.open = append(lx.open, opener)
+	lx.atoms = append(lx.atoms, atom{kind: atomOpen, src: opener, closer: suffix})
+
+	return textStart
+}
+
+func (lx *markdownLexer) top() string {
+	if len(lx.open) == 0 {
+		return ""
+	}
+
+	return lx.open[len(lx.open)-1]
+}
+
+func (lx *markdownLexer) pop() {
+	if len(lx.open) > 0 {
+		lx.open = lx.open[:len(lx.open)-1]
+	}
+}
+
+func (lx *markdownLexer) isOpen(delim string) bool {
+	return slices.Contains(lx.open, delim)
+}
+
+// indexUnescaped returns the index of the first c at or after from that is not
+// escaped with a backslash, or -1.
+func indexUnescaped(s string, from int, c byte) int {
+	for i := from; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case c:
+			return i
+		}
+	}
+
+	return -1
+}
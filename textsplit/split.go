@@ -0,0 +1,308 @@
+// Package textsplit splits long Telegram texts into parts that fit message
+// and caption limits without breaking formatting.
+//
+// Lengths are measured like Telegram measures them: in UTF-16 code units of
+// the visible text, after markup is parsed. Parts break on paragraph, line
+// and word boundaries when possible.
+package textsplit
+
+import (
+	"unicode/utf16"
+
+	"github.com/tgbotkit/client"
+)
+
+const (
+	// MessageLimit is the maximum length of a message text.
+	MessageLimit = 4096
+	// CaptionLimit is the maximum length of a media caption.
+	CaptionLimit = 1024
+)
+
+// Chunk is one part of a text split with entities. Entity offsets are
+// relative to the chunk.
+type Chunk struct {
+	Text     string
+	Entities []client.MessageEntity
+}
+
+// Plain splits text without formatting into parts of at most limit units.
+func Plain(text string, limit int) []string {
+	chunks := Entities(text, nil, limit)
+
+	parts := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		parts = append(parts, chunk.Text)
+	}
+
+	return parts
+}
+
+// Entities splits text and its entities into parts of at most limit units.
+// Entities crossing a split are cut into one entity per part.
+func Entities(text string, entities []client.MessageEntity, limit int) []Chunk {
+	return splitEntities(text, entities, limit, limit)
+}
+
+// CaptionEntities splits a caption and its entities for a media message
+// followed by text messages: the first part fits CaptionLimit and the others
+// MessageLimit.
+func CaptionEntities(text string, entities []client.MessageEntity) []Chunk {
+	return splitEntities(text, entities, CaptionLimit, MessageLimit)
+}
+
+func splitEntities(text string, entities []client.MessageEntity, first, rest int) []Chunk {
+	atoms := textAtoms(text)
+
+	offsets := make([]int, len(atoms)+1)
+	for i, a := range atoms {
+		offsets[i+1] = offsets[i] + a.width
+	}
+
+	segments := splitAtoms(atoms, first, rest)
+	chunks := make([]Chunk, 0, len(segments))
+
+	for _, seg := range segments {
+		chunks = append(chunks, Chunk{
+			Text:     renderAtoms(atoms[seg.start:seg.end]),
+			Entities: clipEntities(entities, offsets[seg.start], offsets[seg.end]),
+		})
+	}
+
+	return chunks
+}
+
+// clipEntities returns the parts of entities inside [start, end), rebased to start.
+func clipEntities(entities []client.MessageEntity, start, end int) []client.MessageEntity {
+	out := make([]client.MessageEntity, 0, len(entities))
+
+	for _, entity := range entities {
+		from := max(entity.Offset, start)
+		to := min(entity.Offset+entity.Length, end)
+
+		if from >= to {
+			continue
+		}
+
+		clipped := entity
+		clipped.Offset = from - start
+		clipped.Length = to - from
+		out = append(out, clipped)
+	}
+
+	return out
+}
+
+type atomKind int
+
+const (
+	atomText atomKind = iota
+	atomOpen
+	atomClose
+)
+
+// atom is an indivisible piece of source text: a visible character, possibly
+// written as an escape sequence, or a zero-width markup token.
+type atom struct {
+	kind  atomKind
+	src   string
+	width int
+	// char is the visible character of a text atom.
+	char rune
+	// closer is the markup that closes an open atom.
+	closer string
+}
+
+func textAtoms(text string) []atom {
+	atoms := make([]atom, 0, len(text))
+	for _, r := range text {
+		atoms = append(atoms, runeAtom(string(r), r))
+	}
+
+	return atoms
+}
+
+func runeAtom(src string, r rune) atom {
+	return atom{kind: atomText, src: src, width: max(utf16.RuneLen(r), 1), char: r}
+}
+
+// segment is one output part: atoms[start:end], preceded by the reopened
+// markup of open and followed by the closers of close.
+type segment struct {
+	start, end int
+	open       []atom
+	close      []atom
+}
+
+// breakRank orders break points: paragraph breaks over line breaks over spaces.
+type breakRank int
+
+const (
+	noBreak breakRank = iota
+	wordBreak
+	lineBreak
+	paragraphBreak
+)
+
+type breakPoint struct {
+	index int
+	width int
+	skip  int
+	stack []atom
+}
+
+// minLimit keeps room for at least one character of any width per part.
+const minLimit = 2
+
+// splitAtoms cuts atoms into segments whose visible width is at most first
+// for the first segment and rest for the others.
+func splitAtoms(atoms []atom, first, rest int) []segment {
+	limit := max(first, minLimit)
+
+	var (
+		segments []segment
+		stack    []atom
+	)
+
+	for start := 0; start < len(atoms); {
+		seg := segment{start: start, open: stack}
+		local := append([]atom(nil), stack...)
+		candidates := map[breakRank]breakPoint{}
+		width := 0
+		i := start
+
+		for ; i < len(atoms); i++ {
+			// A separator right at the limit is still a valid break.
+			if rank, skip := breakAt(atoms, i); rank != noBreak && i > start {
+				candidates[rank] = breakPoint{index: i, width: width, skip: skip, stack: append([]atom(nil), local...)}
+			}
+
+			a := atoms[i]
+			if a.kind == atomText && width+a.width > limit {
+				break
+			}
+
+			local = applyAtom(local, a)
+			width += a.width
+		}
+
+		if i == len(atoms) {
+			seg.end = i
+			segments = append(segments, seg)
+
+			break
+		}
+
+		bp := chooseBreak(candidates, limit)
+		if bp.index <= start {
+			bp = breakPoint{index: max(i, start+1), stack: local}
+		}
+
+		seg.end = bp.index
+		seg.close = bp.stack
+		segments = append(segments, seg)
+
+		stack = bp.stack
+		start = bp.index + bp.skip
+		limit = max(rest, minLimit)
+	}
+
+	return segments
+}
+
+// chooseBreak prefers the strongest break in the second half of the part,
+// falling back to the latest break of any kind.
+func chooseBreak(candidates map[breakRank]breakPoint, limit int) breakPoint {
+	for rank := paragraphBreak; rank > noBreak; rank-- {
+		if bp, ok := candidates[rank]; ok && bp.width >= limit/2 {
+			return bp
+		}
+	}
+
+	var latest breakPoint
+	for _, bp := range candidates {
+		if bp.index > latest.index {
+			latest = bp
+		}
+	}
+
+	return latest
+}
+
+// breakAt reports whether a part may end before atoms[i] and how many
+// separator atoms the next part skips.
+func breakAt(atoms []atom, i int) (breakRank, int) {
+	a := atoms[i]
+	if a.kind != atomText {
+		return noBreak, 0
+	}
+
+	switch a.char {
+	case '\n':
+		n := 1
+		for i+n < len(atoms) && atoms[i+n].kind == atomText && atoms[i+n].char == '\n' {
+			n++
+		}
+
+		if n > 1 {
+			return paragraphBreak, n
+		}
+
+		return lineBreak, 1
+	case ' ':
+		return wordBreak, 1
+	default:
+		return noBreak, 0
+	}
+}
+
+func applyAtom(stack []atom, a atom) []atom {
+	switch a.kind {
+	case atomOpen:
+		return append(stack, a)
+	case atomClose:
+		if len(stack) > 0 {
+			return stack[:len(stack)-1]
+		}
+	case atomText:
+	}
+
+	return stack
+}
+
+func renderAtoms(atoms []atom) string {
+	size := 0
+	for _, a := range atoms {
+		size += len(a.src)
+	}
+
+	buf := make([]byte, 0, size)
+	for _, a := range atoms {
+		buf = append(buf, a.src...)
+	}
+
+	return string(buf)
+}
+
+// renderMarkup renders segments, closing markup left open at each split and
+// reopening it at the start of the next part.
+func renderMarkup(atoms []atom, segments []segment) []string {
+	parts := make([]string, 0, len(segments))
+
+	for _, seg := range segments {
+		var buf []byte
+		for _, a := range seg.open {
+			buf = append(buf, a.src...)
+		}
+
+		buf = append(buf, renderAtoms(atoms[seg.start:seg.end])...)
+
+		for i := len(seg.close) - 1; i >= 0; i-- {
+			buf = append(buf, seg.close[i].closer...)
+		}
+
+		parts = append(parts, string(buf))
+	}
+
+	return parts
+}
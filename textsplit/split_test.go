@@ -0,0 +1,204 @@
+package textsplit_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/textsplit"
+)
+
+func TestPlainPrefersParagraphsThenLinesThenWords(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "fits",
+			text:  "short text",
+			limit: 20,
+			want:  []string{"short text"},
+		},
+		{
+			name:  "paragraph",
+			text:  "first paragraph\nline\n\nsecond",
+			limit: 22,
+			want:  []string{"first paragraph\nline", "second"},
+		},
+		{
+			name:  "line",
+			text:  "one two three\nfour five",
+			limit: 16,
+			want:  []string{"one two three", "four five"},
+		},
+		{
+			name:  "word",
+			text:  "alpha beta gamma delta",
+			limit: 12,
+			want:  []string{"alpha beta", "gamma delta"},
+		},
+		{
+			name:  "hard break",
+			text:  "abcdefghij",
+			limit: 4,
+			want:  []string{"abcd", "efgh", "ij"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := textsplit.Plain(tt.text, tt.limit)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("Plain()=%q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainMeasuresUTF16(t *testing.T) {
+	t.Parallel()
+
+	// Each emoji is two UTF-16 units, so only two fit in five units.
+	got := textsplit.Plain("😀😀😀", 5)
+	if len(got) != 2 || got[0] != "😀😀" || got[1] != "😀" {
+		t.Fatalf("Plain()=%q, want [😀😀 😀]", got)
+	}
+}
+
+func TestEntitiesClipsAndRebases(t *testing.T) {
+	t.Parallel()
+
+	text := "hello world again"
+	entities := []client.MessageEntity{
+		{Type: "bold", Offset: 0, Length: 17},
+		{Type: "italic", Offset: 12, Length: 5},
+	}
+
+	chunks := textsplit.Entities(text, entities, 11)
+	if len(chunks) != 2 {
+		t.Fatalf("chunks=%d, want 2: %+v", len(chunks), chunks)
+	}
+	if chunks[0].Text != "hello world" || chunks[1].Text != "again" {
+		t.Fatalf("texts=%q,%q, want hello world,again", chunks[0].Text, chunks[1].Text)
+	}
+
+	first := chunks[0].Entities
+	if len(first) != 1 || first[0].Type != "bold" || first[0].Offset != 0 || first[0].Length != 11 {
+		t.Fatalf("first entities=%+v, want bold 0+11", first)
+	}
+
+	second := chunks[1].Entities
+	if len(second) != 2 || second[0].Offset != 0 || second[0].Length != 5 || second[1].Offset != 0 || second[1].Length != 5 {
+		t.Fatalf("second entities=%+v, want bold and italic 0+5", second)
+	}
+}
+
+func TestHTMLKeepsTagsBalanced(t *testing.T) {
+	t.Parallel()
+
+	text := `<b>bold <a href="https://x.y">linked words</a> tail</b> &amp; more`
+
+	got := textsplit.HTML(text, 14)
+	want := []string{
+		`<b>bold <a href="https://x.y">linked</a></b>`,
+		`<b><a href="https://x.y">words</a> tail</b> &amp;`,
+		`more`,
+	}
+
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("HTML()=\n%q\nwant\n%q", got, want)
+	}
+
+	for _, part := range got {
+		if n := visibleHTMLLength(part); n > 14 {
+			t.Fatalf("part %q has %d visible units, want <= 14", part, n)
+		}
+	}
+}
+
+func TestMarkdownV2KeepsStylesBalanced(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "nested styles",
+			text:  `*bold _italic words_ end* \. done`,
+			limit: 12,
+			want:  []string{`*bold _italic_*`, `*_words_ end* \.`, `done`},
+		},
+		{
+			name:  "link",
+			text:  `[click this link](https://x.y/a\)b) now`,
+			limit: 10,
+			want:  []string{`[click this](https://x.y/a\)b)`, `[link](https://x.y/a\)b) now`},
+		},
+		{
+			name:  "code block keeps language",
+			text:  "```go\nline one\nline two\n```",
+			limit: 10,
+			want:  []string{"```go\nline one```", "```go\nline two\n```"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := textsplit.MarkdownV2(tt.text, tt.limit)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("MarkdownV2()=\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaptionHTMLUsesCaptionLimitFirst(t *testing.T) {
+	t.Parallel()
+
+	text := "<b>" + strings.Repeat("word ", 1000) + "</b>"
+
+	got := textsplit.CaptionHTML(text)
+	if len(got) != 2 {
+		t.Fatalf("CaptionHTML() returned %d parts, want 2", len(got))
+	}
+
+	if n := visibleHTMLLength(got[0]); n > textsplit.CaptionLimit || n < textsplit.CaptionLimit/2 {
+		t.Fatalf("first part has %d visible units, want up to %d", n, textsplit.CaptionLimit)
+	}
+	if n := visibleHTMLLength(got[1]); n > textsplit.MessageLimit {
+		t.Fatalf("second part has %d visible units, want <= %d", n, textsplit.MessageLimit)
+	}
+	if !strings.HasPrefix(got[1], "<b>") || !strings.HasSuffix(got[1], "</b>") {
+		t.Fatalf("second part %q does not reopen the bold tag", got[1])
+	}
+}
+
+func visibleHTMLLength(s string) int {
+	inTag := false
+	n := 0
+
+	for _, r := range strings.ReplaceAll(s, "&amp;", "&") {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>':
+			inTag = false
+		case !inTag:
+			n += utf16.RuneLen(r)
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,82 @@
+package throttle
+
+import "time"
+
+// bucket is a token bucket that can be paused after a 429 response. A nil
+// bucket is always ready.
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+	paused time.Time
+}
+
+func newBucket(limit Limit, now time.Time) *bucket {
+	return &bucket{limit: limit, tokens: float64(max(limit.Burst, 1)), last: now}
+}
+
+func (b *bucket) unlimited() bool {
+	return b.limit.Interval < 0
+}
+
+func (b *bucket) refill(now time.Time) {
+	if b.unlimited() || !now.After(b.last) {
+		return
+	}
+
+	b.tokens = min(float64(max(b.limit.Burst, 1)), b.tokens+float64(now.Sub(b.last))/float64(b.limit.Interval))
+	b.last = now
+}
+
+// readyAt returns when the bucket will have a token.
+func (b *bucket) readyAt(now time.Time) time.Time {
+	if b == nil {
+		return now
+	}
+
+	at := now
+
+	if !b.unlimited() {
+		b.refill(now)
+
+		if b.tokens < 1 {
+			at = now.Add(time.Duration((1 - b.tokens) * float64(b.limit.Interval)))
+		}
+	}
+
+	if b.paused.After(at) {
+		at = b.paused
+	}
+
+	return at
+}
+
+// pausedAt returns when the pause of the bucket ends, ignoring its tokens.
+func (b *bucket) pausedAt(now time.Time) time.Time {
+	if b == nil || !b.paused.After(now) {
+		return now
+	}
+
+	return b.paused
+}
+
+func (b *bucket) take() {
+	if b == nil || b.unlimited() {
+		return
+	}
+
+	b.tokens--
+}
+
+func (b *bucket) pauseUntil(until time.Time) {
+	if until.After(b.paused) {
+		b.paused = until
+	}
+}
+
+// idle reports whether the bucket is full and not paused.
+func (b *bucket) idle(now time.Time) bool {
+	b.refill(now)
+
+	return (b.unlimited() || b.tokens >= float64(max(b.limit.Burst, 1))) && !now.Before(b.paused)
+}
@@ -0,0 +1,51 @@
+package throttle
+
+import (
+	"context"
+	"strconv"
+)
+
+// Priority orders requests waiting for the same limits. Higher priorities go
+// first; requests of equal priority go in arrival order.
+type Priority int
+
+const (
+	// PriorityLow is for bulk sends such as broadcasts.
+	PriorityLow Priority = -1
+	// PriorityNormal is the priority of requests without one set.
+	PriorityNormal Priority = 0
+	// PriorityHigh is for interactive replies that users are waiting for.
+	PriorityHigh Priority = 1
+)
+
+type (
+	priorityKey struct{}
+	chatKey     struct{}
+)
+
+// WithPriority returns a context whose requests wait in the given priority lane.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFrom returns the priority set on ctx, or PriorityNormal.
+func PriorityFrom(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return priority
+	}
+
+	return PriorityNormal
+}
+
+// WithChat returns a context whose requests count against the limit of
+// chatID. The Limiter reads chat_id from JSON bodies by itself; set the chat
+// for streamed multipart uploads, whose body cannot be inspected.
+func WithChat(ctx context.Context, chatID int64) context.Context {
+	return context.WithValue(ctx, chatKey{}, strconv.FormatInt(chatID, 10))
+}
+
+func chatFrom(ctx context.Context) (string, bool) {
+	chat, ok := ctx.Value(chatKey{}).(string)
+
+	return chat, ok
+}
@@ -0,0 +1,72 @@
+// Code generated by options-gen v0.55.3. DO NOT EDIT.
+
+package throttle
+
+import (
+	fmt461e464ebed9 "fmt"
+
+	errors461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/errors"
+	validator461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/validator"
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+type OptOptionsSetter func(o *Options)
+
+func NewOptions(
+	options ...OptOptionsSetter,
+) Options {
+	var o Options
+
+	// Setting defaults from field tag (if present)
+
+	o.maxRetries = 3
+
+	for _, opt := range options {
+		opt(&o)
+	}
+	return o
+}
+
+// doer sends the requests the limiter lets through. Defaults to an http.Client.
+func WithDoer(opt client.HttpRequestDoer) OptOptionsSetter {
+	return func(o *Options) { o.doer = opt }
+}
+
+// globalLimit bounds all requests of the bot. Defaults to 30 per second.
+func WithGlobalLimit(opt Limit) OptOptionsSetter {
+	return func(o *Options) { o.globalLimit = opt }
+}
+
+// privateChatLimit bounds sends to one private chat. Defaults to 1 per second.
+func WithPrivateChatLimit(opt Limit) OptOptionsSetter {
+	return func(o *Options) { o.privateChatLimit = opt }
+}
+
+// groupChatLimit bounds sends to one group or channel. Defaults to 20 per minute.
+func WithGroupChatLimit(opt Limit) OptOptionsSetter {
+	return func(o *Options) { o.groupChatLimit = opt }
+}
+
+// maxRetries is how many times a request rejected with 429 is requeued.
+func WithMaxRetries(opt int) OptOptionsSetter {
+	return func(o *Options) { o.maxRetries = opt }
+}
+
+// logger is the logger to use.
+func WithLogger(opt logger.Logger) OptOptionsSetter {
+	return func(o *Options) { o.logger = opt }
+}
+
+func (o *Options) Validate() error {
+	errs := new(errors461e464ebed9.ValidationErrors)
+	errs.Add(errors461e464ebed9.NewValidationError("maxRetries", _validate_Options_maxRetries(o)))
+	return errs.AsError()
+}
+
+func _validate_Options_maxRetries(o *Options) error {
+	if err := validator461e464ebed9.GetValidatorFor(o).Var(o.maxRetries, "gte=0"); err != nil {
+		return fmt461e464ebed9.Errorf("field `maxRetries` did not pass the test: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package throttle
+
+import (
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+//go:generate go tool options-gen -out-filename=options.gen.go -from-struct=Options
+
+// Options is the options for the Limiter.
+type Options struct {
+	// doer sends the requests the limiter lets through. Defaults to an http.Client.
+	doer client.HttpRequestDoer
+	// globalLimit bounds all requests of the bot. Defaults to 30 per second.
+	globalLimit Limit
+	// privateChatLimit bounds sends to one private chat. Defaults to 1 per second.
+	privateChatLimit Limit
+	// groupChatLimit bounds sends to one group or channel. Defaults to 20 per minute.
+	groupChatLimit Limit
+	// maxRetries is how many times a request rejected with 429 is requeued.
+	maxRetries int `default:"3" validate:"gte=0"`
+	// logger is the logger to use.
+	logger logger.Logger
+}
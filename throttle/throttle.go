@@ -0,0 +1,442 @@
+// Package throttle schedules outgoing Bot API requests within Telegram's flood
+// limits.
+//
+// Telegram allows about 30 messages per second per bot, one message per second
+// to a private chat and 20 messages per minute to a group. Going faster gets
+// requests rejected with 429 Too Many Requests and a retry_after delay. A
+// Limiter sits between the generated client and the network: it holds every
+// request until the global and per-chat token buckets allow it, serves higher
+// priority requests first, and requeues requests rejected with 429 after the
+// delay Telegram asked for.
+//
+// Pass the Limiter to the generated client with client.WithHTTPClient, or to
+// the bot with runtime.WithLimiter, so Responder and raw Bot.Client() calls
+// share the same limits.
+package throttle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/botapi"
+	"github.com/tgbotkit/runtime/logger"
+)
+
+// Limit is a token bucket: up to Burst requests at once, refilled at one
+// request per Interval. A zero Limit is replaced by the default for its
+// scope; use NoLimit to disable a scope.
+type Limit struct {
+	Interval time.Duration
+	Burst    int
+}
+
+// NoLimit disables a limit.
+var NoLimit = Limit{Interval: -1}
+
+const (
+	telegramGroupsPerMinute = 20
+	telegramGlobalPerSecond = 30
+	// maxErrorBody bounds how much of a 429 response is read to find retry_after.
+	maxErrorBody = 64 << 10
+	// pruneThreshold is the number of chat buckets kept before idle ones are dropped.
+	pruneThreshold = 1024
+	// pruneGrowth spaces prunes out: the next one runs once the map has grown by this factor.
+	pruneGrowth = 2
+)
+
+var (
+	defaultGlobalLimit      = Limit{Interval: time.Second / telegramGlobalPerSecond, Burst: telegramGlobalPerSecond}
+	defaultPrivateChatLimit = Limit{Interval: time.Second, Burst: 1}
+	defaultGroupChatLimit   = Limit{Interval: time.Minute / telegramGroupsPerMinute, Burst: 1}
+)
+
+// Limiter is a client.HttpRequestDoer that sends requests within flood limits.
+type Limiter struct {
+	opts Options
+	log  logger.Logger
+
+	mu      sync.Mutex
+	global  *bucket
+	chats   map[string]*bucket
+	pruneAt int
+	waiting []*waiter
+	timer   *time.Timer
+}
+
+var _ client.HttpRequestDoer = (*Limiter)(nil)
+
+type waiter struct {
+	chat string
+	// limited is set when the request counts against the limit of chat, and
+	// not only waits out its pause.
+	limited  bool
+	priority Priority
+	ready    chan struct{}
+}
+
+// New creates a new Limiter with the given options.
+func New(opts Options) (*Limiter, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid limiter options: %w", err)
+	}
+
+	if opts.doer == nil {
+		opts.doer = &http.Client{}
+	}
+
+	if opts.logger == nil {
+		opts.logger = logger.NewNop()
+	}
+
+	opts.globalLimit = withDefault(opts.globalLimit, defaultGlobalLimit)
+	opts.privateChatLimit = withDefault(opts.privateChatLimit, defaultPrivateChatLimit)
+	opts.groupChatLimit = withDefault(opts.groupChatLimit, defaultGroupChatLimit)
+
+	return &Limiter{
+		opts:    opts,
+		log:     opts.logger,
+		global:  newBucket(opts.globalLimit, time.Now()),
+		chats:   make(map[string]*bucket),
+		pruneAt: pruneThreshold,
+	}, nil
+}
+
+// Do waits until the request is within limits and sends it. Requests rejected
+// with 429 are requeued after retry_after, up to the configured number of
+// retries, when their body can be sent again. The last 429 response is
+// returned as is.
+func (l *Limiter) Do(req *http.Request) (*http.Response, error) {
+	method := path.Base(req.URL.Path)
+	if !isLimited(method) {
+		return l.opts.doer.Do(req)
+	}
+
+	ctx := req.Context()
+	chat, limited := requestScope(method, req)
+	priority := PriorityFrom(ctx)
+
+	for attempt := 0; ; attempt++ {
+		if err := l.wait(ctx, chat, limited, priority); err != nil {
+			return nil, err
+		}
+
+		resp, err := l.opts.doer.Do(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+
+		retryAfter := readRetryAfter(resp)
+		l.pause(chat, retryAfter)
+
+		if attempt >= l.opts.maxRetries || req.GetBody == nil {
+			return resp, nil
+		}
+
+		l.log.Warnf("%s: flood limit hit, retrying in %s", method, retryAfter)
+
+		_ = resp.Body.Close()
+
+		req, err = rewind(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Pending returns the number of requests waiting to be sent.
+func (l *Limiter) Pending() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.waiting)
+}
+
+// wait blocks until a request to chat may be sent.
+func (l *Limiter) wait(ctx context.Context, chat string, limited bool, priority Priority) error {
+	w := &waiter{chat: chat, limited: limited, priority: priority, ready: make(chan struct{})}
+
+	l.mu.Lock()
+	// Waiters stay ordered by priority, then by arrival.
+	at := slices.IndexFunc(l.waiting, func(other *waiter) bool { return other.priority < priority })
+	if at < 0 {
+		at = len(l.waiting)
+	}
+
+	l.waiting = slices.Insert(l.waiting, at, w)
+	l.dispatchLocked()
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.waiting = slices.DeleteFunc(l.waiting, func(other *waiter) bool { return other == w })
+		l.mu.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+// dispatchLocked releases every waiter whose buckets have a token and arms the
+// timer for the earliest moment another one may go.
+func (l *Limiter) dispatchLocked() {
+	now := time.Now()
+	kept := l.waiting[:0]
+
+	var next time.Time
+
+	for i, w := range l.waiting {
+		if at := l.global.readyAt(now); at.After(now) {
+			// Nothing else can go before the global bucket refills.
+			kept = append(kept, l.waiting[i:]...)
+			next = earliest(next, at)
+
+			break
+		}
+
+		chat := l.chatBucket(w.chat, now)
+		if at := w.readyAt(chat, now); at.After(now) {
+			// Requests to other chats are not held up by this one.
+			kept = append(kept, w)
+			next = earliest(next, at)
+
+			continue
+		}
+
+		l.global.take()
+
+		if w.limited {
+			chat.take()
+		}
+
+		close(w.ready)
+	}
+
+	clear(l.waiting[len(kept):])
+	l.waiting = kept
+
+	if len(l.waiting) > 0 && !next.IsZero() {
+		l.scheduleLocked(next.Sub(now))
+	}
+}
+
+// readyAt returns when the waiter may go as far as the bucket of its chat is
+// concerned.
+func (w *waiter) readyAt(chat *bucket, now time.Time) time.Time {
+	if w.limited {
+		return chat.readyAt(now)
+	}
+
+	return chat.pausedAt(now)
+}
+
+func (l *Limiter) scheduleLocked(delay time.Duration) {
+	if l.timer == nil {
+		l.timer = time.AfterFunc(delay, func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			l.dispatchLocked()
+		})
+
+		return
+	}
+
+	l.timer.Reset(delay)
+}
+
+// pause holds requests to chat, or all requests when chat is empty, for delay.
+func (l *Limiter) pause(chat string, delay time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	b := l.global
+	if chat != "" {
+		b = l.chatBucket(chat, now)
+	}
+
+	b.pauseUntil(now.Add(delay))
+}
+
+// chatBucket returns the bucket of chat, or nil for requests not bound to a chat.
+func (l *Limiter) chatBucket(chat string, now time.Time) *bucket {
+	if chat == "" {
+		return nil
+	}
+
+	if b, ok := l.chats[chat]; ok {
+		return b
+	}
+
+	if len(l.chats) >= l.pruneAt {
+		l.pruneLocked(now)
+	}
+
+	limit := l.opts.groupChatLimit
+	if isPrivateChat(chat) {
+		limit = l.opts.privateChatLimit
+	}
+
+	b := newBucket(limit, now)
+	l.chats[chat] = b
+
+	return b
+}
+
+// pruneLocked drops chat buckets that are full and not paused, since a new
+// bucket would behave the same.
+func (l *Limiter) pruneLocked(now time.Time) {
+	for chat, b := range l.chats {
+		if b.idle(now) && !slices.ContainsFunc(l.waiting, func(w *waiter) bool { return w.chat == chat }) {
+			delete(l.chats, chat)
+		}
+	}
+
+	l.pruneAt = max(pruneThreshold, pruneGrowth*len(l.chats))
+}
+
+// isLimited reports whether method counts against the global limit. Long
+// polling waits on the server and does not send anything.
+func isLimited(method string) bool {
+	return method != "getUpdates"
+}
+
+// isChatLimited reports whether method posts into a chat and counts against
+// that chat's limit.
+func isChatLimited(method string) bool {
+	if method == "sendChatAction" {
+		return false
+	}
+
+	for _, prefix := range []string{"send", "copyMessage", "forwardMessage"} {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// requestScope returns the chat a request is bound to, if any, and whether it
+// counts against that chat's limit. Other requests acting on a chat do not,
+// but a 429 on them pauses only that chat.
+func requestScope(method string, req *http.Request) (string, bool) {
+	if isChatLimited(method) {
+		return requestChat(req), true
+	}
+
+	if isChatScoped(method) {
+		return requestChat(req), false
+	}
+
+	return "", false
+}
+
+// isChatScoped reports whether method acts on a chat given by chat_id, such
+// as editing, deleting or pinning one of its messages, without posting into it.
+func isChatScoped(method string) bool {
+	for _, prefix := range []string{"edit", "delete", "pin", "unpin"} {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// requestChat returns the chat a request posts to: the one set on its context,
+// or the chat_id of a JSON body that can be read again.
+func requestChat(req *http.Request) string {
+	if chat, ok := chatFrom(req.Context()); ok {
+		return chat
+	}
+
+	if req.GetBody == nil || !strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		return ""
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	var fields struct {
+		ChatID json.RawMessage `json:"chat_id"`
+	}
+
+	if err := json.NewDecoder(body).Decode(&fields); err != nil {
+		return ""
+	}
+
+	chat := string(fields.ChatID)
+	if unquoted, err := strconv.Unquote(chat); err == nil {
+		chat = unquoted
+	}
+
+	return chat
+}
+
+// isPrivateChat reports whether chat is a user: users have positive IDs, while
+// groups and channels have negative IDs or @usernames.
+func isPrivateChat(chat string) bool {
+	id, err := strconv.ParseInt(chat, 10, 64)
+
+	return err == nil && id > 0
+}
+
+// readRetryAfter returns the delay a 429 response asks for, leaving the body
+// readable for the caller.
+func readRetryAfter(resp *http.Response) time.Duration {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	if apiErr := botapi.FromResponse(resp.StatusCode, data); apiErr != nil && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+
+	return time.Second
+}
+
+func rewind(req *http.Request) (*http.Request, error) {
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("rewind request body: %w", err)
+	}
+
+	next := req.Clone(req.Context())
+	next.Body = body
+
+	return next, nil
+}
+
+func withDefault(limit, fallback Limit) Limit {
+	if limit.Interval == 0 {
+		return fallback
+	}
+
+	return limit
+}
+
+func earliest(current, at time.Time) time.Time {
+	if current.IsZero() || at.Before(current) {
+		return at
+	}
+
+	return current
+}
@@ -0,0 +1,248 @@
+package throttle_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tgbotkit/runtime/throttle"
+)
+
+type recordingDoer struct {
+	mu    sync.Mutex
+	calls []string
+	times []time.Time
+	// respond returns the status and body for a call; nil means 200 {"ok":true}.
+	respond func(call int) (int, string)
+}
+
+func (d *recordingDoer) Do(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+
+	d.mu.Lock()
+	d.calls = append(d.calls, req.URL.Path+" "+string(body))
+	d.times = append(d.times, time.Now())
+	call := len(d.calls)
+	d.mu.Unlock()
+
+	status, respBody := http.StatusOK, `{"ok":true}`
+	if d.respond != nil {
+		status, respBody = d.respond(call)
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(respBody)),
+		Header:     http.Header{},
+	}, nil
+}
+
+func post(ctx context.Context, t *testing.T, limiter *throttle.Limiter, method, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.telegram.org/botTOKEN/"+method,
+		bytes.NewReader([]byte(body)))
+	if err != nil {
+		t.Fatalf("NewRequest() unexpected error: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := limiter.Do(req)
+	if err != nil {
+		t.Fatalf("Do(%s) unexpected error: %v", method, err)
+	}
+
+	return resp
+}
+
+func newLimiter(t *testing.T, doer *recordingDoer, opts ...throttle.OptOptionsSetter) *throttle.Limiter {
+	t.Helper()
+
+	limiter, err := throttle.New(throttle.NewOptions(append([]throttle.OptOptionsSetter{throttle.WithDoer(doer)}, opts...)...))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	return limiter
+}
+
+func TestLimiterSpacesSendsPerChat(t *testing.T) {
+	t.Parallel()
+
+	const interval = 80 * time.Millisecond
+
+	doer := &recordingDoer{}
+	limiter := newLimiter(t, doer,
+		throttle.WithPrivateChatLimit(throttle.Limit{Interval: interval, Burst: 1}),
+		throttle.WithGlobalLimit(throttle.NoLimit),
+	)
+
+	start := time.Now()
+	post(t.Context(), t, limiter, "sendMessage", `{"chat_id":1,"text":"a"}`)
+	post(t.Context(), t, limiter, "sendMessage", `{"chat_id":2,"text":"b"}`)
+
+	if elapsed := time.Since(start); elapsed >= interval/2 {
+		t.Fatalf("sends to different chats took %s, want no wait", elapsed)
+	}
+
+	post(t.Context(), t, limiter, "sendMessage", `{"chat_id":1,"text":"c"}`)
+
+	if gap := doer.times[2].Sub(doer.times[0]); gap < interval*3/4 {
+		t.Fatalf("second send to chat 1 after %s, want about %s", gap, interval)
+	}
+}
+
+func TestLimiterServesHigherPriorityFirst(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingDoer{}
+	limiter := newLimiter(t, doer, throttle.WithGlobalLimit(throttle.Limit{Interval: 50 * time.Millisecond, Burst: 1}))
+
+	// Use up the only token so the next requests queue.
+	post(t.Context(), t, limiter, "getMe", `{}`)
+
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+		post(throttle.WithPriority(t.Context(), throttle.PriorityLow), t, limiter, "getChat", `{"n":"low"}`)
+	})
+
+	waitPending(t, limiter, 1)
+
+	wg.Go(func() {
+		post(throttle.WithPriority(t.Context(), throttle.PriorityHigh), t, limiter, "getChat", `{"n":"high"}`)
+	})
+
+	wg.Wait()
+
+	if len(doer.calls) != 3 || !strings.Contains(doer.calls[1], "high") || !strings.Contains(doer.calls[2], "low") {
+		t.Fatalf("calls=%q, want high before low", doer.calls)
+	}
+}
+
+func TestLimiterRequeuesAfterRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingDoer{respond: func(call int) (int, string) {
+		if call == 1 {
+			return http.StatusTooManyRequests,
+				`{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":1}}`
+		}
+
+		return http.StatusOK, `{"ok":true}`
+	}}
+	limiter := newLimiter(t, doer, throttle.WithGroupChatLimit(throttle.NoLimit))
+
+	resp := post(t.Context(), t, limiter, "sendMessage", `{"chat_id":-100,"text":"hi"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode=%d, want 200", resp.StatusCode)
+	}
+
+	if len(doer.calls) != 2 || doer.calls[0] != doer.calls[1] {
+		t.Fatalf("calls=%q, want the same request twice", doer.calls)
+	}
+
+	if gap := doer.times[1].Sub(doer.times[0]); gap < 900*time.Millisecond {
+		t.Fatalf("retried after %s, want retry_after of 1s", gap)
+	}
+}
+
+func TestLimiterPausesOnlyTheChatOfAnEdit(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingDoer{respond: func(call int) (int, string) {
+		if call == 1 {
+			return http.StatusTooManyRequests,
+				`{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":1}}`
+		}
+
+		return http.StatusOK, `{"ok":true}`
+	}}
+	limiter := newLimiter(t, doer, throttle.WithGroupChatLimit(throttle.NoLimit), throttle.WithMaxRetries(0))
+
+	edit := `{"chat_id":-100,"message_id":1,"text":"hi"}`
+	if resp := post(t.Context(), t, limiter, "editMessageText", edit); resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("StatusCode=%d, want 429", resp.StatusCode)
+	}
+
+	start := time.Now()
+	post(t.Context(), t, limiter, "sendMessage", `{"chat_id":-200,"text":"hi"}`)
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("send to another chat waited %s, want no pause", elapsed)
+	}
+
+	post(t.Context(), t, limiter, "editMessageText", edit)
+
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Fatalf("edit in the paused chat went after %s, want retry_after of 1s", elapsed)
+	}
+}
+
+func TestLimiterReturnsLastTooManyRequests(t *testing.T) {
+	t.Parallel()
+
+	const flood = `{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":1}}`
+
+	doer := &recordingDoer{respond: func(int) (int, string) { return http.StatusTooManyRequests, flood }}
+	limiter := newLimiter(t, doer, throttle.WithMaxRetries(0))
+
+	resp := post(t.Context(), t, limiter, "sendMessage", `{"chat_id":5,"text":"hi"}`)
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusTooManyRequests || string(body) != flood {
+		t.Fatalf("response=%d %s, want the 429 body", resp.StatusCode, body)
+	}
+
+	// The chat stays paused for retry_after.
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.telegram.org/botTOKEN/sendMessage",
+		strings.NewReader(`{"chat_id":5,"text":"again"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	if _, err := limiter.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Do() error=%v, want context.DeadlineExceeded while paused", err)
+	}
+
+	if limiter.Pending() != 0 {
+		t.Fatalf("Pending()=%d, want 0 after the caller gave up", limiter.Pending())
+	}
+}
+
+func TestLimiterDoesNotHoldLongPolling(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingDoer{}
+	limiter := newLimiter(t, doer, throttle.WithGlobalLimit(throttle.Limit{Interval: time.Hour, Burst: 1}))
+
+	post(t.Context(), t, limiter, "getMe", `{}`)
+
+	start := time.Now()
+	post(t.Context(), t, limiter, "getUpdates", `{}`)
+
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("getUpdates waited %s, want no wait", elapsed)
+	}
+}
+
+func waitPending(t *testing.T, limiter *throttle.Limiter, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for limiter.Pending() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Pending()=%d, want %d", limiter.Pending(), want)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
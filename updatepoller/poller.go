@@ -3,6 +3,7 @@ package updatepoller
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sync"
@@ -10,6 +11,8 @@ import (
 
 	"github.com/metalagman/appkit/lifecycle"
 	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/botapi"
+	"github.com/tgbotkit/runtime/internal/rawupdate"
 	"github.com/tgbotkit/runtime/logger"
 )
 
@@ -23,6 +26,7 @@ type Poller struct {
 	done   chan struct{}
 
 	updates          chan client.Update
+	raw              *rawupdate.Store
 	pendingOffset    int
 	hasPendingOffset bool
 }
@@ -48,6 +52,7 @@ func NewPoller(opts Options) (*Poller, error) {
 		opts:    opts,
 		log:     opts.logger,
 		updates: make(chan client.Update, opts.bufferSize),
+		raw:     rawupdate.NewStore(opts.bufferSize),
 	}, nil
 }
 
@@ -136,6 +141,12 @@ func (p *Poller) UpdateChan() <-chan client.Update {
 	return p.updates
 }
 
+// RawUpdate returns the raw JSON of an update delivered through UpdateChan and forgets it.
+// Raw JSON is kept for a bounded number of recent updates only.
+func (p *Poller) RawUpdate(updateID int) (json.RawMessage, bool) {
+	return p.raw.Take(updateID)
+}
+
 func (p *Poller) finish(done chan struct{}) {
 	p.mu.Lock()
 
@@ -199,7 +210,7 @@ func (p *Poller) fetchUpdates(ctx context.Context) ([]client.Update, bool) {
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		p.log.Errorf("fetch updates: %s", resp.Status())
+		p.log.Errorf("fetch updates: %v", botapi.FromResponse(resp.StatusCode(), resp.Body))
 
 		return nil, false
 	}
@@ -208,9 +219,31 @@ func (p *Poller) fetchUpdates(ctx context.Context) ([]client.Update, bool) {
 		return nil, true
 	}
 
+	p.rememberRaw(resp.Body, resp.JSON200.Result)
+
 	return resp.JSON200.Result, true
 }
 
+// rememberRaw keeps the raw JSON of each fetched update so fields unknown to the
+// generated client are not lost.
+func (p *Poller) rememberRaw(body []byte, updates []client.Update) {
+	if len(body) == 0 || len(updates) == 0 {
+		return
+	}
+
+	var envelope struct {
+		Result []json.RawMessage `json:"result"`
+	}
+
+	if err := json.Unmarshal(body, &envelope); err != nil || len(envelope.Result) != len(updates) {
+		return
+	}
+
+	for i, update := range updates {
+		p.raw.Put(update.UpdateId, envelope.Result[i])
+	}
+}
+
 func (p *Poller) getUpdatesContext(ctx context.Context) (context.Context, context.CancelFunc) {
 	if p.opts.requestTimeout <= 0 {
 		return ctx, func() {}
@@ -136,6 +136,34 @@ func TestPollerSavesLastUpdateIDAfterSuccessfulBatch(t *testing.T) {
 	}
 }
 
+func TestPollerKeepsRawUpdateJSON(t *testing.T) {
+	rawUpdate := `{"update_id":20,"future_field":{"id":1}}`
+	tgClient := &pollerMockClient{
+		getUpdatesFunc: func(_ context.Context, _ client.GetUpdatesJSONRequestBody) (*client.GetUpdatesResponse, error) {
+			resp := getUpdatesResponse([]client.Update{{UpdateId: 20}})
+			resp.Body = []byte(`{"ok":true,"result":[` + rawUpdate + `]}`)
+
+			return resp, nil
+		},
+	}
+
+	p, err := NewPoller(NewOptions(tgClient, WithOffsetStore(newPollerOffsetStore(0))))
+	if err != nil {
+		t.Fatalf("NewPoller() unexpected error: %v", err)
+	}
+	p.updates = make(chan client.Update, 1)
+
+	p.poll(context.Background())
+
+	raw, ok := p.RawUpdate(20)
+	if !ok {
+		t.Fatal("RawUpdate() ok=false, want true")
+	}
+	if string(raw) != rawUpdate {
+		t.Fatalf("raw=%s, want %s", raw, rawUpdate)
+	}
+}
+
 func TestPollerRetriesPendingOffsetBeforeFetchingMore(t *testing.T) {
 	store := newPollerOffsetStore(7)
 	store.failSave.Store(true)
@@ -4,6 +4,7 @@ package webhook
 
 import (
 	fmt461e464ebed9 "fmt"
+	"time"
 
 	errors461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/errors"
 	validator461e464ebed9 "github.com/kazhuravlev/options-gen/pkg/validator"
@@ -60,6 +61,12 @@ func WithMaxConnections(opt int) OptOptionsSetter {
 	return func(o *Options) { o.maxConnections = opt }
 }
 
+// replyTimeout is how long a request waits for handlers to Reply before
+// it is acknowledged without one. Zero acknowledges at once.
+func WithReplyTimeout(opt time.Duration) OptOptionsSetter {
+	return func(o *Options) { o.replyTimeout = opt }
+}
+
 func (o *Options) Validate() error {
 	errs := new(errors461e464ebed9.ValidationErrors)
 	errs.Add(errors461e464ebed9.NewValidationError("bufferSize", _validate_Options_bufferSize(o)))
@@ -1,7 +1,11 @@
 // Package webhook provides an implementation of UpdateSource using Telegram webhooks.
 package webhook
 
-import "github.com/tgbotkit/client"
+import (
+	"time"
+
+	"github.com/tgbotkit/client"
+)
 
 //go:generate go tool options-gen -out-filename=options.gen.go -from-struct=Options
 
@@ -15,6 +19,9 @@ type Options struct {
 	allowedUpdates     []string                            `option:"optional"`
 	dropPendingUpdates bool                                `option:"optional"`
 	maxConnections     int                                 `option:"optional" validate:"omitempty,min=1,max=100"`
+	// replyTimeout is how long a request waits for handlers to Reply before
+	// it is acknowledged without one. Zero acknowledges at once.
+	replyTimeout time.Duration `option:"optional"`
 
 	webhookRegistrationEnabled    bool `option:"-"`
 	webhookRegistrationConfigured bool `option:"-"`
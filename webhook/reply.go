@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// Reply answers the update being handled with a Bot API method call in the
+// webhook's HTTP response, which saves a request to Telegram. params are the
+// method's parameters, such as a client.SendMessageJSONRequestBody. Telegram
+// does not report the result of the call, so methods whose result the handler
+// needs must be called directly.
+//
+// Reply reports whether the call was taken. It is not when the update did not
+// come through a webhook with WithReplyTimeout, when the response was already
+// written after the timeout or an earlier Reply, or when params do not encode
+// as a JSON object. The handler must then call the method itself.
+func Reply(ctx context.Context, method string, params any) bool {
+	slot, ok := ctx.Value(replyKey{}).(*replySlot)
+	if !ok || method == "" {
+		return false
+	}
+
+	body, err := replyBody(method, params)
+	if err != nil {
+		return false
+	}
+
+	return slot.set(body)
+}
+
+// UpdateContext returns the context for handling an update received from
+// UpdateChan, carrying what Reply needs, and a function to call once the
+// update is handled. With WithReplyTimeout, the webhook acknowledges the
+// update when that function is called, unless a reply was set before.
+func (h *Webhook) UpdateContext(ctx context.Context, updateID int) (context.Context, func()) {
+	slot := h.replies.get(updateID)
+	if slot == nil {
+		return ctx, func() {}
+	}
+
+	return context.WithValue(ctx, replyKey{}, slot), slot.finish
+}
+
+type replyKey struct{}
+
+func replyBody(method string, params any) ([]byte, error) {
+	fields := make(map[string]json.RawMessage)
+
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+	}
+
+	name, err := json.Marshal(method)
+	if err != nil {
+		return nil, err
+	}
+
+	fields["method"] = name
+
+	return json.Marshal(fields)
+}
+
+// replySlot holds the reply to one update until the webhook writes its
+// response.
+type replySlot struct {
+	mu     sync.Mutex
+	body   []byte
+	closed bool
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newReplySlot() *replySlot {
+	return &replySlot{done: make(chan struct{})}
+}
+
+func (s *replySlot) set(body []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || s.body != nil {
+		return false
+	}
+
+	s.body = body
+	s.finish()
+
+	return true
+}
+
+// finish wakes the webhook waiting for the update to be handled.
+func (s *replySlot) finish() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// close stops taking replies and returns the one set, if any.
+func (s *replySlot) close() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+
+	return s.body
+}
+
+// replyRegistry holds the slots of updates whose requests wait for a reply.
+type replyRegistry struct {
+	mu    sync.Mutex
+	slots map[int]*replySlot
+}
+
+func newReplyRegistry() *replyRegistry {
+	return &replyRegistry{slots: make(map[int]*replySlot)}
+}
+
+func (r *replyRegistry) open(updateID int) *replySlot {
+	slot := newReplySlot()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.slots[updateID] = slot
+
+	return slot
+}
+
+func (r *replyRegistry) get(updateID int) *replySlot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.slots[updateID]
+}
+
+// remove forgets slot, unless a redelivery of the update replaced it.
+func (r *replyRegistry) remove(updateID int, slot *replySlot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.slots[updateID] == slot {
+		delete(r.slots, updateID)
+	}
+}
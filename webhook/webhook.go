@@ -1,16 +1,20 @@
 package webhook
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/metalagman/appkit/lifecycle"
 	"github.com/tgbotkit/client"
+	"github.com/tgbotkit/runtime/botapi"
 	"github.com/tgbotkit/runtime/botcontext"
+	"github.com/tgbotkit/runtime/internal/rawupdate"
 )
 
 const (
@@ -22,6 +26,8 @@ const (
 type Webhook struct {
 	opts    Options
 	updates chan client.Update
+	raw     *rawupdate.Store
+	replies *replyRegistry
 }
 
 var _ http.Handler = (*Webhook)(nil)
@@ -36,6 +42,8 @@ func New(opts Options) (*Webhook, error) {
 	return &Webhook{
 		opts:    opts,
 		updates: make(chan client.Update, opts.bufferSize),
+		raw:     rawupdate.NewStore(opts.bufferSize),
+		replies: newReplyRegistry(),
 	}, nil
 }
 
@@ -44,6 +52,12 @@ func (h *Webhook) UpdateChan() <-chan client.Update {
 	return h.updates
 }
 
+// RawUpdate returns the raw JSON of an update delivered through UpdateChan and forgets it.
+// Raw JSON is kept for a bounded number of recent updates only.
+func (h *Webhook) RawUpdate(updateID int) (json.RawMessage, bool) {
+	return h.raw.Take(updateID)
+}
+
 // Start satisfies the lifecycle.Lifecycle interface. The context is used only for the startup timeout.
 func (h *Webhook) Start(ctx context.Context) error {
 	return h.SetWebhook(ctx)
@@ -69,6 +83,12 @@ func (h *Webhook) SetWebhook(ctx context.Context) error {
 		return fmt.Errorf("set webhook: %w", err)
 	}
 
+	if resp.JSON200 == nil {
+		if apiErr := botapi.FromResponse(resp.StatusCode(), resp.Body); apiErr != nil {
+			return fmt.Errorf("set webhook: %w", apiErr)
+		}
+	}
+
 	if resp.JSON200 == nil || !bool(resp.JSON200.Ok) {
 		return fmt.Errorf("set webhook: unexpected response: %s", resp.Status())
 	}
@@ -78,6 +98,7 @@ func (h *Webhook) SetWebhook(ctx context.Context) error {
 
 // ServeHTTP implements http.Handler interface.
 // It validates the request, decodes the update, and sends it to the updates channel.
+// With WithReplyTimeout, it then waits for a Reply to write as the response body.
 func (h *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if !h.authorize(w, r) {
 		return
@@ -89,12 +110,27 @@ func (h *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	update, ok := h.decodeUpdate(w, r)
+	update, raw, ok := h.decodeUpdate(w, r)
 	if !ok {
 		return
 	}
 
-	h.enqueueUpdate(w, r, update)
+	if h.opts.replyTimeout <= 0 {
+		w.WriteHeader(h.enqueueUpdate(r, update, raw))
+
+		return
+	}
+
+	slot := h.replies.open(update.UpdateId)
+	defer h.replies.remove(update.UpdateId, slot)
+
+	if status := h.enqueueUpdate(r, update, raw); status != http.StatusOK {
+		w.WriteHeader(status)
+
+		return
+	}
+
+	h.writeReply(w, r, slot)
 }
 
 func (h *Webhook) registrationEnabled() bool {
@@ -151,25 +187,32 @@ func (h *Webhook) authorize(w http.ResponseWriter, r *http.Request) bool {
 	return false
 }
 
-func (h *Webhook) decodeUpdate(w http.ResponseWriter, r *http.Request) (client.Update, bool) {
+func (h *Webhook) decodeUpdate(w http.ResponseWriter, r *http.Request) (client.Update, json.RawMessage, bool) {
 	var update client.Update
 
 	r.Body = http.MaxBytesReader(w, r.Body, h.opts.maxBodyBytes)
 
-	decoder := json.NewDecoder(r.Body)
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeDecodeError(w, err)
+
+		return client.Update{}, nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
 	if err := decoder.Decode(&update); err != nil {
 		writeDecodeError(w, err)
 
-		return client.Update{}, false
+		return client.Update{}, nil, false
 	}
 
 	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
 		w.WriteHeader(http.StatusBadRequest)
 
-		return client.Update{}, false
+		return client.Update{}, nil, false
 	}
 
-	return update, true
+	return update, bytes.TrimSpace(data), true
 }
 
 func writeDecodeError(w http.ResponseWriter, err error) {
@@ -183,17 +226,48 @@ func writeDecodeError(w http.ResponseWriter, err error) {
 	w.WriteHeader(http.StatusBadRequest)
 }
 
-func (h *Webhook) enqueueUpdate(w http.ResponseWriter, r *http.Request, update client.Update) {
+func (h *Webhook) enqueueUpdate(r *http.Request, update client.Update, raw json.RawMessage) int {
 	if bc := botcontext.FromContext(r.Context()); bc != nil {
 		bc.Logger().Debugf("got update: %v", update.UpdateId)
 	}
 
+	// Raw JSON must be available before the consumer can receive the update.
+	h.raw.Put(update.UpdateId, raw)
+
 	select {
 	case h.updates <- update:
-		w.WriteHeader(http.StatusOK)
+		return http.StatusOK
 	case <-r.Context().Done():
-		w.WriteHeader(http.StatusRequestTimeout)
+		h.raw.Take(update.UpdateId)
+
+		return http.StatusRequestTimeout
 	default:
-		w.WriteHeader(http.StatusServiceUnavailable)
+		h.raw.Take(update.UpdateId)
+
+		return http.StatusServiceUnavailable
 	}
 }
+
+// writeReply waits until the update is handled, a handler sets a reply or
+// the reply timeout ends, and writes the reply or a bare acknowledgement.
+func (h *Webhook) writeReply(w http.ResponseWriter, r *http.Request, slot *replySlot) {
+	timer := time.NewTimer(h.opts.replyTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-slot.done:
+	case <-timer.C:
+	case <-r.Context().Done():
+	}
+
+	body := slot.close()
+	if body == nil {
+		w.WriteHeader(http.StatusOK)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
@@ -85,6 +85,34 @@ func TestWebhook_ServeHTTP_Success(t *testing.T) {
 	}
 }
 
+func TestWebhook_ServeHTTP_KeepsRawUpdate(t *testing.T) {
+	wh, err := webhook.New(webhook.NewOptions())
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	body := `{"update_id":321,"future_field":{"id":1}}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body+"\n"))
+	rr := httptest.NewRecorder()
+
+	wh.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("response code=%d, want %d", rr.Code, http.StatusOK)
+	}
+
+	raw, ok := wh.RawUpdate(321)
+	if !ok {
+		t.Fatal("RawUpdate() ok=false, want true")
+	}
+	if string(raw) != body {
+		t.Fatalf("raw=%s, want %s", raw, body)
+	}
+	if _, ok := wh.RawUpdate(321); ok {
+		t.Fatal("RawUpdate() second call ok=true, want false")
+	}
+}
+
 func TestWebhook_ServeHTTP_SecretToken(t *testing.T) {
 	token := "my-secret-token"
 	opts := webhook.NewOptions(webhook.WithToken(token))
@@ -374,3 +402,96 @@ func TestWebhook_SetWebhookRegistrationMode(t *testing.T) {
 		}
 	})
 }
+
+func serveUpdate(t *testing.T, wh *webhook.Webhook, updateID int) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, err := json.Marshal(client.Update{UpdateId: updateID})
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	wh.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+
+	return rr
+}
+
+func TestWebhook_ServeHTTP_WritesReply(t *testing.T) {
+	wh, err := webhook.New(webhook.NewOptions(webhook.WithReplyTimeout(time.Second)))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	replied := make(chan [2]bool, 1)
+	go func() {
+		update := <-wh.UpdateChan()
+		ctx, done := wh.UpdateContext(context.Background(), update.UpdateId)
+		defer done()
+
+		first := webhook.Reply(ctx, "sendMessage", client.SendMessageJSONRequestBody{ChatId: 42, Text: "pong"})
+		second := webhook.Reply(ctx, "sendMessage", client.SendMessageJSONRequestBody{ChatId: 42, Text: "again"})
+		replied <- [2]bool{first, second}
+	}()
+
+	rr := serveUpdate(t, wh, 1)
+
+	if got := <-replied; got != [2]bool{true, false} {
+		t.Fatalf("Reply() results=%v, want first taken only", got)
+	}
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("response code=%d content type=%q, want JSON 200", rr.Code, rr.Header().Get("Content-Type"))
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+	if got["method"] != "sendMessage" || got["chat_id"] != float64(42) || got["text"] != "pong" {
+		t.Fatalf("reply=%v, want sendMessage to 42", got)
+	}
+}
+
+func TestWebhook_ServeHTTP_AcksWithoutReply(t *testing.T) {
+	wh, err := webhook.New(webhook.NewOptions(webhook.WithReplyTimeout(time.Minute)))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	go func() {
+		update := <-wh.UpdateChan()
+		_, done := wh.UpdateContext(context.Background(), update.UpdateId)
+		done()
+	}()
+
+	rr := serveUpdate(t, wh, 2)
+	if rr.Code != http.StatusOK || rr.Body.Len() != 0 {
+		t.Fatalf("response code=%d body=%q, want bare 200", rr.Code, rr.Body.String())
+	}
+}
+
+func TestWebhook_ServeHTTP_AcksAfterReplyTimeout(t *testing.T) {
+	wh, err := webhook.New(webhook.NewOptions(webhook.WithReplyTimeout(20 * time.Millisecond)))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	contexts := make(chan context.Context, 1)
+	go func() {
+		update := <-wh.UpdateChan()
+		ctx, _ := wh.UpdateContext(context.Background(), update.UpdateId)
+		contexts <- ctx
+	}()
+
+	rr := serveUpdate(t, wh, 3)
+	if rr.Code != http.StatusOK || rr.Body.Len() != 0 {
+		t.Fatalf("response code=%d body=%q, want bare 200", rr.Code, rr.Body.String())
+	}
+
+	if webhook.Reply(<-contexts, "sendMessage", client.SendMessageJSONRequestBody{ChatId: 1, Text: "late"}) {
+		t.Fatal("Reply() after the timeout was taken")
+	}
+	if webhook.Reply(context.Background(), "sendMessage", nil) {
+		t.Fatal("Reply() outside a webhook request was taken")
+	}
+}